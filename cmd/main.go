@@ -49,13 +49,14 @@ func main() {
 	}
 	logger := zapLogger.NewLogger(cfg.Logging.Level)
 	r := gin.Default()
-	close := di.ConfigureApp(r, logger)
-	defer close()
+	close := di.ConfigureApp(r, cfg, logger)
 	serverREST := listenRESTServer(r, logger, cfg.Server)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("Shutting down server...")
+	// фоновые задачи и SSE потоки останавливаются до Shutdown, иначе он ждал бы открытые потоки до таймаута
+	close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
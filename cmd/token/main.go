@@ -0,0 +1,41 @@
+// Утилита для выпуска HS256 токенов при локальной разработке:
+//
+//	go run ./cmd/token -sub u5
+//	go run ./cmd/token -sub ops -roles admin -ttl 1h
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/auth"
+)
+
+func main() {
+	sub := flag.String("sub", "", "id пользователя (claim sub)")
+	roles := flag.String("roles", "user", "роли через запятую: user, admin")
+	ttl := flag.Duration("ttl", 24*time.Hour, "время жизни токена")
+	secret := flag.String("secret", os.Getenv("JWT_SECRET"), "секрет HS256, по умолчанию JWT_SECRET")
+	issuer := flag.String("iss", "", "claim iss")
+	audience := flag.String("aud", "", "claim aud")
+	flag.Parse()
+
+	if *sub == "" || *secret == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	claims := auth.NewClaims(*sub, strings.Split(*roles, ","), *ttl)
+	claims.Issuer = *issuer
+	if *audience != "" {
+		claims.Audience = auth.Audience{*audience}
+	}
+	token, err := auth.SignHS256([]byte(*secret), claims)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+}
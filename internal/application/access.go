@@ -0,0 +1,22 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+)
+
+var (
+	ErrUnauthenticated = errors.New("no caller in request context")
+	ErrForbidden       = errors.New("caller is not allowed to perform this action")
+)
+
+// caller возвращает вызывающего из контекста запроса
+func caller(ctx context.Context) (entityAuth.Principal, error) {
+	p, ok := entityAuth.FromContext(ctx)
+	if !ok {
+		return entityAuth.Principal{}, ErrUnauthenticated
+	}
+	return p, nil
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+)
+
+var (
+	ErrInvalidAPIKeyName = errors.New("api key name must not be empty")
+	ErrInvalidScopes     = errors.New("api key needs at least one known scope")
+	ErrInvalidExpiry     = errors.New("api key expiry must be in the future")
+	ErrAPIKeyNotFound    = errors.New("api key not found or already revoked")
+)
+
+// IssueAPIKey выпускает ключ для бота или CI. Ключ возвращается один раз, в БД хранится только его хеш
+func (s *PrService) IssueAPIKey(ctx context.Context, req dto.IssueAPIKeyRequest) (*entityAuth.APIKey, string, error) {
+	p, err := caller(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", ErrInvalidAPIKeyName
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, "", ErrInvalidExpiry
+	}
+	plain, err := entityAuth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := entityAuth.APIKey{
+		Name:      name,
+		Prefix:    entityAuth.VisiblePrefix(plain),
+		Hash:      entityAuth.HashAPIKey(plain),
+		Scopes:    scopes,
+		CreatedBy: p.Subject,
+		CreatedAt: now,
+		ExpiresAt: req.ExpiresAt,
+	}
+	key.Id, err = s.repo.AddAPIKey(ctx, key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to save api key: %w", err)
+	}
+	return &key, plain, nil
+}
+
+func (s *PrService) GetAPIKeys(ctx context.Context) ([]entityAuth.APIKey, error) {
+	return s.repo.GetAPIKeys(ctx)
+}
+
+func (s *PrService) RevokeAPIKey(ctx context.Context, id int) error {
+	if err := s.repo.RevokeAPIKey(ctx, id, time.Now().UTC()); err != nil {
+		if errors.Is(err, repos.ErrAPIKeyNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// normalizeScopes проверяет права ключа и убирает повторы
+func normalizeScopes(raw []string) ([]entityAuth.Permission, error) {
+	res := make([]entityAuth.Permission, 0, len(raw))
+	seen := make(map[entityAuth.Permission]bool, len(raw))
+	for _, r := range raw {
+		perm := entityAuth.Permission(strings.ToLower(strings.TrimSpace(r)))
+		if !entityAuth.IsValidScope(perm) {
+			return nil, ErrInvalidScopes
+		}
+		if !seen[perm] {
+			seen[perm] = true
+			res = append(res, perm)
+		}
+	}
+	if len(res) == 0 {
+		return nil, ErrInvalidScopes
+	}
+	return res, nil
+}
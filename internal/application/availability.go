@@ -0,0 +1,200 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidAvailability  = errors.New("availability window must end after it starts and in the future")
+	ErrAvailabilityNotFound = errors.New("availability window not found")
+)
+
+// AddAvailability сохраняет окно недоступности пользователя, если окно уже началось,
+// то его PR переназначаются сразу, не дожидаясь фоновой задачи
+func (s *PrService) AddAvailability(ctx context.Context, req dto.AvailabilityRequest) (*entityUser.Availability, error) {
+	user, err := s.repo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if !req.To.After(req.From) || !req.To.After(now) {
+		return nil, ErrInvalidAvailability
+	}
+	w := entityUser.Availability{
+		UserID: user.Id,
+		From:   req.From,
+		To:     req.To,
+		Reason: req.Reason,
+	}
+	if w.Id, err = s.repo.AddAvailability(ctx, w); err != nil {
+		return nil, fmt.Errorf("failed to add availability window: %w", err)
+	}
+	if w.Covers(now) {
+		if err := s.startAvailability(ctx, w); err != nil {
+			return nil, err
+		}
+	}
+	return &w, nil
+}
+
+func (s *PrService) GetAvailability(ctx context.Context, userID string) ([]entityUser.Availability, error) {
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	windows, err := s.repo.GetAvailability(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability: %w", err)
+	}
+	return windows, nil
+}
+
+// DeleteAvailability удаляет окно, если пользователь был внутри него,
+// то ему сразу предлагаются PR команды, которым не хватает ревьюеров
+func (s *PrService) DeleteAvailability(ctx context.Context, userID string, id int) error {
+	windows, err := s.GetAvailability(ctx, userID)
+	if err != nil {
+		return err
+	}
+	var deleted *entityUser.Availability
+	for i := range windows {
+		if windows[i].Id == id {
+			deleted = &windows[i]
+			break
+		}
+	}
+	if err := s.repo.DeleteAvailability(ctx, userID, id); err != nil {
+		if errors.Is(err, repos.ErrAvailabilityNotFound) {
+			return ErrAvailabilityNotFound
+		}
+		return fmt.Errorf("failed to delete availability window: %w", err)
+	}
+	now := time.Now()
+	if deleted == nil || !deleted.Covers(now) {
+		return nil
+	}
+	return s.endAvailability(ctx, *deleted, now)
+}
+
+// ApplyAvailability обрабатывает начавшиеся и закончившиеся окна недоступности:
+// при начале окна PR пользователя переназначаются так же, как при деактивации,
+// а после окончания пользователю предлагаются PR с need_more_reviewers.
+// Ошибка одного окна не останавливает проход: оно повторится на следующем, а остальные применяются сейчас
+func (s *PrService) ApplyAvailability(ctx context.Context, now time.Time) error {
+	var errs []error
+	starting, err := s.repo.GetStartingAvailability(ctx, now)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get starting availability windows: %w", err))
+	}
+	for _, w := range starting {
+		if err := s.startAvailability(ctx, w); err != nil {
+			errs = append(errs, fmt.Errorf("availability window %d of user %s: %w", w.Id, w.UserID, err))
+		}
+	}
+	ending, err := s.repo.GetEndingAvailability(ctx, now)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get ending availability windows: %w", err))
+	}
+	for _, w := range ending {
+		if err := s.endAvailability(ctx, w, now); err != nil {
+			errs = append(errs, fmt.Errorf("availability window %d of user %s: %w", w.Id, w.UserID, err))
+			continue
+		}
+		if err := s.repo.MarkAvailabilityEnded(ctx, w.Id); err != nil {
+			errs = append(errs, fmt.Errorf("availability window %d of user %s: %w", w.Id, w.UserID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *PrService) startAvailability(ctx context.Context, w entityUser.Availability) error {
+	user, err := s.repo.GetUserByID(ctx, w.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user %s: %w", w.UserID, err)
+	}
+	if user.IsActive {
+		if err := s.moveReviewsAway(ctx, *user); err != nil {
+			return fmt.Errorf("failed to move reviews of unavailable user %s: %w", user.Id, err)
+		}
+	}
+	return s.repo.MarkAvailabilityStarted(ctx, w.Id)
+}
+
+func (s *PrService) endAvailability(ctx context.Context, w entityUser.Availability, now time.Time) error {
+	user, err := s.repo.GetUserByID(ctx, w.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user %s: %w", w.UserID, err)
+	}
+	if !user.IsActive {
+		return nil
+	}
+	windows, err := s.repo.GetAvailability(ctx, user.Id)
+	if err != nil {
+		return fmt.Errorf("failed to get availability: %w", err)
+	}
+	for _, other := range windows {
+		if other.Id != w.Id && other.Covers(now) {
+			return nil
+		}
+	}
+	if err := s.offerNeedMorePrs(ctx, *user); err != nil {
+		return fmt.Errorf("failed to offer PRs to available user %s: %w", user.Id, err)
+	}
+	return nil
+}
+
+// AvailabilityJob периодически применяет окна недоступности пользователей. Проход выполняет одна реплика,
+// см. reviewerJobsLockID
+type AvailabilityJob struct {
+	repo     interfaces.PullRequestRepo
+	svc      interfaces.PrService
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewAvailabilityJob(repo interfaces.PullRequestRepo, svc interfaces.PrService, interval time.Duration, logger *zap.Logger) *AvailabilityJob {
+	return &AvailabilityJob{
+		repo:     repo,
+		svc:      svc,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (j *AvailabilityJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := j.ApplyDue(ctx, now); err != nil {
+				j.logger.Error("failed to apply availability windows", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ApplyDue выполняет один проход, если advisory lock не занят другой репликой
+func (j *AvailabilityJob) ApplyDue(ctx context.Context, now time.Time) error {
+	_, err := j.repo.RunExclusive(ctx, reviewerJobsLockID, func(ctx context.Context) error {
+		return j.svc.ApplyAvailability(ctx, now)
+	})
+	return err
+}
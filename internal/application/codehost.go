@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+)
+
+var (
+	ErrInvalidCodeHostUser   = errors.New("provider must be github or gitlab and login is required")
+	ErrCodeHostUserNotMapped = errors.New("code host user is not mapped to a service user")
+	ErrDuplicateDelivery     = errors.New("code host delivery is already processed")
+)
+
+// MapCodeHostUser связывает логин у провайдера с пользователем сервиса, старая связь логина заменяется
+func (s *PrService) MapCodeHostUser(ctx context.Context, req dto.CodeHostUserRequest) (*entityHost.UserMapping, error) {
+	m, err := codeHostUserFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetUserByID(ctx, req.UserID); err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	m.UserID = req.UserID
+	if err := s.repo.SetCodeHostUser(ctx, m); err != nil {
+		return nil, fmt.Errorf("failed to map code host user: %w", err)
+	}
+	return &m, nil
+}
+
+func (s *PrService) UnmapCodeHostUser(ctx context.Context, req dto.CodeHostUserRequest) error {
+	m, err := codeHostUserFromRequest(req)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteCodeHostUser(ctx, m.Provider, m.Login); err != nil {
+		if errors.Is(err, repos.ErrCodeHostUserNotFound) {
+			return ErrCodeHostUserNotMapped
+		}
+		return fmt.Errorf("failed to unmap code host user: %w", err)
+	}
+	return nil
+}
+
+func (s *PrService) GetCodeHostUsers(ctx context.Context, provider string) ([]entityHost.UserMapping, error) {
+	p := entityHost.Provider(strings.ToLower(provider))
+	if !p.IsValid() {
+		return nil, ErrInvalidCodeHostUser
+	}
+	return s.repo.GetCodeHostUsers(ctx, p)
+}
+
+func codeHostUserFromRequest(req dto.CodeHostUserRequest) (entityHost.UserMapping, error) {
+	p := entityHost.Provider(strings.ToLower(req.Provider))
+	login := strings.TrimSpace(req.Login)
+	if !p.IsValid() || login == "" {
+		return entityHost.UserMapping{}, ErrInvalidCodeHostUser
+	}
+	return entityHost.UserMapping{Provider: p, Login: login}, nil
+}
+
+// HandleCodeHostEvent повторяет в сервисе то, что произошло с PR у провайдера.
+// Каждая доставка обрабатывается один раз, при ошибке она забывается, чтобы провайдер мог её повторить
+func (s *PrService) HandleCodeHostEvent(ctx context.Context, e entityHost.PullRequestEvent) (*entityPR.PullRequest, error) {
+	claimed, err := s.repo.ClaimCodeHostDelivery(ctx, e.Provider, e.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrDuplicateDelivery
+	}
+	pr, err := s.applyCodeHostEvent(ctx, e)
+	if err != nil {
+		if releaseErr := s.repo.ReleaseCodeHostDelivery(ctx, e.Provider, e.DeliveryID); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+	return pr, nil
+}
+
+func (s *PrService) applyCodeHostEvent(ctx context.Context, e entityHost.PullRequestEvent) (*entityPR.PullRequest, error) {
+	// изменение уже произошло в репозитории, сервис его только повторяет, поэтому вызов идёт от имени
+	// провайдера с правами администратора: мерж без нужных одобрений будет отмечен review_bypassed
+	ctx = entityAuth.WithPrincipal(ctx, entityAuth.Principal{
+		Subject: string(e.Provider) + ":" + e.SenderLogin,
+		Roles:   []entityAuth.Role{entityAuth.RoleAdmin},
+	})
+	prID := e.PrID()
+	switch e.Action {
+	case entityHost.ActionOpened:
+		author, err := s.repo.GetCodeHostUser(ctx, e.Provider, e.AuthorLogin)
+		if err != nil {
+			if errors.Is(err, repos.ErrCodeHostUserNotFound) {
+				return nil, fmt.Errorf("%w: %s %s", ErrCodeHostUserNotMapped, e.Provider, e.AuthorLogin)
+			}
+			return nil, err
+		}
+		pr, err := s.CreatePR(ctx, dto.CreatePR{PrID: prID, PrName: e.Title, PrAuthor: author.UserID, Draft: e.Draft})
+		if errors.Is(err, ErrPrIsAlreadyCreated) {
+			pr, err = s.repo.GetPr(ctx, prID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// после связи ReviewerSyncJob запросит ревью выбранных ревьюеров у провайдера
+		if pr.CodeHost == nil {
+			link := entityHost.NewLink(e, time.Now().UTC())
+			if err := s.repo.AddCodeHostLink(ctx, link); err != nil {
+				return nil, err
+			}
+			pr.CodeHost = &link
+		}
+		return pr, nil
+	case entityHost.ActionMerged:
+		return s.Merge(ctx, prID)
+	case entityHost.ActionClosed:
+		return s.Close(ctx, prID)
+	case entityHost.ActionReopened:
+		return s.Reopen(ctx, dto.PullRequestLifecycleRequest{PrID: prID})
+	case entityHost.ActionReady:
+		return s.Ready(ctx, dto.PullRequestLifecycleRequest{PrID: prID})
+	case entityHost.ActionDraft:
+		return s.ToDraft(ctx, prID)
+	default:
+		return nil, entityHost.ErrIgnoredEvent
+	}
+}
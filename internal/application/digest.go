@@ -0,0 +1,121 @@
+package application
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"go.uber.org/zap"
+)
+
+// DigestJob раз в день отправляет пользователям с email сводку их открытых ревью.
+// Сводка за день отмечается в БД до отправки, поэтому после перезапуска или на нескольких репликах
+// она не дублируется, а если письмо не ушло, отметка снимается и отправка повторяется на следующем проходе
+type DigestJob struct {
+	repo     interfaces.PullRequestRepo
+	sender   interfaces.DigestSender
+	at       time.Duration
+	interval time.Duration
+	logger   *zap.Logger
+	// doneDay - день, за который сводки уже разосланы всем, до следующего дня БД не опрашивается
+	doneDay time.Time
+}
+
+// NewDigestJob создаёт задачу, at - время сводки от начала суток по UTC
+func NewDigestJob(repo interfaces.PullRequestRepo, sender interfaces.DigestSender, at, interval time.Duration, logger *zap.Logger) *DigestJob {
+	return &DigestJob{
+		repo:     repo,
+		sender:   sender,
+		at:       at,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (j *DigestJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := j.SendDue(ctx, now); err != nil {
+				j.logger.Error("failed to send review digests", zap.Error(err))
+			}
+		}
+	}
+}
+
+// SendDue отправляет сводки за день now, если время сводки наступило, и возвращает число отправленных писем.
+// Пользователи без открытых ревью письма не получают
+func (j *DigestJob) SendDue(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if now.Sub(day) < j.at || day.Equal(j.doneDay) {
+		return 0, nil
+	}
+	users, err := j.repo.GetUsersWithEmail(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sent, failed := 0, false
+	for _, user := range users {
+		claimed, err := j.repo.ClaimDigest(ctx, user.Id, day)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		prs, err := j.repo.GetUsersPr(ctx, user.Id, true)
+		if err == nil && len(prs) == 0 {
+			continue
+		}
+		if err == nil {
+			err = j.sender.SendDigest(ctx, newDigest(user, day, prs))
+		}
+		if err != nil {
+			failed = true
+			j.logger.Warn("failed to send review digest", zap.String("user_id", user.Id), zap.Error(err))
+			if err := j.repo.ReleaseDigest(ctx, user.Id, day); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		sent++
+	}
+	if !failed {
+		j.doneDay = day
+	}
+	return sent, nil
+}
+
+// newDigest собирает сводку из открытых PR пользователя, от старых к новым
+func newDigest(user entityUser.User, day time.Time, prs []entityPR.PullRequest) entityNotify.Digest {
+	slices.SortFunc(prs, func(a, b entityPR.PullRequest) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.Id, b.Id))
+	})
+	items := make([]entityNotify.DigestItem, 0, len(prs))
+	for _, pr := range prs {
+		items = append(items, entityNotify.DigestItem{
+			PrID:      pr.Id,
+			PrName:    pr.Name,
+			AuthorID:  pr.Author.Id,
+			CreatedAt: pr.CreatedAt,
+		})
+	}
+	return entityNotify.Digest{
+		UserID:       user.Id,
+		UserName:     user.Name,
+		Email:        user.Email,
+		Date:         day,
+		PullRequests: items,
+	}
+}
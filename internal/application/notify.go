@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+	"go.uber.org/zap"
+)
+
+// notificationQueueSize - сколько событий ждёт отправки уведомлений, при переполнении новые отбрасываются
+const notificationQueueSize = 1000
+
+// NotificationPublisher подписан на события outbox и превращает назначения, переназначения, мержи
+// и нехватку ревьюеров в уведомления. Publish только ставит событие в очередь, сами уведомления отправляет Run,
+// чтобы relay не держал транзакцию и блокировку outbox, пока отвечает чат или SMTP. Уведомления не важнее
+// самих событий: если notifier не ответил или очередь переполнена, это пишется в лог и не задерживает события PR
+type NotificationPublisher struct {
+	repo     interfaces.PullRequestRepo
+	notifier interfaces.Notifier
+	logger   *zap.Logger
+	queue    chan entityEvent.Event
+}
+
+func NewNotificationPublisher(repo interfaces.PullRequestRepo, notifier interfaces.Notifier, logger *zap.Logger) *NotificationPublisher {
+	return &NotificationPublisher{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+		queue:    make(chan entityEvent.Event, notificationQueueSize),
+	}
+}
+
+func (p *NotificationPublisher) Publish(_ context.Context, e entityEvent.Event) error {
+	if len(Notifications(e)) == 0 {
+		return nil
+	}
+	select {
+	case p.queue <- e:
+	default:
+		p.logger.Warn("notification queue is full, dropping event", zap.String("event_id", e.Id), zap.String("type", string(e.Type)))
+	}
+	return nil
+}
+
+// Run блокируется до отмены ctx
+func (p *NotificationPublisher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-p.queue:
+			p.send(ctx, e)
+		}
+	}
+}
+
+// SendQueued отправляет уведомления по всем событиям, уже стоящим в очереди
+func (p *NotificationPublisher) SendQueued(ctx context.Context) {
+	for {
+		select {
+		case e := <-p.queue:
+			p.send(ctx, e)
+		default:
+			return
+		}
+	}
+}
+
+func (p *NotificationPublisher) send(ctx context.Context, e entityEvent.Event) {
+	notifications := Notifications(e)
+	if len(notifications) == 0 {
+		return
+	}
+	teamName := ""
+	if team, err := p.repo.GetTeam(ctx, e.TeamID); err == nil && team != nil {
+		teamName = team.Name
+	}
+	for _, n := range notifications {
+		n.TeamName = teamName
+		emails, err := p.repo.GetUserEmails(ctx, n.Recipients)
+		if err != nil {
+			p.logger.Warn("failed to get recipient emails", zap.String("pr_id", n.PrID), zap.Error(err))
+		}
+		n.Emails = emails
+		if err := p.notifier.Notify(ctx, n); err != nil {
+			p.logger.Warn("failed to send notification",
+				zap.String("kind", string(n.Kind)),
+				zap.String("pr_id", n.PrID),
+				zap.String("event_id", e.Id),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// Notifications определяет, о чём сообщить по событию PR. Замена ревьюера через reassign или по SLA - переназначение,
+// остальные смены ревьюеров и создание PR - назначение новых ревьюеров, просрочка ревью - напоминание или эскалация
+func Notifications(e entityEvent.Event) []entityNotify.Notification {
+	data, ok := entityEvent.PrDataOf(e)
+	if !ok {
+		return nil
+	}
+	base := entityNotify.Notification{
+		TeamID:         e.TeamID,
+		PrID:           data.PullRequestID,
+		PrName:         data.PullRequestName,
+		AuthorID:       data.AuthorID,
+		Reviewers:      data.AssignedReviewers,
+		MergedBy:       data.MergedBy,
+		NeedMoreReason: data.NeedMoreReason,
+		Reason:         data.Reason,
+	}
+	var res []entityNotify.Notification
+	add := func(kind entityNotify.Kind, recipients, removed []string) {
+		n := base
+		n.Kind = kind
+		n.Recipients = recipients
+		n.Removed = removed
+		res = append(res, n)
+	}
+	switch e.Type {
+	case entityEvent.PrCreated:
+		if len(data.AssignedReviewers) > 0 {
+			add(entityNotify.KindAssigned, data.AssignedReviewers, nil)
+		}
+	case entityEvent.PrReviewersChanged:
+		switch {
+		case entityEvent.IsReplacement(data.Reason) && len(data.AddedReviewers) > 0:
+			add(entityNotify.KindReassigned, data.AddedReviewers, data.RemovedReviewers)
+		case len(data.AddedReviewers) > 0:
+			add(entityNotify.KindAssigned, data.AddedReviewers, nil)
+		}
+	case entityEvent.PrMerged:
+		add(entityNotify.KindMerged, append([]string{data.AuthorID}, data.AssignedReviewers...), nil)
+		return res
+	case entityEvent.PrReviewOverdue:
+		overdue, ok := entityEvent.OverdueDataOf(e)
+		if !ok {
+			return nil
+		}
+		base.ReviewerID, base.AssignedAt = overdue.ReviewerID, overdue.AssignedAt
+		if overdue.Stage == entityEvent.StageEscalation {
+			add(entityNotify.KindReviewEscalated, overdue.Leads, nil)
+		} else {
+			add(entityNotify.KindReviewReminder, []string{overdue.ReviewerID}, nil)
+		}
+		return res
+	default:
+		return nil
+	}
+	if data.NeedMoreReviewers && (e.Type == entityEvent.PrCreated || len(data.RemovedReviewers) > 0) {
+		add(entityNotify.KindNeedMoreReviewers, []string{data.AuthorID}, nil)
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	"go.uber.org/zap"
+)
+
+// outboxBatch - сколько событий relay публикует за один проход
+const outboxBatch = 100
+
+// OutboxRelay периодически публикует события из outbox. Событие отмечается отправленным
+// только после успешной публикации, поэтому доставка - хотя бы один раз
+type OutboxRelay struct {
+	repo      interfaces.PullRequestRepo
+	publisher interfaces.EventPublisher
+	interval  time.Duration
+	logger    *zap.Logger
+}
+
+func NewOutboxRelay(repo interfaces.PullRequestRepo, publisher interfaces.EventPublisher, interval time.Duration, logger *zap.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Relay(ctx); err != nil {
+				r.logger.Error("failed to relay outbox events", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Relay публикует накопившиеся события, пока проходы забирают полную пачку
+func (r *OutboxRelay) Relay(ctx context.Context) error {
+	for {
+		sent, err := r.repo.PublishOutbox(ctx, outboxBatch, r.publisher.Publish)
+		if err != nil {
+			return err
+		}
+		if sent < outboxBatch || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
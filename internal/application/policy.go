@@ -0,0 +1,422 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityOwners "github.com/JanArsMAI/PullRequestService/internal/domain/codeowners"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityPr "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+)
+
+// PolicyService проверяет, что у вызывающего есть право в команде, которой касается запрос,
+// и передаёт вызов дальше. Методы без проверки делегируются как есть
+type PolicyService struct {
+	interfaces.PrService
+	repo interfaces.PullRequestRepo
+}
+
+func NewPolicyService(next interfaces.PrService, repo interfaces.PullRequestRepo) interfaces.PrService {
+	return &PolicyService{PrService: next, repo: repo}
+}
+
+func (s *PolicyService) authorize(ctx context.Context, perm entityAuth.Permission, teamID int) error {
+	p, err := caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !p.Can(perm, teamID) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// Если объект не найден, команда считается нулевой: пройдут только глобальные права,
+// а ошибку "не найдено" вернёт сам сервис
+
+func (s *PolicyService) teamByName(ctx context.Context, name string) int {
+	team, err := s.repo.GetTeamByName(ctx, name)
+	if err != nil || team == nil {
+		return 0
+	}
+	return team.Id
+}
+
+func (s *PolicyService) userTeam(ctx context.Context, userID string) int {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil || user == nil {
+		return 0
+	}
+	return user.TeamID
+}
+
+func (s *PolicyService) prTeam(ctx context.Context, prID string) int {
+	pr, err := s.repo.GetPr(ctx, prID)
+	if err != nil || pr == nil {
+		return 0
+	}
+	return pr.Author.TeamID
+}
+
+// AddTeam переводит существующих пользователей из их команд и меняет их данные, поэтому право
+// в какой-то одной команде тут не подходит - создавать команды может только администратор
+func (s *PolicyService) AddTeam(ctx context.Context, teamDto *dto.AddTeamRequest) error {
+	p, err := caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !p.IsAdmin() {
+		return ErrForbidden
+	}
+	return s.PrService.AddTeam(ctx, teamDto)
+}
+
+func (s *PolicyService) UpdateTeamSettings(ctx context.Context, settings dto.TeamSettingsRequest) (*entityTeam.Team, error) {
+	if err := s.authorize(ctx, entityAuth.PermTeamManage, s.teamByName(ctx, settings.TeamName)); err != nil {
+		return nil, err
+	}
+	return s.PrService.UpdateTeamSettings(ctx, settings)
+}
+
+func (s *PolicyService) SetUserActive(ctx context.Context, userID string, isActive bool) error {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, s.userTeam(ctx, userID)); err != nil {
+		return err
+	}
+	return s.PrService.SetUserActive(ctx, userID, isActive)
+}
+
+func (s *PolicyService) SetMaxOpenReviews(ctx context.Context, userID string, maxOpen int) (*entityUser.User, error) {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, s.userTeam(ctx, userID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.SetMaxOpenReviews(ctx, userID, maxOpen)
+}
+
+func (s *PolicyService) SetUserEmail(ctx context.Context, userID string, email string) (*entityUser.User, error) {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, s.userTeam(ctx, userID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.SetUserEmail(ctx, userID, email)
+}
+
+func (s *PolicyService) SetUserTags(ctx context.Context, req dto.UserTagsRequest) (*entityUser.User, error) {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, s.userTeam(ctx, req.UserID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.SetUserTags(ctx, req)
+}
+
+func (s *PolicyService) AddAvailability(ctx context.Context, req dto.AvailabilityRequest) (*entityUser.Availability, error) {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, s.userTeam(ctx, req.UserID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.AddAvailability(ctx, req)
+}
+
+func (s *PolicyService) DeleteAvailability(ctx context.Context, userID string, id int) error {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, s.userTeam(ctx, userID)); err != nil {
+		return err
+	}
+	return s.PrService.DeleteAvailability(ctx, userID, id)
+}
+
+func (s *PolicyService) Deactivate(ctx context.Context, teamName string, userIDs []string) error {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, s.teamByName(ctx, teamName)); err != nil {
+		return err
+	}
+	return s.PrService.Deactivate(ctx, teamName, userIDs)
+}
+
+func (s *PolicyService) CreatePR(ctx context.Context, prDto dto.CreatePR) (*entityPr.PullRequest, error) {
+	if err := s.authorize(ctx, entityAuth.PermPrCreate, s.userTeam(ctx, prDto.PrAuthor)); err != nil {
+		return nil, err
+	}
+	return s.PrService.CreatePR(ctx, prDto)
+}
+
+func (s *PolicyService) Ready(ctx context.Context, req dto.PullRequestLifecycleRequest) (*entityPr.PullRequest, error) {
+	if err := s.authorize(ctx, entityAuth.PermPrManage, s.prTeam(ctx, req.PrID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.Ready(ctx, req)
+}
+
+func (s *PolicyService) Close(ctx context.Context, prID string) (*entityPr.PullRequest, error) {
+	if err := s.authorize(ctx, entityAuth.PermPrManage, s.prTeam(ctx, prID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.Close(ctx, prID)
+}
+
+func (s *PolicyService) ToDraft(ctx context.Context, prID string) (*entityPr.PullRequest, error) {
+	if err := s.authorize(ctx, entityAuth.PermPrManage, s.prTeam(ctx, prID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.ToDraft(ctx, prID)
+}
+
+func (s *PolicyService) Reopen(ctx context.Context, req dto.PullRequestLifecycleRequest) (*entityPr.PullRequest, error) {
+	if err := s.authorize(ctx, entityAuth.PermPrManage, s.prTeam(ctx, req.PrID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.Reopen(ctx, req)
+}
+
+// Reassign проверяется по команде, из пула которой назначен заменяемый ревьюер:
+// тимлид может перераспределять ревью своей команды и в чужих PR
+func (s *PolicyService) Reassign(ctx context.Context, prID, oldReviewerID, preferredID string) (*entityPr.PullRequest, string, error) {
+	teamID := 0
+	if pr, err := s.repo.GetPr(ctx, prID); err == nil && pr != nil {
+		if pool, ok := pr.PoolOf(oldReviewerID); ok {
+			teamID = pool
+		} else {
+			teamID = s.userTeam(ctx, oldReviewerID)
+		}
+	}
+	if err := s.authorize(ctx, entityAuth.PermPrReassign, teamID); err != nil {
+		return nil, "", err
+	}
+	return s.PrService.Reassign(ctx, prID, oldReviewerID, preferredID)
+}
+
+func (s *PolicyService) GetStatistics(ctx context.Context) (map[string]int, map[string]int, error) {
+	if err := s.authorize(ctx, entityAuth.PermStatsRead, 0); err != nil {
+		return nil, nil, err
+	}
+	return s.PrService.GetStatistics(ctx)
+}
+
+func (s *PolicyService) GrantRole(ctx context.Context, req dto.RoleRequest) ([]entityAuth.Grant, error) {
+	if err := s.authorize(ctx, entityAuth.PermRolesManage, 0); err != nil {
+		return nil, err
+	}
+	return s.PrService.GrantRole(ctx, req)
+}
+
+func (s *PolicyService) RevokeRole(ctx context.Context, req dto.RoleRequest) ([]entityAuth.Grant, error) {
+	if err := s.authorize(ctx, entityAuth.PermRolesManage, 0); err != nil {
+		return nil, err
+	}
+	return s.PrService.RevokeRole(ctx, req)
+}
+
+func (s *PolicyService) IssueAPIKey(ctx context.Context, req dto.IssueAPIKeyRequest) (*entityAuth.APIKey, string, error) {
+	if err := s.authorize(ctx, entityAuth.PermAPIKeysManage, 0); err != nil {
+		return nil, "", err
+	}
+	return s.PrService.IssueAPIKey(ctx, req)
+}
+
+func (s *PolicyService) GetAPIKeys(ctx context.Context) ([]entityAuth.APIKey, error) {
+	if err := s.authorize(ctx, entityAuth.PermAPIKeysManage, 0); err != nil {
+		return nil, err
+	}
+	return s.PrService.GetAPIKeys(ctx)
+}
+
+func (s *PolicyService) RevokeAPIKey(ctx context.Context, id int) error {
+	if err := s.authorize(ctx, entityAuth.PermAPIKeysManage, 0); err != nil {
+		return err
+	}
+	return s.PrService.RevokeAPIKey(ctx, id)
+}
+
+func (s *PolicyService) AddWebhook(ctx context.Context, req dto.WebhookRequest) (*entityHook.Subscription, error) {
+	if err := s.authorize(ctx, entityAuth.PermWebhooksManage, 0); err != nil {
+		return nil, err
+	}
+	return s.PrService.AddWebhook(ctx, req)
+}
+
+func (s *PolicyService) GetWebhooks(ctx context.Context) ([]entityHook.Subscription, error) {
+	if err := s.authorize(ctx, entityAuth.PermWebhooksManage, 0); err != nil {
+		return nil, err
+	}
+	return s.PrService.GetWebhooks(ctx)
+}
+
+func (s *PolicyService) DeleteWebhook(ctx context.Context, id int) error {
+	if err := s.authorize(ctx, entityAuth.PermWebhooksManage, 0); err != nil {
+		return err
+	}
+	return s.PrService.DeleteWebhook(ctx, id)
+}
+
+func (s *PolicyService) GetDeliveries(ctx context.Context, status string) ([]entityHook.Delivery, error) {
+	if err := s.authorize(ctx, entityAuth.PermWebhooksManage, 0); err != nil {
+		return nil, err
+	}
+	return s.PrService.GetDeliveries(ctx, status)
+}
+
+func (s *PolicyService) Redeliver(ctx context.Context, id int) (*entityHook.Delivery, error) {
+	if err := s.authorize(ctx, entityAuth.PermWebhooksManage, 0); err != nil {
+		return nil, err
+	}
+	return s.PrService.Redeliver(ctx, id)
+}
+
+func (s *PolicyService) UploadCodeOwners(ctx context.Context, req dto.UploadCodeOwnersRequest) ([]entityOwners.Rule, error) {
+	if err := s.authorize(ctx, entityAuth.PermCodeOwnersManage, s.teamByName(ctx, req.TeamName)); err != nil {
+		return nil, err
+	}
+	return s.PrService.UploadCodeOwners(ctx, req)
+}
+
+func (s *PolicyService) TestCodeOwners(ctx context.Context, req dto.TestCodeOwnersRequest) (map[string][]entityOwners.Owner, error) {
+	if err := s.authorize(ctx, entityAuth.PermCodeOwnersManage, s.teamByName(ctx, req.TeamName)); err != nil {
+		return nil, err
+	}
+	return s.PrService.TestCodeOwners(ctx, req)
+}
+
+func (s *PolicyService) MapCodeHostUser(ctx context.Context, req dto.CodeHostUserRequest) (*entityHost.UserMapping, error) {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, s.userTeam(ctx, req.UserID)); err != nil {
+		return nil, err
+	}
+	return s.PrService.MapCodeHostUser(ctx, req)
+}
+
+// UnmapCodeHostUser - тимлид может удалить только связь с участником своей команды
+func (s *PolicyService) UnmapCodeHostUser(ctx context.Context, req dto.CodeHostUserRequest) error {
+	teamID := 0
+	if m, err := s.repo.GetCodeHostUser(ctx, entityHost.Provider(strings.ToLower(req.Provider)), strings.TrimSpace(req.Login)); err == nil {
+		teamID = s.userTeam(ctx, m.UserID)
+	}
+	if err := s.authorize(ctx, entityAuth.PermUserManage, teamID); err != nil {
+		return err
+	}
+	return s.PrService.UnmapCodeHostUser(ctx, req)
+}
+
+func (s *PolicyService) GetCodeHostUsers(ctx context.Context, provider string) ([]entityHost.UserMapping, error) {
+	if err := s.authorize(ctx, entityAuth.PermUserManage, 0); err != nil {
+		return nil, err
+	}
+	return s.PrService.GetCodeHostUsers(ctx, provider)
+}
+
+// teamVisibility - чьи данные видит вызывающий: all - всех команд (администратор и API ключ с team:read),
+// иначе команд, где он тимлид, и своей команды
+type teamVisibility struct {
+	principal entityAuth.Principal
+	all       bool
+	teams     []int
+}
+
+func (v teamVisibility) canSee(teamID int) bool {
+	if v.all {
+		return true
+	}
+	for _, id := range v.teams {
+		if id != 0 && id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PolicyService) visibility(ctx context.Context) (teamVisibility, error) {
+	p, err := caller(ctx)
+	if err != nil {
+		return teamVisibility{}, err
+	}
+	v := teamVisibility{principal: p, all: p.IsAdmin() || p.HasScope(entityAuth.PermTeamRead)}
+	if v.all {
+		return v, nil
+	}
+	v.teams = append([]int{}, p.LeadOf...)
+	if p.UserID != "" {
+		if own := s.userTeam(ctx, p.UserID); own != 0 && !p.IsLeadOf(own) {
+			v.teams = append(v.teams, own)
+		}
+	}
+	return v, nil
+}
+
+// GetTeam - состав команды виден её участникам, тимлиду, администратору и API ключам с team:read
+func (s *PolicyService) GetTeam(ctx context.Context, teamName string) (*entityTeam.Team, error) {
+	v, err := s.visibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.PrService.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if !v.canSee(team.Id) {
+		return nil, ErrForbidden
+	}
+	return team, nil
+}
+
+// GetSLABreaches - видимость как у GetTeam, без teamName тимлид и участник получают просрочки своих команд
+func (s *PolicyService) GetSLABreaches(ctx context.Context, teamName string) ([]entityPr.SLABreach, error) {
+	v, err := s.visibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if teamName != "" {
+		if !v.canSee(s.teamByName(ctx, teamName)) {
+			return nil, ErrForbidden
+		}
+		return s.PrService.GetSLABreaches(ctx, teamName)
+	}
+	if v.all {
+		return s.PrService.GetSLABreaches(ctx, "")
+	}
+	if len(v.teams) == 0 {
+		return nil, ErrForbidden
+	}
+	breaches, err := s.PrService.GetSLABreaches(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]entityPr.SLABreach, 0, len(breaches))
+	for _, b := range breaches {
+		if v.canSee(b.TeamID) {
+			visible = append(visible, b)
+		}
+	}
+	return visible, nil
+}
+
+// EventFilter - видимость как у GetTeam. События пользователя видит он сам и те, кто видит его команду,
+// без team_name и user_id поток ограничивается видимыми командами
+func (s *PolicyService) EventFilter(ctx context.Context, req dto.EventStreamRequest) (*entityEvent.Filter, error) {
+	v, err := s.visibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.PrService.EventFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if f.UserID != "" && !v.principal.IsUser(f.UserID) && !v.canSee(s.userTeam(ctx, f.UserID)) {
+		return nil, ErrForbidden
+	}
+	if f.TeamIDs != nil {
+		for _, id := range f.TeamIDs {
+			if !v.canSee(id) {
+				return nil, ErrForbidden
+			}
+		}
+		return f, nil
+	}
+	// события пользователя уже проверены выше, команды ограничивать не нужно
+	if f.UserID != "" || v.all {
+		return f, nil
+	}
+	if len(v.teams) == 0 {
+		return nil, ErrForbidden
+	}
+	f.TeamIDs = v.teams
+	return f, nil
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityOwners "github.com/JanArsMAI/PullRequestService/internal/domain/codeowners"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
 	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
 	entity "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
 	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
@@ -28,16 +32,116 @@ var (
 	ErrPrIsMerged                 = errors.New("PR is already merged")
 	ErrNoCandidate                = errors.New("no candidate to reassign")
 	ErrNotAssigned                = errors.New("no user with this id assigned to PR")
+	ErrInvalidReviewerStrategy    = errors.New("unknown reviewer strategy")
+	ErrInvalidRequiredReviewers   = errors.New("required reviewers must be positive")
+	ErrInvalidExtraTeams          = errors.New("invalid extra teams for PR")
+	ErrInvalidCandidate           = errors.New("candidate cannot replace this reviewer")
+	ErrInvalidCodeOwners          = errors.New("invalid code owners rules")
+	ErrUnknownOwner               = errors.New("code owner does not exist")
+	ErrInvalidTags                = errors.New("invalid tags")
+	ErrInvalidMaxOpenReviews      = errors.New("max open reviews must not be negative")
+	ErrInvalidEmail               = errors.New("invalid email address")
+	ErrInvalidTransition          = entityPR.ErrInvalidTransition
+	ErrPrNotOpen                  = errors.New("PR is not open")
+	ErrInvalidRequiredApprovals   = errors.New("required approvals must be positive")
 )
 
 type PrService struct {
-	repo interfaces.PullRequestRepo
+	repo      interfaces.PullRequestRepo
+	selectors map[string]ReviewerSelector
 }
 
-func NewPrService(repo interfaces.PullRequestRepo) interfaces.PrService {
-	return &PrService{
-		repo: repo,
+type Option func(*PrService)
+
+// WithSelector подменяет реализацию стратегии выбора ревьюеров (например, в тестах)
+func WithSelector(strategy string, selector ReviewerSelector) Option {
+	return func(s *PrService) {
+		s.selectors[strategy] = selector
+	}
+}
+
+func NewPrService(repo interfaces.PullRequestRepo, opts ...Option) interfaces.PrService {
+	s := &PrService{
+		repo:      repo,
+		selectors: defaultSelectors(repo),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *PrService) selectorFor(team *entityTeam.Team) ReviewerSelector {
+	if sel, ok := s.selectors[team.ReviewerStrategy]; ok {
+		return sel
+	}
+	return s.selectors[entityTeam.StrategyRandom]
+}
+
+// pick выбирает count ревьюеров стратегией команды. Если у PR есть требуемые навыки,
+// то сначала выбираются кандидаты с наибольшим числом совпадающих навыков, затем остальные
+func (s *PrService) pick(ctx context.Context, team *entityTeam.Team, candidates []entityUser.User, count int, tags []string) ([]entityUser.User, error) {
+	selector := s.selectorFor(team)
+	if len(tags) == 0 || len(candidates) == 0 || count <= 0 {
+		return selector.Select(ctx, *team, candidates, count)
+	}
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.Id)
+	}
+	userTags, err := s.repo.GetUserTags(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user tags: %w", err)
+	}
+	tiers := make([][]entityUser.User, len(tags)+1)
+	for _, c := range candidates {
+		overlap := tagOverlap(userTags[c.Id], tags)
+		tiers[overlap] = append(tiers[overlap], c)
+	}
+	selected := make([]entityUser.User, 0, count)
+	for overlap := len(tags); overlap >= 0 && len(selected) < count; overlap-- {
+		if len(tiers[overlap]) == 0 {
+			continue
+		}
+		picked, err := selector.Select(ctx, *team, tiers[overlap], count-len(selected))
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, picked...)
+	}
+	return selected, nil
+}
+
+func tagOverlap(userTags, required []string) int {
+	count := 0
+	for _, t := range required {
+		for _, u := range userTags {
+			if t == u {
+				count++
+				break
+			}
+		}
 	}
+	return count
+}
+
+// normalizeTags приводит навыки к нижнему регистру, убирает дубли и сортирует
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(tags))
+	res := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || len(t) > 50 || strings.ContainsAny(t, " \t\n") {
+			return nil, ErrInvalidTags
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		res = append(res, t)
+	}
+	sort.Strings(res)
+	return res, nil
 }
 
 func (s *PrService) AddTeam(ctx context.Context, teamDto *dto.AddTeamRequest) error {
@@ -45,6 +149,22 @@ func (s *PrService) AddTeam(ctx context.Context, teamDto *dto.AddTeamRequest) er
 	if team != nil {
 		return ErrTeamWithNameAlreadyCreated
 	}
+	strategy := teamDto.ReviewerStrategy
+	if strategy == "" {
+		strategy = entityTeam.StrategyRandom
+	}
+	if !entityTeam.IsValidStrategy(strategy) {
+		return ErrInvalidReviewerStrategy
+	}
+	if teamDto.RequiredReviewers < 0 {
+		return ErrInvalidRequiredReviewers
+	}
+
+	for _, userDto := range teamDto.Members {
+		if userDto.Email != "" && !entityUser.IsValidEmail(userDto.Email) {
+			return ErrInvalidEmail
+		}
+	}
 
 	users := make([]entityUser.User, 0, len(teamDto.Members))
 	errChan := make(chan error, len(teamDto.Members))
@@ -58,11 +178,13 @@ func (s *PrService) AddTeam(ctx context.Context, teamDto *dto.AddTeamRequest) er
 					Id:       userDto.Id,
 					Name:     userDto.Name,
 					IsActive: userDto.IsActive,
+					Email:    userDto.Email,
 				})
 				continue
 			}
 			return fmt.Errorf("failed to get user: %w", err)
 		}
+		// email принимается только для новых пользователей, адрес существующего меняется через users/setEmail
 		user.Name = userDto.Name
 		user.IsActive = userDto.IsActive
 
@@ -71,13 +193,15 @@ func (s *PrService) AddTeam(ctx context.Context, teamDto *dto.AddTeamRequest) er
 			return fmt.Errorf("failed to get user's PRs: %w", err)
 		}
 
+		// пользователь переходит в новую команду, его ревью остаются старой команде
+		moveCtx := entityEvent.WithReason(ctx, entityEvent.ReasonTeamMove)
 		var wg sync.WaitGroup
 		for _, pr := range prs {
 			wg.Add(1)
 			pr := pr
 			go func() {
 				defer wg.Done()
-				if err := s.ReassignPullRequest(ctx, pr, *user); err != nil {
+				if err := s.ReassignPullRequest(moveCtx, pr, *user); err != nil {
 					errChan <- fmt.Errorf("reassign PR %s for user %s: %w", pr.Id, user.Id, err)
 				}
 			}()
@@ -85,7 +209,7 @@ func (s *PrService) AddTeam(ctx context.Context, teamDto *dto.AddTeamRequest) er
 		wg.Wait()
 
 		if len(prs) > 0 {
-			if err := s.repo.RemoveReviewerFromAllPR(ctx, user.Id); err != nil {
+			if err := s.repo.RemoveReviewerFromAllPR(moveCtx, user.Id); err != nil {
 				return fmt.Errorf("failed to remove old reviewer %s from all PRs: %w", user.Id, err)
 			}
 		}
@@ -100,15 +224,24 @@ func (s *PrService) AddTeam(ctx context.Context, teamDto *dto.AddTeamRequest) er
 		}
 	}
 
-	if err := s.repo.AddTeam(ctx, teamDto.TeamName, users); err != nil {
+	newTeam := entityTeam.Team{
+		Name:              teamDto.TeamName,
+		ReviewerStrategy:  strategy,
+		RequiredReviewers: teamDto.RequiredReviewers,
+		Users:             users,
+	}
+	if err := s.repo.AddTeam(ctx, newTeam); err != nil {
 		return fmt.Errorf("failed to add team: %w", err)
 	}
-
 	return nil
 }
 
 func (s *PrService) ReassignPullRequest(ctx context.Context, activePr entityPR.PullRequest, user entityUser.User) error {
-	team, err := s.repo.GetTeam(ctx, user.TeamID)
+	poolID := user.TeamID
+	if p, ok := activePr.PoolOf(user.Id); ok {
+		poolID = p
+	}
+	team, err := s.repo.GetTeam(ctx, poolID)
 	if err != nil {
 		if err == repos.ErrTeamNotFound {
 			return ErrTeamNotFound
@@ -122,33 +255,40 @@ func (s *PrService) ReassignPullRequest(ctx context.Context, activePr entityPR.P
 		}
 	}
 	activePr.Reviewers = filtered
-	activeCount := 0
-	for _, r := range activePr.Reviewers {
-		for _, tUser := range team.Users {
-			if tUser.Id == r.Id && tUser.IsActive {
-				activeCount++
-				break
-			}
-		}
-	}
-	if activeCount < 2 {
+	// флаг need_more_reviewers считается по пулу команды автора,
+	// для слотов из других команд важно только, нашлась ли замена
+	authorPool := activePr.Author.TeamID == 0 || activePr.Author.TeamID == team.Id
+	activeCount := activeInPool(activePr, team)
+	required := team.ReviewersNeeded()
+	if authorPool && activeCount < required {
 		activePr.NeedMoreReviewers = true
 	}
 	candidates := make([]entityUser.User, 0)
 	for _, candidate := range team.Users {
 		if candidate.Id != user.Id &&
 			candidate.Id != activePr.Author.Id &&
-			candidate.IsActive {
+			candidate.IsActive &&
+			!isReviewer(activePr, candidate.Id) {
 			candidates = append(candidates, candidate)
 		}
 	}
-	if len(candidates) > 0 {
-		rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
-		activePr.Reviewers = append(activePr.Reviewers, candidates[0])
+	candidates, atCapacity, err := s.withRoom(ctx, candidates)
+	if err != nil {
+		return err
+	}
+	selected, err := s.pick(ctx, team, candidates, 1, activePr.RequiredTags)
+	if err != nil {
+		return fmt.Errorf("failed to select reviewer for PR %s: %w", activePr.Id, err)
+	}
+	if len(selected) > 0 {
+		activePr.Reviewers = append(activePr.Reviewers, selected[0])
+		activePr.SetPool(selected[0].Id, team.Id)
 		activeCount++
-		if activeCount >= 2 {
+		if authorPool && activeCount >= required {
 			activePr.NeedMoreReviewers = false
 		}
+	} else if !authorPool {
+		activePr.NeedMoreReviewers = true
 	}
 	seen := make(map[string]struct{})
 	unique := make([]entityUser.User, 0)
@@ -159,10 +299,125 @@ func (s *PrService) ReassignPullRequest(ctx context.Context, activePr entityPR.P
 		}
 	}
 	activePr.Reviewers = unique
+	activePr.UpdateNeedMoreReason(atCapacity)
 
+	// причину задаёт вызывающий (например, переход в другую команду), по умолчанию - деактивация
+	if entityEvent.ReasonFrom(ctx) == "" {
+		ctx = entityEvent.WithReason(ctx, entityEvent.ReasonDeactivate)
+	}
 	return s.repo.UpdatePr(ctx, activePr.Id, activePr)
 }
 
+// withRoom убирает кандидатов, у которых открытых ревью уже столько, сколько позволяет max_open_reviews,
+// второе значение - был ли кто-то пропущен из-за лимита
+func (s *PrService) withRoom(ctx context.Context, candidates []entityUser.User) ([]entityUser.User, bool, error) {
+	limited := make([]string, 0)
+	for _, c := range candidates {
+		if c.MaxOpenReviews > 0 {
+			limited = append(limited, c.Id)
+		}
+	}
+	if len(limited) == 0 {
+		return candidates, false, nil
+	}
+	load, err := s.repo.GetReviewLoad(ctx, limited)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get review load: %w", err)
+	}
+	res := make([]entityUser.User, 0, len(candidates))
+	atCapacity := false
+	for _, c := range candidates {
+		if c.MaxOpenReviews > 0 && load[c.Id].OpenReviews >= c.MaxOpenReviews {
+			atCapacity = true
+			continue
+		}
+		res = append(res, c)
+	}
+	return res, atCapacity, nil
+}
+
+// activeInPool считает активных ревьюеров PR, назначенных из пула команды team
+// (ревьюеры без сохранённого пула считаются назначенными из команды автора)
+func activeInPool(pr entityPR.PullRequest, team *entityTeam.Team) int {
+	count := 0
+	for _, r := range pr.Reviewers {
+		if pool, ok := pr.PoolOf(r.Id); ok && pool != team.Id {
+			continue
+		}
+		for _, u := range team.Users {
+			if u.Id == r.Id && u.IsActive {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func isReviewer(pr entityPR.PullRequest, userID string) bool {
+	for _, r := range pr.Reviewers {
+		if r.Id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PrService) UpdateTeamSettings(ctx context.Context, settings dto.TeamSettingsRequest) (*entityTeam.Team, error) {
+	team, err := s.repo.GetTeamByName(ctx, settings.TeamName)
+	if err != nil {
+		if errors.Is(err, repos.ErrTeamNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
+	}
+	if settings.ReviewerStrategy != "" {
+		if !entityTeam.IsValidStrategy(settings.ReviewerStrategy) {
+			return nil, ErrInvalidReviewerStrategy
+		}
+		team.ReviewerStrategy = settings.ReviewerStrategy
+	}
+	if settings.RequiredReviewers < 0 {
+		return nil, ErrInvalidRequiredReviewers
+	}
+	if settings.RequiredReviewers > 0 {
+		team.RequiredReviewers = settings.RequiredReviewers
+	}
+	if settings.RequiredApprovals < 0 {
+		return nil, ErrInvalidRequiredApprovals
+	}
+	if settings.RequiredApprovals > 0 {
+		team.RequiredApprovals = settings.RequiredApprovals
+	}
+	if settings.SLA != nil {
+		sla := entityTeam.SLA{
+			ReviewHours:   settings.SLA.ReviewHours,
+			EscalateHours: settings.SLA.EscalateHours,
+			Action:        settings.SLA.Action,
+		}
+		if sla.Action == "" {
+			sla.Action = entityTeam.SLAActionEscalate
+		}
+		if !sla.IsValid() {
+			return nil, ErrInvalidSLA
+		}
+		team.SLA = sla
+	}
+	if settings.StaleReviewHours != nil {
+		if *settings.StaleReviewHours < 0 {
+			return nil, ErrInvalidStaleReviewHours
+		}
+		team.StaleReviewHours = *settings.StaleReviewHours
+	}
+	if err := s.repo.UpdateTeamSettings(ctx, *team); err != nil {
+		if errors.Is(err, repos.ErrTeamNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("failed to update team settings: %w", err)
+	}
+	return team, nil
+}
+
 func (s *PrService) GetTeam(ctx context.Context, teamName string) (*entityTeam.Team, error) {
 	team, err := s.repo.GetTeamByName(ctx, teamName)
 	if err != nil {
@@ -190,62 +445,84 @@ func (s *PrService) SetUserActive(ctx context.Context, userId string, isActive b
 		return err
 	}
 
+	if !isActive {
+		return s.moveReviewsAway(entityEvent.WithReason(ctx, entityEvent.ReasonDeactivate), *user)
+	}
+	return s.offerNeedMorePrs(entityEvent.WithReason(ctx, entityEvent.ReasonActivate), *user)
+}
+
+// moveReviewsAway переназначает открытые PR пользователя на других участников его команды (или пула)
+func (s *PrService) moveReviewsAway(ctx context.Context, user entityUser.User) error {
 	activePrs, err := s.repo.GetUsersPr(ctx, user.Id, true)
 	if err != nil {
 		return fmt.Errorf("failed to get user PRs for reassignment: %w", err)
 	}
+	for _, pr := range activePrs {
+		if err := s.ReassignPullRequest(ctx, pr, user); err != nil {
+			return fmt.Errorf("failed to reassign PR %s: %w", pr.Id, err)
+		}
+	}
+	if err := s.repo.RemoveReviewerFromAllPR(ctx, user.Id); err != nil {
+		return fmt.Errorf("failed to remove old reviewer %s from all PRs: %w", user.Id, err)
+	}
+	return nil
+}
+
+// offerNeedMorePrs назначает пользователя на открытые PR его команды, которым не хватает ревьюеров
+func (s *PrService) offerNeedMorePrs(ctx context.Context, user entityUser.User) error {
+	required := entityTeam.DefaultRequiredReviewers
 	team, _ := s.repo.GetTeam(ctx, user.TeamID)
-	if !isActive {
-		for _, pr := range activePrs {
-			if err := s.ReassignPullRequest(ctx, pr, *user); err != nil {
-				return fmt.Errorf("failed to reassign PR %s: %w", pr.Id, err)
-			}
+	if team != nil {
+		required = team.ReviewersNeeded()
+	}
+	activePrs, err := s.repo.GetTeamPr(ctx, user.TeamID)
+	if err != nil {
+		return fmt.Errorf("failed to get team for user %s: %w", user.Id, err)
+	}
+	openReviews := 0
+	if user.MaxOpenReviews > 0 {
+		load, err := s.repo.GetReviewLoad(ctx, []string{user.Id})
+		if err != nil {
+			return fmt.Errorf("failed to get review load of user %s: %w", user.Id, err)
 		}
-		if err := s.repo.RemoveReviewerFromAllPR(ctx, user.Id); err != nil {
-			return fmt.Errorf("failed to remove old reviewer %s from all PRs: %w", user.Id, err)
+		openReviews = load[user.Id].OpenReviews
+	}
+	for _, pr := range activePrs {
+		if pr.Status != entityPR.StatusOpen || !pr.NeedMoreReviewers || isReviewer(pr, user.Id) || user.Id == pr.Author.Id {
+			continue
 		}
-	} else {
-		activePrs, err = s.repo.GetTeamPr(ctx, user.TeamID)
-		if err != nil {
-			return fmt.Errorf("failed to get team for user %s: %w", user.Id, err)
+		if user.MaxOpenReviews > 0 && openReviews >= user.MaxOpenReviews {
+			break
 		}
-		for _, pr := range activePrs {
-			if !pr.NeedMoreReviewers {
+		openReviews++
+		pr.Reviewers = append(pr.Reviewers, user)
+		pr.SetPool(user.Id, user.TeamID)
+		activeReviewersCount := 0
+		for _, r := range pr.Reviewers {
+			if r.Id == user.Id {
+				activeReviewersCount++
 				continue
 			}
-			alreadyReviewer := false
-			for _, r := range pr.Reviewers {
-				if r.Id == user.Id {
-					alreadyReviewer = true
-					break
-				}
+			if team == nil {
+				continue
 			}
-			if !alreadyReviewer && user.Id != pr.Author.Id {
-				pr.Reviewers = append(pr.Reviewers, *user)
-				activeReviewersCount := 0
-				for _, r := range pr.Reviewers {
-					for _, tUser := range team.Users {
-						if tUser.Id == r.Id && tUser.IsActive {
-							activeReviewersCount++
-							break
-						}
-					}
-				}
-				if activeReviewersCount >= 2 {
-					pr.NeedMoreReviewers = false
-				}
-				user.IsActive = isActive
-				if err := s.repo.UpdateUser(ctx, *user); err != nil {
-					return err
-				}
-				if err := s.repo.UpdatePr(ctx, pr.Id, pr); err != nil {
-					return fmt.Errorf("failed to update PR %s after activating user: %w", pr.Id, err)
-				}
-				if err := s.repo.AddReviewerToPR(ctx, pr.Id, userId); err != nil {
-					return err
+			for _, tUser := range team.Users {
+				if tUser.Id == r.Id && tUser.IsActive {
+					activeReviewersCount++
+					break
 				}
 			}
 		}
+		if activeReviewersCount >= required {
+			pr.NeedMoreReviewers = false
+			pr.UpdateNeedMoreReason(false)
+		}
+		if err := s.repo.UpdatePr(ctx, pr.Id, pr); err != nil {
+			return fmt.Errorf("failed to update PR %s after activating user: %w", pr.Id, err)
+		}
+		if err := s.repo.AddReviewerToPR(ctx, pr.Id, user.Id); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -261,6 +538,84 @@ func (s *PrService) GetUserWithTeam(ctx context.Context, userId string) (*entity
 	return user, team, nil
 }
 
+// SetMaxOpenReviews задаёт лимит одновременных открытых ревью пользователя, 0 снимает лимит
+func (s *PrService) SetMaxOpenReviews(ctx context.Context, userID string, maxOpen int) (*entityUser.User, error) {
+	if maxOpen < 0 {
+		return nil, ErrInvalidMaxOpenReviews
+	}
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if err := s.repo.SetMaxOpenReviews(ctx, user.Id, maxOpen); err != nil {
+		return nil, fmt.Errorf("failed to set max open reviews: %w", err)
+	}
+	user.MaxOpenReviews = maxOpen
+	return user, nil
+}
+
+// SetUserEmail задаёт адрес для писем о назначениях и ежедневной сводки, пустой адрес отключает письма
+func (s *PrService) SetUserEmail(ctx context.Context, userID string, email string) (*entityUser.User, error) {
+	email = strings.TrimSpace(email)
+	if email != "" && !entityUser.IsValidEmail(email) {
+		return nil, ErrInvalidEmail
+	}
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if err := s.repo.SetUserEmail(ctx, user.Id, email); err != nil {
+		return nil, fmt.Errorf("failed to set email: %w", err)
+	}
+	user.Email = email
+	return user, nil
+}
+
+// SetUserTags заменяет навыки пользователя
+func (s *PrService) SetUserTags(ctx context.Context, req dto.UserTagsRequest) (*entityUser.User, error) {
+	user, err := s.repo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetUserTags(ctx, user.Id, tags); err != nil {
+		return nil, fmt.Errorf("failed to set user tags: %w", err)
+	}
+	user.Tags = tags
+	return user, nil
+}
+
+func (s *PrService) GetUserTags(ctx context.Context, userID string) (*entityUser.User, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	tags, err := s.repo.GetUserTags(ctx, []string{user.Id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user tags: %w", err)
+	}
+	user.Tags = tags[user.Id]
+	if user.Tags == nil {
+		user.Tags = []string{}
+	}
+	return user, nil
+}
+
 func (s *PrService) CreatePR(ctx context.Context, prDto dto.CreatePR) (*entityPR.PullRequest, error) {
 	potentialPr, err := s.repo.GetPr(ctx, prDto.PrID)
 	if err == nil && potentialPr != nil {
@@ -284,33 +639,284 @@ func (s *PrService) CreatePR(ctx context.Context, prDto dto.CreatePR) (*entityPR
 		Author:            *author,
 		Reviewers:         []entityUser.User{},
 		NeedMoreReviewers: false,
-		Status:            "OPEN",
+		Status:            entityPR.StatusOpen,
 		CreatedAt:         time.Now(),
 	}
+	if prDto.Draft {
+		pr.Status = entityPR.StatusDraft
+	}
+	if len(prDto.RequiredTags) > 0 {
+		if pr.RequiredTags, err = normalizeTags(prDto.RequiredTags); err != nil {
+			return nil, err
+		}
+	}
+	if pr.Status == entityPR.StatusOpen {
+		if err := s.assignReviewers(ctx, pr, team, prDto.ExtraTeams, prDto.ChangedFiles); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.AddPR(ctx, *pr); err != nil {
+		return nil, fmt.Errorf("failed to create PR: %w", err)
+	}
+	return pr, nil
+}
+
+// assignReviewers назначает ревьюеров открытому PR: владельцев изменённых файлов,
+// участников команды автора и ревьюеров из дополнительных команд
+func (s *PrService) assignReviewers(ctx context.Context, pr *entityPR.PullRequest, team *entityTeam.Team, extraTeams []dto.TeamQuota, changedFiles []string) error {
+	atCapacity := false
+	var err error
+	if len(changedFiles) > 0 {
+		if atCapacity, err = s.addCodeOwners(ctx, pr, team, changedFiles); err != nil {
+			return err
+		}
+	}
 	activeUsers := make([]entityUser.User, 0)
 	for _, u := range team.Users {
-		if u.IsActive && u.Id != author.Id {
+		if u.IsActive && u.Id != pr.Author.Id && !isReviewer(*pr, u.Id) {
 			activeUsers = append(activeUsers, u)
 		}
 	}
-	rand.Shuffle(len(activeUsers), func(i, j int) {
-		activeUsers[i], activeUsers[j] = activeUsers[j], activeUsers[i]
-	})
+	activeUsers, teamAtCapacity, err := s.withRoom(ctx, activeUsers)
+	if err != nil {
+		return err
+	}
+	atCapacity = atCapacity || teamAtCapacity
+	required := team.ReviewersNeeded()
+	need := required - activeInPool(*pr, team)
+	if need > 0 {
+		selected, err := s.pick(ctx, team, activeUsers, need, pr.RequiredTags)
+		if err != nil {
+			return fmt.Errorf("failed to select reviewers: %w", err)
+		}
+		for _, r := range selected {
+			pr.Reviewers = append(pr.Reviewers, r)
+			pr.SetPool(r.Id, team.Id)
+		}
+	}
+	pr.NeedMoreReviewers = activeInPool(*pr, team) < required
 
-	if len(activeUsers) == 0 {
-		pr.NeedMoreReviewers = true
-	} else if len(activeUsers) < 2 {
-		pr.Reviewers = append(pr.Reviewers, activeUsers...)
-		pr.NeedMoreReviewers = true
-	} else {
-		pr.Reviewers = append(pr.Reviewers, activeUsers[0], activeUsers[1])
+	for _, extra := range extraTeams {
+		filled, poolAtCapacity, err := s.addReviewersFromPool(ctx, pr, extra, team.Id)
+		if err != nil {
+			return err
+		}
+		if !filled {
+			pr.NeedMoreReviewers = true
+		}
+		atCapacity = atCapacity || poolAtCapacity
 	}
+	pr.UpdateNeedMoreReason(atCapacity)
+	return nil
+}
 
-	if err := s.repo.AddPR(ctx, *pr); err != nil {
-		return nil, fmt.Errorf("failed to create PR: %w", err)
+// addReviewersFromPool добавляет в PR ревьюеров из дополнительной команды,
+// возвращает false, если квоту заполнить не удалось, и были ли пропущены кандидаты из-за лимита ревью
+func (s *PrService) addReviewersFromPool(ctx context.Context, pr *entityPR.PullRequest, quota dto.TeamQuota, authorTeamID int) (bool, bool, error) {
+	if quota.TeamName == "" || quota.Reviewers < 0 {
+		return false, false, ErrInvalidExtraTeams
+	}
+	count := quota.Reviewers
+	if count == 0 {
+		count = 1
+	}
+	poolTeam, err := s.repo.GetTeamByName(ctx, quota.TeamName)
+	if err != nil {
+		if errors.Is(err, repos.ErrTeamNotFound) {
+			return false, false, ErrTeamNotFound
+		}
+		return false, false, fmt.Errorf("failed to get team %s: %w", quota.TeamName, err)
 	}
+	if poolTeam.Id == authorTeamID {
+		return false, false, ErrInvalidExtraTeams
+	}
+	candidates := make([]entityUser.User, 0)
+	for _, u := range poolTeam.Users {
+		if u.IsActive && u.Id != pr.Author.Id && !isReviewer(*pr, u.Id) {
+			candidates = append(candidates, u)
+		}
+	}
+	candidates, atCapacity, err := s.withRoom(ctx, candidates)
+	if err != nil {
+		return false, false, err
+	}
+	selected, err := s.pick(ctx, poolTeam, candidates, count, pr.RequiredTags)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to select reviewers from team %s: %w", poolTeam.Name, err)
+	}
+	for _, r := range selected {
+		pr.Reviewers = append(pr.Reviewers, r)
+		pr.SetPool(r.Id, poolTeam.Id)
+	}
+	return len(selected) >= count, atCapacity, nil
+}
 
-	return pr, nil
+// addCodeOwners назначает ревьюерами владельцев изменённых файлов по правилам команды автора:
+// пользователь-владелец добавляется сам, из команды-владельца выбирается один ревьюер.
+// Возвращает, были ли владельцы пропущены из-за лимита открытых ревью
+func (s *PrService) addCodeOwners(ctx context.Context, pr *entityPR.PullRequest, team *entityTeam.Team, files []string) (bool, error) {
+	rules, err := s.repo.GetCodeOwners(ctx, team.Id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code owners: %w", err)
+	}
+	atCapacity := false
+	seen := make(map[entityOwners.Owner]bool)
+	for _, owner := range matchOwners(rules, files) {
+		if seen[owner] {
+			continue
+		}
+		seen[owner] = true
+		switch owner.Kind {
+		case entityOwners.OwnerUser:
+			u, err := s.repo.GetUserByID(ctx, owner.Name)
+			if err != nil {
+				if errors.Is(err, repos.ErrNoUserWithId) {
+					continue
+				}
+				return false, fmt.Errorf("failed to get code owner %s: %w", owner.Name, err)
+			}
+			if !u.IsActive || u.Id == pr.Author.Id || isReviewer(*pr, u.Id) {
+				continue
+			}
+			windows, err := s.repo.GetAvailability(ctx, u.Id)
+			if err != nil {
+				return false, fmt.Errorf("failed to get availability of code owner %s: %w", u.Id, err)
+			}
+			if entityUser.Unavailable(windows, time.Now()) {
+				continue
+			}
+			room, full, err := s.withRoom(ctx, []entityUser.User{*u})
+			if err != nil {
+				return false, err
+			}
+			atCapacity = atCapacity || full
+			if len(room) == 0 {
+				continue
+			}
+			pr.Reviewers = append(pr.Reviewers, *u)
+			pr.SetPool(u.Id, u.TeamID)
+		case entityOwners.OwnerTeam:
+			ownerTeam := team
+			if owner.Name != team.Name {
+				ownerTeam, err = s.repo.GetTeamByName(ctx, owner.Name)
+				if err != nil {
+					if errors.Is(err, repos.ErrTeamNotFound) {
+						continue
+					}
+					return false, fmt.Errorf("failed to get code owner team %s: %w", owner.Name, err)
+				}
+			}
+			candidates := make([]entityUser.User, 0)
+			for _, u := range ownerTeam.Users {
+				if u.IsActive && u.Id != pr.Author.Id && !isReviewer(*pr, u.Id) {
+					candidates = append(candidates, u)
+				}
+			}
+			candidates, full, err := s.withRoom(ctx, candidates)
+			if err != nil {
+				return false, err
+			}
+			atCapacity = atCapacity || full
+			selected, err := s.pick(ctx, ownerTeam, candidates, 1, pr.RequiredTags)
+			if err != nil {
+				return false, fmt.Errorf("failed to select reviewer from team %s: %w", ownerTeam.Name, err)
+			}
+			for _, r := range selected {
+				pr.Reviewers = append(pr.Reviewers, r)
+				pr.SetPool(r.Id, ownerTeam.Id)
+			}
+		}
+	}
+	return atCapacity, nil
+}
+
+// matchOwners возвращает владельцев всех путей в порядке их появления
+func matchOwners(rules []entityOwners.Rule, files []string) []entityOwners.Owner {
+	owners := make([]entityOwners.Owner, 0)
+	for _, f := range files {
+		if rule, ok := entityOwners.Match(rules, f); ok {
+			owners = append(owners, rule.Owners...)
+		}
+	}
+	return owners
+}
+
+// UploadCodeOwners заменяет правила CODEOWNERS команды
+func (s *PrService) UploadCodeOwners(ctx context.Context, req dto.UploadCodeOwnersRequest) ([]entityOwners.Rule, error) {
+	team, err := s.repo.GetTeamByName(ctx, req.TeamName)
+	if err != nil {
+		if errors.Is(err, repos.ErrTeamNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	rules, err := entityOwners.Parse(strings.NewReader(req.Content))
+	if err != nil {
+		if errors.Is(err, entityOwners.ErrInvalidSyntax) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCodeOwners, err)
+		}
+		return nil, err
+	}
+	checked := make(map[entityOwners.Owner]bool)
+	for _, rule := range rules {
+		for _, owner := range rule.Owners {
+			if checked[owner] {
+				continue
+			}
+			checked[owner] = true
+			if err := s.checkOwner(ctx, owner); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.repo.ReplaceCodeOwners(ctx, team.Id, rules); err != nil {
+		return nil, fmt.Errorf("failed to save code owners: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *PrService) checkOwner(ctx context.Context, owner entityOwners.Owner) error {
+	var err error
+	if owner.Kind == entityOwners.OwnerTeam {
+		_, err = s.repo.GetTeamByName(ctx, owner.Name)
+		if errors.Is(err, repos.ErrTeamNotFound) {
+			return fmt.Errorf("%w: team %s", ErrUnknownOwner, owner.Name)
+		}
+	} else {
+		_, err = s.repo.GetUserByID(ctx, owner.Name)
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return fmt.Errorf("%w: user %s", ErrUnknownOwner, owner.Name)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check owner %s: %w", owner.Name, err)
+	}
+	return nil
+}
+
+// TestCodeOwners показывает, какие владельцы будут назначены для каждого пути
+func (s *PrService) TestCodeOwners(ctx context.Context, req dto.TestCodeOwnersRequest) (map[string][]entityOwners.Owner, error) {
+	team, err := s.repo.GetTeamByName(ctx, req.TeamName)
+	if err != nil {
+		if errors.Is(err, repos.ErrTeamNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	rules, err := s.repo.GetCodeOwners(ctx, team.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code owners: %w", err)
+	}
+	res := make(map[string][]entityOwners.Owner, len(req.Paths))
+	for _, path := range req.Paths {
+		if rule, ok := entityOwners.Match(rules, path); ok {
+			res[path] = rule.Owners
+		} else {
+			res[path] = []entityOwners.Owner{}
+		}
+	}
+	return res, nil
 }
 
 func (s *PrService) GetUsersPr(ctx context.Context, userId string) ([]entity.PullRequest, error) {
@@ -327,41 +933,170 @@ func (s *PrService) GetUsersPr(ctx context.Context, userId string) ([]entity.Pul
 	return prs, nil
 }
 
-func (s *PrService) Merge(ctx context.Context, userId string, prId string) (*entityPR.PullRequest, error) {
-	pr, err := s.repo.GetPr(ctx, prId)
+// GetPullRequest возвращает PR вместе с состоянием переноса ревьюеров к провайдеру
+func (s *PrService) GetPullRequest(ctx context.Context, prID string) (*entityPR.PullRequest, error) {
+	pr, err := s.repo.GetPr(ctx, prID)
 	if err != nil {
 		if errors.Is(err, repos.ErrPrNotFound) {
 			return nil, ErrPrNotFound
 		}
 		return nil, fmt.Errorf("failed to get PR: %w", err)
 	}
-	isReviewer := false
-	for _, r := range pr.Reviewers {
-		if r.Id == userId {
-			isReviewer = true
-			break
-		}
+	return pr, nil
+}
+
+// GetPullRequestHistory возвращает историю ревьюеров PR: кого и когда назначили, сняли или заменили, почему и кто
+func (s *PrService) GetPullRequestHistory(ctx context.Context, prID string) ([]entityPR.Assignment, error) {
+	if _, err := s.GetPullRequest(ctx, prID); err != nil {
+		return nil, err
+	}
+	history, err := s.repo.GetAssignments(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviewer history: %w", err)
 	}
-	if userId == "admin" {
-		isReviewer = true
+	return history, nil
+}
+
+func (s *PrService) Merge(ctx context.Context, prId string) (*entityPR.PullRequest, error) {
+	p, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := s.repo.GetPr(ctx, prId)
+	if err != nil {
+		if errors.Is(err, repos.ErrPrNotFound) {
+			return nil, ErrPrNotFound
+		}
+		return nil, fmt.Errorf("failed to get PR: %w", err)
 	}
-	if pr.Status != "OPEN" {
+	if pr.Status == entityPR.StatusMerged {
 		return pr, nil
 	}
-	if !isReviewer {
+	if !pr.Status.CanTransitionTo(entityPR.StatusMerged) {
+		return nil, ErrInvalidTransition
+	}
+	isAdmin := p.IsAdmin()
+	// API ключу с pr:merge (CI) не нужно быть ревьюером, но одобрения он не обходит
+	if !isAdmin && !isReviewer(*pr, p.UserID) && !p.HasScope(entityAuth.PermPrMerge) {
 		return nil, ErrUnableToMerge
 	}
+	if err := s.checkApprovals(ctx, pr); err != nil {
+		if !isAdmin || !(errors.Is(err, ErrNotEnoughApprovals) || errors.Is(err, ErrChangesRequested)) {
+			return nil, err
+		}
+		// администратор может смержить PR без одобрений, это сохраняется в review_bypassed
+		pr.ReviewBypassed = true
+	}
 
-	pr.Status = "MERGED"
+	if err := pr.TransitionTo(entityPR.StatusMerged); err != nil {
+		return nil, err
+	}
 	now := time.Now().UTC()
 	pr.MergedAt = &now
+	pr.MergedBy = p.Subject
 	if err := s.repo.UpdatePr(ctx, pr.Id, *pr); err != nil {
 		return nil, fmt.Errorf("failed to update PR: %w", err)
 	}
 	return pr, nil
 }
 
-func (s *PrService) Reassign(ctx context.Context, prID, oldReviewerID string) (*entityPR.PullRequest, string, error) {
+// Ready переводит черновик PR в открытое состояние и назначает ревьюеров
+func (s *PrService) Ready(ctx context.Context, req dto.PullRequestLifecycleRequest) (*entityPR.PullRequest, error) {
+	return s.openPr(ctx, req, entityPR.StatusDraft)
+}
+
+// Reopen повторно открывает закрытый PR и заново назначает ревьюеров
+func (s *PrService) Reopen(ctx context.Context, req dto.PullRequestLifecycleRequest) (*entityPR.PullRequest, error) {
+	return s.openPr(ctx, req, entityPR.StatusClosed)
+}
+
+// openPr переводит PR из состояния from в OPEN и назначает ревьюеров из команды автора
+func (s *PrService) openPr(ctx context.Context, req dto.PullRequestLifecycleRequest, from entityPR.Status) (*entityPR.PullRequest, error) {
+	pr, err := s.repo.GetPr(ctx, req.PrID)
+	if err != nil {
+		if errors.Is(err, repos.ErrPrNotFound) {
+			return nil, ErrPrNotFound
+		}
+		return nil, fmt.Errorf("failed to get PR: %w", err)
+	}
+	if pr.Status != from {
+		return nil, ErrInvalidTransition
+	}
+	if err := pr.TransitionTo(entityPR.StatusOpen); err != nil {
+		return nil, err
+	}
+	team, err := s.repo.GetTeam(ctx, pr.Author.TeamID)
+	if err != nil {
+		if errors.Is(err, repos.ErrTeamNotFound) {
+			return nil, ErrAuthorOrTeamAreNotFound
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	pr.Reviewers = []entityUser.User{}
+	pr.ReviewerPools = nil
+	if err := s.assignReviewers(ctx, pr, team, req.ExtraTeams, req.ChangedFiles); err != nil {
+		return nil, err
+	}
+	reason := entityEvent.ReasonReopen
+	if from == entityPR.StatusDraft {
+		reason = entityEvent.ReasonReady
+	}
+	if err := s.repo.UpdatePr(entityEvent.WithReason(ctx, reason), pr.Id, *pr); err != nil {
+		return nil, fmt.Errorf("failed to update PR: %w", err)
+	}
+	return pr, nil
+}
+
+// Close закрывает PR без слияния и освобождает его ревьюеров
+func (s *PrService) Close(ctx context.Context, prID string) (*entityPR.PullRequest, error) {
+	pr, err := s.repo.GetPr(ctx, prID)
+	if err != nil {
+		if errors.Is(err, repos.ErrPrNotFound) {
+			return nil, ErrPrNotFound
+		}
+		return nil, fmt.Errorf("failed to get PR: %w", err)
+	}
+	if err := pr.TransitionTo(entityPR.StatusClosed); err != nil {
+		return nil, err
+	}
+	pr.Reviewers = []entityUser.User{}
+	pr.ReviewerPools = nil
+	pr.NeedMoreReviewers = false
+	pr.UpdateNeedMoreReason(false)
+	if err := s.repo.UpdatePr(entityEvent.WithReason(ctx, entityEvent.ReasonClose), pr.Id, *pr); err != nil {
+		return nil, fmt.Errorf("failed to update PR: %w", err)
+	}
+	return pr, nil
+}
+
+// ToDraft возвращает открытый PR в черновики и освобождает его ревьюеров
+func (s *PrService) ToDraft(ctx context.Context, prID string) (*entityPR.PullRequest, error) {
+	pr, err := s.repo.GetPr(ctx, prID)
+	if err != nil {
+		if errors.Is(err, repos.ErrPrNotFound) {
+			return nil, ErrPrNotFound
+		}
+		return nil, fmt.Errorf("failed to get PR: %w", err)
+	}
+	if err := pr.TransitionTo(entityPR.StatusDraft); err != nil {
+		return nil, err
+	}
+	pr.Reviewers = []entityUser.User{}
+	pr.ReviewerPools = nil
+	pr.NeedMoreReviewers = false
+	pr.UpdateNeedMoreReason(false)
+	if err := s.repo.UpdatePr(entityEvent.WithReason(ctx, entityEvent.ReasonDraft), pr.Id, *pr); err != nil {
+		return nil, fmt.Errorf("failed to update PR: %w", err)
+	}
+	return pr, nil
+}
+
+func (s *PrService) Reassign(ctx context.Context, prID, oldReviewerID, preferredID string) (*entityPR.PullRequest, string, error) {
+	return s.replaceReviewer(ctx, prID, oldReviewerID, preferredID, entityEvent.ReasonReassign)
+}
+
+// replaceReviewer заменяет ревьюера по правилам Reassign, reason попадает в pr.reviewers_changed
+func (s *PrService) replaceReviewer(ctx context.Context, prID, oldReviewerID, preferredID, reason string) (*entityPR.PullRequest, string, error) {
 	pr, err := s.repo.GetPr(ctx, prID)
 	if err != nil {
 		if errors.Is(err, repos.ErrPrNotFound) {
@@ -369,9 +1104,12 @@ func (s *PrService) Reassign(ctx context.Context, prID, oldReviewerID string) (*
 		}
 		return nil, "", fmt.Errorf("cannot get PR: %w", err)
 	}
-	if pr.Status == "MERGED" {
+	if pr.Status == entityPR.StatusMerged {
 		return nil, "", ErrPrIsMerged
 	}
+	if pr.Status != entityPR.StatusOpen {
+		return nil, "", ErrPrNotOpen
+	}
 	found := false
 	for _, r := range pr.Reviewers {
 		if r.Id == oldReviewerID {
@@ -383,35 +1121,61 @@ func (s *PrService) Reassign(ctx context.Context, prID, oldReviewerID string) (*
 		return nil, "", ErrNotAssigned
 	}
 
-	user, err := s.repo.GetUserByID(ctx, pr.Author.Id)
+	oldReviewer, err := s.repo.GetUserByID(ctx, oldReviewerID)
 	if err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, "", ErrUserNotFound
+		}
 		return nil, "", err
 	}
 
-	team, err := s.repo.GetTeam(ctx, user.TeamID)
+	// замена берётся из того же пула, из которого был назначен заменяемый ревьюер,
+	// а если пул неизвестен - из команды заменяемого ревьюера
+	poolID := oldReviewer.TeamID
+	if p, ok := pr.PoolOf(oldReviewerID); ok {
+		poolID = p
+	}
+	team, err := s.repo.GetTeam(ctx, poolID)
 	if err != nil {
+		if errors.Is(err, repos.ErrTeamNotFound) {
+			return nil, "", ErrTeamNotFound
+		}
 		return nil, "", fmt.Errorf("cannot get team: %w", err)
 	}
 	candidates := make([]entityUser.User, 0)
 	for _, u := range team.Users {
-		if u.IsActive && u.Id != oldReviewerID {
-			alreadyAssigned := false
-			for _, r := range pr.Reviewers {
-				if r.Id == u.Id {
-					alreadyAssigned = true
-					break
-				}
-			}
-			if !alreadyAssigned {
-				candidates = append(candidates, u)
-			}
+		if u.IsActive && u.Id != oldReviewerID && u.Id != pr.Author.Id && !isReviewer(*pr, u.Id) {
+			candidates = append(candidates, u)
 		}
 	}
+	candidates, atCapacity, err := s.withRoom(ctx, candidates)
+	if err != nil {
+		return nil, "", err
+	}
 
-	if len(candidates) == 0 {
-		return nil, "", ErrNoCandidate
+	var newReviewer entityUser.User
+	if preferredID != "" {
+		found := false
+		for _, c := range candidates {
+			if c.Id == preferredID {
+				newReviewer = c
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, "", ErrInvalidCandidate
+		}
+	} else {
+		selected, err := s.pick(ctx, team, candidates, 1, pr.RequiredTags)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to select reviewer: %w", err)
+		}
+		if len(selected) == 0 {
+			return nil, "", ErrNoCandidate
+		}
+		newReviewer = selected[0]
 	}
-	newReviewer := candidates[rand.Intn(len(candidates))]
 
 	updatedReviewers := make([]entityUser.User, 0, len(pr.Reviewers))
 	for _, r := range pr.Reviewers {
@@ -421,20 +1185,15 @@ func (s *PrService) Reassign(ctx context.Context, prID, oldReviewerID string) (*
 	}
 	updatedReviewers = append(updatedReviewers, newReviewer)
 	pr.Reviewers = updatedReviewers
-	activeCount := 0
-	for _, r := range pr.Reviewers {
-		for _, u := range team.Users {
-			if u.Id == r.Id && u.IsActive {
-				activeCount++
-			}
-		}
+	pr.SetPool(newReviewer.Id, team.Id)
+	if pr.Author.TeamID == 0 || team.Id == pr.Author.TeamID {
+		pr.NeedMoreReviewers = activeInPool(*pr, team) < team.ReviewersNeeded()
+		pr.UpdateNeedMoreReason(atCapacity)
 	}
-	pr.NeedMoreReviewers = activeCount < 2
 
-	if err := s.repo.UpdatePr(ctx, prID, *pr); err != nil {
+	if err := s.repo.UpdatePr(entityEvent.WithReason(ctx, reason), prID, *pr); err != nil {
 		return nil, "", fmt.Errorf("failed to update PR reviewers: %w", err)
 	}
-
 	return pr, newReviewer.Id, nil
 }
 
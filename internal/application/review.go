@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+)
+
+var (
+	ErrInvalidVerdict     = errors.New("unknown review verdict")
+	ErrNotEnoughApprovals = errors.New("PR does not have enough approvals")
+	ErrChangesRequested   = errors.New("changes are requested on PR")
+)
+
+// SubmitReview сохраняет вердикт назначенного ревьюера по открытому PR
+func (s *PrService) SubmitReview(ctx context.Context, req dto.ReviewRequest) (*entityPR.Review, error) {
+	p, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	verdict := entityPR.Verdict(req.Verdict)
+	if !verdict.IsValid() {
+		return nil, ErrInvalidVerdict
+	}
+	pr, err := s.repo.GetPr(ctx, req.PrID)
+	if err != nil {
+		if errors.Is(err, repos.ErrPrNotFound) {
+			return nil, ErrPrNotFound
+		}
+		return nil, fmt.Errorf("failed to get PR: %w", err)
+	}
+	if pr.Status == entityPR.StatusMerged {
+		return nil, ErrPrIsMerged
+	}
+	if pr.Status != entityPR.StatusOpen {
+		return nil, ErrPrNotOpen
+	}
+	if p.UserID == "" || !isReviewer(*pr, p.UserID) {
+		return nil, ErrNotAssigned
+	}
+	review := &entityPR.Review{
+		PrID:       pr.Id,
+		ReviewerID: p.UserID,
+		Verdict:    verdict,
+		Body:       req.Body,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if review.Id, err = s.repo.AddReview(ctx, *review); err != nil {
+		return nil, fmt.Errorf("failed to save review: %w", err)
+	}
+	return review, nil
+}
+
+// checkApprovals проверяет, что у PR достаточно одобрений и нет неснятых запросов изменений
+func (s *PrService) checkApprovals(ctx context.Context, pr *entityPR.PullRequest) error {
+	required := entityTeam.DefaultRequiredApprovals
+	team, err := s.repo.GetTeam(ctx, pr.Author.TeamID)
+	if err != nil && !errors.Is(err, repos.ErrTeamNotFound) {
+		return fmt.Errorf("failed to get team of PR author: %w", err)
+	}
+	if team != nil {
+		required = team.ApprovalsNeeded()
+	}
+	reviews, err := s.repo.GetReviews(ctx, pr.Id)
+	if err != nil {
+		return fmt.Errorf("failed to get reviews: %w", err)
+	}
+	reviewerIDs := make([]string, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		reviewerIDs = append(reviewerIDs, r.Id)
+	}
+	state := entityPR.Summarize(reviews, reviewerIDs)
+	if state.ChangesRequested {
+		return ErrChangesRequested
+	}
+	if state.Approvals < required {
+		return ErrNotEnoughApprovals
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"go.uber.org/zap"
+)
+
+// ReviewerSyncPublisher подписан на события outbox: после изменения ревьюеров PR, связанного с провайдером,
+// ставит их перенос в очередь, сами запросы к провайдеру отправляет ReviewerSyncJob
+type ReviewerSyncPublisher struct {
+	repo interfaces.PullRequestRepo
+}
+
+func NewReviewerSyncPublisher(repo interfaces.PullRequestRepo) *ReviewerSyncPublisher {
+	return &ReviewerSyncPublisher{repo: repo}
+}
+
+func (p *ReviewerSyncPublisher) Publish(ctx context.Context, e entityEvent.Event) error {
+	if e.Aggregate != entityEvent.AggregatePr {
+		return nil
+	}
+	switch e.Type {
+	case entityEvent.PrCreated, entityEvent.PrReviewersChanged:
+		return p.repo.MarkReviewerSyncPending(ctx, e.AggregateID, time.Now().UTC())
+	}
+	return nil
+}
+
+// параметры синхронизации: сколько PR забирается за раз и на сколько они откладываются,
+// чтобы другие реплики не взяли их одновременно
+const (
+	reviewerSyncBatch = 20
+	reviewerSyncLease = time.Minute
+)
+
+// ReviewerSyncJob периодически переносит ревьюеров PR к провайдеру: запрашивает ревью у новых
+// и снимает запрос с замененных. Неудачные попытки повторяются с экспоненциальной паузой
+type ReviewerSyncJob struct {
+	repo     interfaces.PullRequestRepo
+	clients  map[entityHost.Provider]interfaces.CodeHostClient
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewReviewerSyncJob(repo interfaces.PullRequestRepo, clients map[entityHost.Provider]interfaces.CodeHostClient, interval time.Duration, logger *zap.Logger) *ReviewerSyncJob {
+	return &ReviewerSyncJob{
+		repo:     repo,
+		clients:  clients,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (j *ReviewerSyncJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := j.SyncDue(ctx, now); err != nil {
+				j.logger.Error("failed to sync reviewers to code host", zap.Error(err))
+			}
+		}
+	}
+}
+
+// SyncDue переносит ревьюеров PR, время попытки которых наступило
+func (j *ReviewerSyncJob) SyncDue(ctx context.Context, now time.Time) error {
+	due, err := j.repo.ClaimDueReviewerSyncs(ctx, now, reviewerSyncLease, reviewerSyncBatch)
+	if err != nil {
+		return err
+	}
+	for _, link := range due {
+		client, ok := j.clients[link.Provider]
+		if !ok {
+			link.Abandon(fmt.Sprintf("no API token configured for %s", link.Provider))
+		} else if err := j.sync(ctx, client, &link); err != nil {
+			link.Failed(now, err.Error())
+			j.logger.Warn("reviewer sync failed",
+				zap.String("pr_id", link.PrID),
+				zap.String("provider", string(link.Provider)),
+				zap.Int("attempt", link.Attempts),
+				zap.Error(err),
+			)
+		} else {
+			link.Succeeded(now, link.SyncedReviewers, link.LastError)
+		}
+		if err := j.repo.UpdateReviewerSync(ctx, link); err != nil && !errors.Is(err, repos.ErrPrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sync приводит ревьюеров у провайдера к ревьюерам PR. Ревьюеры без логина у провайдера пропускаются
+// и перечисляются в LastError, после успешного запроса link.SyncedReviewers сразу обновляется,
+// чтобы при ошибке следующего запроса не повторять уже сделанное
+func (j *ReviewerSyncJob) sync(ctx context.Context, client interfaces.CodeHostClient, link *entityHost.Link) error {
+	pr, err := j.repo.GetPr(ctx, link.PrID)
+	if err != nil {
+		return fmt.Errorf("failed to get PR: %w", err)
+	}
+	desired := make([]string, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		desired = append(desired, r.Id)
+	}
+	logins, err := j.repo.GetCodeHostLogins(ctx, link.Provider, append(slices.Clone(desired), link.SyncedReviewers...))
+	if err != nil {
+		return err
+	}
+	var unmapped []string
+	mapped := make([]string, 0, len(desired))
+	for _, id := range desired {
+		if _, ok := logins[id]; ok {
+			mapped = append(mapped, id)
+		} else {
+			unmapped = append(unmapped, id)
+		}
+	}
+	add, remove := entityHost.ReviewerDiff(link.SyncedReviewers, mapped)
+	if len(remove) > 0 {
+		if err := client.RemoveReviewers(ctx, *link, loginsOf(remove, logins)); err != nil {
+			return fmt.Errorf("failed to remove reviewers: %w", err)
+		}
+		link.SyncedReviewers = slices.DeleteFunc(link.SyncedReviewers, func(id string) bool {
+			return slices.Contains(remove, id)
+		})
+	}
+	if len(add) > 0 {
+		if err := client.RequestReviewers(ctx, *link, loginsOf(add, logins)); err != nil {
+			return fmt.Errorf("failed to request reviewers: %w", err)
+		}
+		link.SyncedReviewers = append(link.SyncedReviewers, add...)
+	}
+	link.LastError = ""
+	if len(unmapped) > 0 {
+		link.LastError = fmt.Sprintf("reviewers without %s login: %s", link.Provider, strings.Join(unmapped, ", "))
+	}
+	return nil
+}
+
+// loginsOf переводит user_id в логины, снятый ревьюер мог потерять связь с логином - тогда он пропускается
+func loginsOf(userIDs []string, logins map[string]string) []string {
+	res := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if login, ok := logins[id]; ok {
+			res = append(res, login)
+		}
+	}
+	return res
+}
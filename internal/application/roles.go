@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+)
+
+var (
+	ErrInvalidRole    = errors.New("role must be admin or team_lead with a team")
+	ErrRoleNotGranted = errors.New("role is not granted to user")
+)
+
+// GrantRole выдаёт пользователю роль и возвращает все его роли
+func (s *PrService) GrantRole(ctx context.Context, req dto.RoleRequest) ([]entityAuth.Grant, error) {
+	g, err := s.grantFromRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.GrantRole(ctx, req.UserID, g); err != nil {
+		return nil, fmt.Errorf("failed to grant role: %w", err)
+	}
+	return s.repo.GetUserGrants(ctx, req.UserID)
+}
+
+// RevokeRole отзывает у пользователя роль и возвращает оставшиеся роли
+func (s *PrService) RevokeRole(ctx context.Context, req dto.RoleRequest) ([]entityAuth.Grant, error) {
+	g, err := s.grantFromRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RevokeRole(ctx, req.UserID, g); err != nil {
+		if errors.Is(err, repos.ErrGrantNotFound) {
+			return nil, ErrRoleNotGranted
+		}
+		return nil, fmt.Errorf("failed to revoke role: %w", err)
+	}
+	return s.repo.GetUserGrants(ctx, req.UserID)
+}
+
+func (s *PrService) GetUserRoles(ctx context.Context, userID string) ([]entityAuth.Grant, error) {
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return s.repo.GetUserGrants(ctx, userID)
+}
+
+func (s *PrService) grantFromRequest(ctx context.Context, req dto.RoleRequest) (entityAuth.Grant, error) {
+	if _, err := s.repo.GetUserByID(ctx, req.UserID); err != nil {
+		if errors.Is(err, repos.ErrNoUserWithId) {
+			return entityAuth.Grant{}, ErrUserNotFound
+		}
+		return entityAuth.Grant{}, err
+	}
+	switch entityAuth.Role(req.Role) {
+	case entityAuth.RoleAdmin:
+		if req.TeamName != "" {
+			return entityAuth.Grant{}, ErrInvalidRole
+		}
+		return entityAuth.Grant{Role: entityAuth.RoleAdmin}, nil
+	case entityAuth.RoleTeamLead:
+		if req.TeamName == "" {
+			return entityAuth.Grant{}, ErrInvalidRole
+		}
+		team, err := s.repo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			if errors.Is(err, repos.ErrTeamNotFound) {
+				return entityAuth.Grant{}, ErrTeamNotFound
+			}
+			return entityAuth.Grant{}, err
+		}
+		return entityAuth.Grant{Role: entityAuth.RoleTeamLead, TeamID: team.Id, TeamName: team.Name}, nil
+	}
+	return entityAuth.Grant{}, ErrInvalidRole
+}
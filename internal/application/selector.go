@@ -0,0 +1,215 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+)
+
+// ReviewerSelector выбирает не более count ревьюеров из уже отфильтрованных кандидатов
+// (активные участники, без автора и уже назначенных ревьюеров).
+type ReviewerSelector interface {
+	Select(ctx context.Context, team entityTeam.Team, candidates []entityUser.User, count int) ([]entityUser.User, error)
+}
+
+func defaultSelectors(repo interfaces.PullRequestRepo) map[string]ReviewerSelector {
+	return map[string]ReviewerSelector{
+		entityTeam.StrategyRandom:      NewRandomSelector(nil),
+		entityTeam.StrategyRoundRobin:  NewRoundRobinSelector(repo),
+		entityTeam.StrategyLeastLoaded: NewLeastLoadedSelector(repo),
+		entityTeam.StrategyWeighted:    NewWeightedSelector(repo, nil),
+	}
+}
+
+// lockedRand - обёртка над rand.Rand, так как сам rand.Rand не потокобезопасен,
+// а выбор ревьюеров может идти параллельно (см. AddTeam)
+type lockedRand struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func (r *lockedRand) shuffle(n int, swap func(i, j int)) {
+	if r.rnd == nil {
+		rand.Shuffle(n, swap)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rnd.Shuffle(n, swap)
+}
+
+func (r *lockedRand) float64() float64 {
+	if r.rnd == nil {
+		return rand.Float64()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rnd.Float64()
+}
+
+func limit(count, n int) int {
+	if count > n {
+		return n
+	}
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+type RandomSelector struct {
+	rnd lockedRand
+}
+
+// NewRandomSelector создаёт случайный выбор, rnd можно передать для детерминированных тестов,
+// при nil используется глобальный генератор
+func NewRandomSelector(rnd *rand.Rand) *RandomSelector {
+	return &RandomSelector{rnd: lockedRand{rnd: rnd}}
+}
+
+func (s *RandomSelector) Select(_ context.Context, _ entityTeam.Team, candidates []entityUser.User, count int) ([]entityUser.User, error) {
+	shuffled := make([]entityUser.User, len(candidates))
+	copy(shuffled, candidates)
+	s.rnd.shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[:limit(count, len(shuffled))], nil
+}
+
+// RoundRobinSelector идёт по участникам команды по кругу (в порядке user_id). Последний выбранный
+// хранится в БД у команды, поэтому очередь общая для всех реплик и продолжается после перезапуска
+type RoundRobinSelector struct {
+	repo interfaces.PullRequestRepo
+}
+
+func NewRoundRobinSelector(repo interfaces.PullRequestRepo) *RoundRobinSelector {
+	return &RoundRobinSelector{repo: repo}
+}
+
+func (s *RoundRobinSelector) Select(ctx context.Context, team entityTeam.Team, candidates []entityUser.User, count int) ([]entityUser.User, error) {
+	n := limit(count, len(candidates))
+	if n == 0 {
+		return []entityUser.User{}, nil
+	}
+	sorted := make([]entityUser.User, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id < sorted[j].Id })
+
+	var selected []entityUser.User
+	err := s.repo.RotateRoundRobin(ctx, team.Id, func(last string) (string, error) {
+		start := sort.Search(len(sorted), func(i int) bool { return sorted[i].Id > last }) % len(sorted)
+		selected = make([]entityUser.User, 0, n)
+		for i := 0; i < n; i++ {
+			selected = append(selected, sorted[(start+i)%len(sorted)])
+		}
+		return selected[len(selected)-1].Id, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate reviewers of team %d: %w", team.Id, err)
+	}
+	return selected, nil
+}
+
+// LeastLoadedSelector выбирает кандидатов с наименьшим числом открытых ревью,
+// при равенстве - тех, кому ревью назначали давнее всего (или не назначали никогда)
+type LeastLoadedSelector struct {
+	repo interfaces.PullRequestRepo
+}
+
+func NewLeastLoadedSelector(repo interfaces.PullRequestRepo) *LeastLoadedSelector {
+	return &LeastLoadedSelector{repo: repo}
+}
+
+func (s *LeastLoadedSelector) Select(ctx context.Context, _ entityTeam.Team, candidates []entityUser.User, count int) ([]entityUser.User, error) {
+	n := limit(count, len(candidates))
+	if n == 0 {
+		return []entityUser.User{}, nil
+	}
+	load, err := reviewLoad(ctx, s.repo, candidates)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make([]entityUser.User, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		li, lj := load[sorted[i].Id], load[sorted[j].Id]
+		if li.OpenReviews != lj.OpenReviews {
+			return li.OpenReviews < lj.OpenReviews
+		}
+		switch {
+		case li.LastAssignedAt == nil && lj.LastAssignedAt != nil:
+			return true
+		case li.LastAssignedAt != nil && lj.LastAssignedAt == nil:
+			return false
+		case li.LastAssignedAt != nil && !li.LastAssignedAt.Equal(*lj.LastAssignedAt):
+			return li.LastAssignedAt.Before(*lj.LastAssignedAt)
+		}
+		return sorted[i].Id < sorted[j].Id
+	})
+	return sorted[:n], nil
+}
+
+// WeightedSelector - случайный выбор, где вес кандидата обратно пропорционален
+// числу его открытых ревью: 1/(1+load)
+type WeightedSelector struct {
+	repo interfaces.PullRequestRepo
+	rnd  lockedRand
+}
+
+func NewWeightedSelector(repo interfaces.PullRequestRepo, rnd *rand.Rand) *WeightedSelector {
+	return &WeightedSelector{repo: repo, rnd: lockedRand{rnd: rnd}}
+}
+
+func (s *WeightedSelector) Select(ctx context.Context, _ entityTeam.Team, candidates []entityUser.User, count int) ([]entityUser.User, error) {
+	n := limit(count, len(candidates))
+	if n == 0 {
+		return []entityUser.User{}, nil
+	}
+	load, err := reviewLoad(ctx, s.repo, candidates)
+	if err != nil {
+		return nil, err
+	}
+	pool := make([]entityUser.User, len(candidates))
+	copy(pool, candidates)
+	sort.Slice(pool, func(i, j int) bool { return pool[i].Id < pool[j].Id })
+
+	selected := make([]entityUser.User, 0, n)
+	for len(selected) < n {
+		total := 0.0
+		for _, c := range pool {
+			total += 1 / float64(1+load[c.Id].OpenReviews)
+		}
+		point := s.rnd.float64() * total
+		idx := len(pool) - 1
+		for i, c := range pool {
+			point -= 1 / float64(1+load[c.Id].OpenReviews)
+			if point < 0 {
+				idx = i
+				break
+			}
+		}
+		selected = append(selected, pool[idx])
+		pool = append(pool[:idx], pool[idx+1:]...)
+	}
+	return selected, nil
+}
+
+// reviewLoad возвращает нагрузку кандидатов одним агрегирующим запросом,
+// у кого ни одного назначения нет - в мапе отсутствуют (нулевая нагрузка)
+func reviewLoad(ctx context.Context, repo interfaces.PullRequestRepo, candidates []entityUser.User) (map[string]entityUser.ReviewLoad, error) {
+	userIDs := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		userIDs = append(userIDs, c.Id)
+	}
+	load, err := repo.GetReviewLoad(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review load: %w", err)
+	}
+	return load, nil
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidSLA = errors.New("invalid review SLA")
+
+// CheckReviewSLA находит ревьюеров, не оставивших ревью в срок команды. После первого срока ревьюеру
+// приходит напоминание, после второго он заменяется по правилам Reassign (действие reassign)
+// или о просрочке сообщается тимлидам (escalate, а также reassign, если заменить некем).
+// Напоминание и эскалация публикуются как pr.review_overdue, уведомления по ним рассылает NotificationPublisher
+func (s *PrService) CheckReviewSLA(ctx context.Context, now time.Time) error {
+	breaches, err := s.repo.GetSLABreaches(ctx, now, nil)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, b := range breaches {
+		if err := s.applySLA(ctx, b, now); err != nil {
+			errs = append(errs, fmt.Errorf("PR %s reviewer %s: %w", b.PrID, b.ReviewerID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *PrService) applySLA(ctx context.Context, b entityPR.SLABreach, now time.Time) error {
+	escalate := b.NeedsEscalation(now)
+	if escalate && b.SLA.Action == entityTeam.SLAActionReassign {
+		_, _, err := s.replaceReviewer(ctx, b.PrID, b.ReviewerID, "", entityEvent.ReasonSLA)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, ErrNoCandidate):
+			// заменить некем - сообщаем тимлидам
+		case errors.Is(err, ErrNotAssigned), errors.Is(err, ErrPrNotOpen), errors.Is(err, ErrPrIsMerged), errors.Is(err, ErrPrNotFound):
+			// PR изменился после выборки
+			return nil
+		default:
+			return err
+		}
+	}
+	if b.NeedsReminder() {
+		if _, err := s.repo.MarkReviewOverdue(ctx, b, entityEvent.StageReminder, nil, now); err != nil {
+			return err
+		}
+	}
+	if escalate {
+		leads, err := s.repo.GetTeamLeads(ctx, b.TeamID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.repo.MarkReviewOverdue(ctx, b, entityEvent.StageEscalation, leads, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSLABreaches возвращает текущие просрочки ревью команды teamName или, если она не указана, всех команд
+func (s *PrService) GetSLABreaches(ctx context.Context, teamName string) ([]entityPR.SLABreach, error) {
+	var teamIDs []int
+	if teamName != "" {
+		team, err := s.repo.GetTeamByName(ctx, teamName)
+		if err != nil {
+			if errors.Is(err, repos.ErrTeamNotFound) {
+				return nil, ErrTeamNotFound
+			}
+			return nil, err
+		}
+		teamIDs = []int{team.Id}
+	}
+	return s.repo.GetSLABreaches(ctx, time.Now().UTC(), teamIDs)
+}
+
+// SLAJob периодически проверяет сроки ревью. Проход выполняет одна реплика, см. reviewerJobsLockID
+type SLAJob struct {
+	repo     interfaces.PullRequestRepo
+	svc      interfaces.PrService
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewSLAJob(repo interfaces.PullRequestRepo, svc interfaces.PrService, interval time.Duration, logger *zap.Logger) *SLAJob {
+	return &SLAJob{
+		repo:     repo,
+		svc:      svc,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (j *SLAJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			_, err := j.repo.RunExclusive(ctx, reviewerJobsLockID, func(ctx context.Context) error {
+				return j.svc.CheckReviewSLA(ctx, now.UTC())
+			})
+			if err != nil {
+				j.logger.Error("failed to check review SLA", zap.Error(err))
+			}
+		}
+	}
+}
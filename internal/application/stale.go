@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidStaleReviewHours = errors.New("invalid stale review hours")
+
+// reviewerJobsLockID - ключ advisory lock фоновых задач, которые заменяют ревьюеров (SLA, stale_review_hours
+// и окна недоступности). Пока одна реплика выполняет проход, другие свой пропускают, поэтому один ревьюер
+// не заменяется дважды, а окно недоступности не применяется двумя репликами сразу
+const reviewerJobsLockID = 20251205
+
+// ReassignStaleReviews заменяет ревьюеров, не оставивших ни одного ревью за stale_review_hours команды автора PR,
+// по правилам Reassign. Замена записывается в pr.reviewers_changed с причиной stale.
+// Если заменить некем, ревьюер остаётся на PR до следующего прохода. Возвращает число замен
+func (s *PrService) ReassignStaleReviews(ctx context.Context, now time.Time) (int, error) {
+	stale, err := s.repo.GetStaleReviews(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	replaced := 0
+	var errs []error
+	for _, r := range stale {
+		_, _, err := s.replaceReviewer(ctx, r.PrID, r.ReviewerID, "", entityEvent.ReasonStale)
+		switch {
+		case err == nil:
+			replaced++
+		case errors.Is(err, ErrNoCandidate), errors.Is(err, ErrNotAssigned), errors.Is(err, ErrPrNotOpen),
+			errors.Is(err, ErrPrIsMerged), errors.Is(err, ErrPrNotFound):
+			// заменить некем или PR изменился после выборки
+		default:
+			errs = append(errs, fmt.Errorf("PR %s reviewer %s: %w", r.PrID, r.ReviewerID, err))
+		}
+	}
+	return replaced, errors.Join(errs...)
+}
+
+// StaleReviewJob периодически заменяет ревьюеров без ревью. Проход выполняет одна реплика, см. reviewerJobsLockID
+type StaleReviewJob struct {
+	repo     interfaces.PullRequestRepo
+	svc      interfaces.PrService
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewStaleReviewJob(repo interfaces.PullRequestRepo, svc interfaces.PrService, interval time.Duration, logger *zap.Logger) *StaleReviewJob {
+	return &StaleReviewJob{
+		repo:     repo,
+		svc:      svc,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (j *StaleReviewJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			replaced, err := j.ReassignDue(ctx, now.UTC())
+			if err != nil {
+				j.logger.Error("failed to reassign stale reviews", zap.Error(err))
+			}
+			if replaced > 0 {
+				j.logger.Info("reassigned stale reviews", zap.Int("count", replaced))
+			}
+		}
+	}
+}
+
+// ReassignDue выполняет один проход, если advisory lock не занят другой репликой, и возвращает число замен
+func (j *StaleReviewJob) ReassignDue(ctx context.Context, now time.Time) (int, error) {
+	replaced := 0
+	_, err := j.repo.RunExclusive(ctx, reviewerJobsLockID, func(ctx context.Context) error {
+		var err error
+		replaced, err = j.svc.ReassignStaleReviews(ctx, now)
+		return err
+	})
+	return replaced, err
+}
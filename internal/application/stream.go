@@ -0,0 +1,60 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+)
+
+var ErrInvalidEventType = errors.New("unknown event type")
+
+// streamTypes - события потока по умолчанию: назначения ревьюеров и активность пользователей
+var streamTypes = []entityEvent.Type{
+	entityEvent.PrCreated,
+	entityEvent.PrReviewersChanged,
+	entityEvent.PrMerged,
+	entityEvent.UserActivated,
+	entityEvent.UserDeactivated,
+}
+
+// EventFilter проверяет типы событий и собирает фильтр потока: события пользователя user_id и команды team_name,
+// без них - события всех команд. Кому какие события видны, решает PolicyService
+func (s *PrService) EventFilter(ctx context.Context, req dto.EventStreamRequest) (*entityEvent.Filter, error) {
+	f := &entityEvent.Filter{Types: streamTypes}
+	if len(req.Types) > 0 {
+		f.Types = make([]entityEvent.Type, 0, len(req.Types))
+		for _, t := range req.Types {
+			et := entityEvent.Type(strings.TrimSpace(t))
+			if !et.IsValid() {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidEventType, t)
+			}
+			f.Types = append(f.Types, et)
+		}
+	}
+	if req.UserID != "" {
+		u, err := s.repo.GetUserByID(ctx, req.UserID)
+		if err != nil {
+			if errors.Is(err, repos.ErrNoUserWithId) {
+				return nil, ErrUserNotFound
+			}
+			return nil, err
+		}
+		f.UserID = u.Id
+	}
+	if req.TeamName != "" {
+		team, err := s.repo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			if errors.Is(err, repos.ErrTeamNotFound) {
+				return nil, ErrTeamNotFound
+			}
+			return nil, err
+		}
+		f.TeamIDs = []int{team.Id}
+	}
+	return f, nil
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	"go.uber.org/zap"
+)
+
+// streamFeedBatch - сколько событий outbox читается за один запрос
+const streamFeedBatch = 500
+
+// streamGapTimeout - сколько ждать пропущенный номер outbox. Номер пропускается, если транзакция
+// с меньшим номером закоммитилась позже следующей, или навсегда, если она откатилась
+const streamGapTimeout = time.Minute
+
+// StreamFeed на каждой реплике читает новые события из outbox и передаёт их в поток SSE.
+// Поток не зависит от того, какая реплика публикует outbox и куда (events.publisher),
+// а журналы потока на всех репликах совпадают, поэтому Last-Event-ID подходит к любой из них
+type StreamFeed struct {
+	repo     interfaces.PullRequestRepo
+	stream   interfaces.EventPublisher
+	size     int
+	interval time.Duration
+	logger   *zap.Logger
+
+	started bool
+	after   int64
+	// gaps - пропущенные номера и когда их заметили, они перечитываются до streamGapTimeout
+	gaps map[int64]time.Time
+}
+
+// NewStreamFeed - size событий до запуска сразу попадают в журнал потока, чтобы после перезапуска
+// реплики клиенты могли продолжить поток
+func NewStreamFeed(repo interfaces.PullRequestRepo, stream interfaces.EventPublisher, size int, interval time.Duration, logger *zap.Logger) *StreamFeed {
+	return &StreamFeed{
+		repo:     repo,
+		stream:   stream,
+		size:     size,
+		interval: interval,
+		logger:   logger,
+		gaps:     make(map[int64]time.Time),
+	}
+}
+
+// Run блокируется до отмены ctx
+func (f *StreamFeed) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := f.Poll(ctx, now); err != nil {
+				f.logger.Error("failed to read outbox for event stream", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Poll передаёт в поток события, появившиеся в outbox с прошлого вызова, в порядке номеров
+func (f *StreamFeed) Poll(ctx context.Context, now time.Time) error {
+	if !f.started {
+		last, err := f.repo.GetLastOutboxSeq(ctx)
+		if err != nil {
+			return err
+		}
+		f.after = max(last-int64(f.size), 0)
+		f.started = true
+	}
+	from := f.after
+	for seq, seenAt := range f.gaps {
+		if now.Sub(seenAt) > streamGapTimeout {
+			delete(f.gaps, seq)
+			continue
+		}
+		from = min(from, seq-1)
+	}
+	for {
+		events, err := f.repo.GetOutboxEvents(ctx, from, streamFeedBatch)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			from = e.Seq
+			if e.Seq <= f.after {
+				if _, ok := f.gaps[e.Seq]; !ok {
+					// уже в потоке
+					continue
+				}
+				delete(f.gaps, e.Seq)
+			} else {
+				for seq := f.after + 1; seq < e.Seq; seq++ {
+					f.gaps[seq] = now
+				}
+				f.after = e.Seq
+			}
+			if err := f.stream.Publish(ctx, e); err != nil {
+				return err
+			}
+		}
+		if len(events) < streamFeedBatch || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func asUser(userID string) context.Context {
+	return entityAuth.WithPrincipal(context.Background(), entityAuth.Principal{
+		Subject: userID,
+		UserID:  userID,
+		Roles:   []entityAuth.Role{entityAuth.RoleUser},
+	})
+}
+
+func asAdmin() context.Context {
+	return entityAuth.WithPrincipal(context.Background(), entityAuth.Principal{
+		Subject: "admin",
+		Roles:   []entityAuth.Role{entityAuth.RoleAdmin},
+	})
+}
+
+func TestPolicyService_GetTeam_MemberAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(&entityTeam.Team{
+		Id:    1,
+		Name:  "team1",
+		Users: []entityUser.User{{Id: "u1"}, {Id: "u2"}},
+	}, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1}, nil)
+
+	team, err := svc.GetTeam(asUser("u2"), "team1")
+	assert.NoError(t, err)
+	assert.Equal(t, "team1", team.Name)
+}
+
+func TestPolicyService_GetTeam_OutsiderForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(&entityTeam.Team{
+		Id:    1,
+		Name:  "team1",
+		Users: []entityUser.User{{Id: "u1"}},
+	}, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "admin").Return(nil, repos.ErrNoUserWithId)
+
+	// пользователь с id "admin" не получает прав администратора
+	team, err := svc.GetTeam(asUser("admin"), "team1")
+	assert.Nil(t, team)
+	assert.ErrorIs(t, err, application.ErrForbidden)
+}
+
+func TestPolicyService_GetTeam_NoCaller(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	team, err := svc.GetTeam(context.Background(), "team1")
+	assert.Nil(t, team)
+	assert.ErrorIs(t, err, application.ErrUnauthenticated)
+}
+
+func TestPrService_Merge_UserNamedAdminIsNotAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(reviewedPr(), nil)
+
+	pr, err := svc.Merge(asUser("admin"), "pr1")
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrUnableToMerge)
+}
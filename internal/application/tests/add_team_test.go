@@ -54,7 +54,7 @@ func TestPrService_AddTeam_Success(t *testing.T) {
 		GetUserByID(gomock.Any(), "user1").
 		Return(nil, repos.ErrNoUserWithId)
 	mockRepo.EXPECT().
-		AddTeam(gomock.Any(), "team1", gomock.AssignableToTypeOf([]entityUser.User{})).
+		AddTeam(gomock.Any(), gomock.AssignableToTypeOf(entityTeam.Team{})).
 		Return(nil)
 
 	err := svc.AddTeam(context.Background(), teamDto)
@@ -113,3 +113,30 @@ func TestPrService_AddTeam_ErrorGettingUsersPr(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get user's PRs")
 }
+
+func TestPrService_AddTeam_KeepsEmailOfExistingUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	teamDto := &dto.AddTeamRequest{
+		TeamName: "team2",
+		Members: []dto.MemberDto{
+			{Id: "user1", Name: "Alice", IsActive: true, Email: "attacker@example.com"},
+			{Id: "user2", Name: "Bob", IsActive: true, Email: "bob@example.com"},
+		},
+	}
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team2").Return(nil, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "user1").Return(&entityUser.User{Id: "user1", TeamID: 1, Email: "alice@example.com"}, nil)
+	mockRepo.EXPECT().GetUsersPr(gomock.Any(), "user1", true).Return(nil, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "user2").Return(nil, repos.ErrNoUserWithId)
+	mockRepo.EXPECT().AddTeam(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, team entityTeam.Team) error {
+		assert.Equal(t, "alice@example.com", team.Users[0].Email)
+		assert.Equal(t, "bob@example.com", team.Users[1].Email)
+		return nil
+	})
+
+	assert.NoError(t, svc.AddTeam(context.Background(), teamDto))
+}
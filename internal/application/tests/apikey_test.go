@@ -0,0 +1,109 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/auth"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPrService_IssueAPIKey_StoresHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	var stored entityAuth.APIKey
+	mockRepo.EXPECT().AddAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, k entityAuth.APIKey) (int, error) {
+			stored = k
+			return 7, nil
+		})
+
+	key, plain, err := svc.IssueAPIKey(asAdmin(), dto.IssueAPIKeyRequest{
+		Name:   "ci",
+		Scopes: []string{"pr:create", "PR:MERGE", "pr:create"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, key.Id)
+	assert.True(t, strings.HasPrefix(plain, entityAuth.APIKeyPrefix))
+	assert.Equal(t, entityAuth.HashAPIKey(plain), stored.Hash)
+	assert.NotContains(t, stored.Hash, plain)
+	assert.True(t, strings.HasPrefix(plain, stored.Prefix))
+	assert.Equal(t, []entityAuth.Permission{entityAuth.PermPrCreate, entityAuth.PermPrMerge}, stored.Scopes)
+	assert.Equal(t, "admin", stored.CreatedBy)
+}
+
+func TestPrService_IssueAPIKey_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	_, _, err := svc.IssueAPIKey(asAdmin(), dto.IssueAPIKeyRequest{Name: "ci", Scopes: []string{"roles:manage"}})
+	assert.ErrorIs(t, err, application.ErrInvalidScopes)
+
+	_, _, err = svc.IssueAPIKey(asAdmin(), dto.IssueAPIKeyRequest{Name: "ci"})
+	assert.ErrorIs(t, err, application.ErrInvalidScopes)
+
+	past := time.Now().Add(-time.Hour)
+	_, _, err = svc.IssueAPIKey(asAdmin(), dto.IssueAPIKeyRequest{Name: "ci", Scopes: []string{"pr:merge"}, ExpiresAt: &past})
+	assert.ErrorIs(t, err, application.ErrInvalidExpiry)
+
+	_, _, err = svc.IssueAPIKey(asAdmin(), dto.IssueAPIKeyRequest{Name: " ", Scopes: []string{"pr:merge"}})
+	assert.ErrorIs(t, err, application.ErrInvalidAPIKeyName)
+}
+
+func TestAPIKeyVerifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+
+	now := time.Date(2025, 11, 28, 12, 0, 0, 0, time.UTC)
+	jwtVerifier, _ := auth.NewHS256Verifier(jwtSecret)
+	v := auth.WithAPIKeys(jwtVerifier, mockRepo, func() time.Time { return now })
+
+	plain, _ := entityAuth.GenerateAPIKey()
+	recent := now.Add(-10 * time.Second)
+	expired := now.Add(-time.Minute)
+	mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), entityAuth.HashAPIKey(plain)).Return(&entityAuth.APIKey{
+		Id: 3, Scopes: []entityAuth.Permission{entityAuth.PermPrCreate},
+	}, nil)
+	mockRepo.EXPECT().TouchAPIKey(gomock.Any(), 3, now).Return(nil)
+
+	p, err := v.Verify(context.Background(), plain)
+	assert.NoError(t, err)
+	assert.Equal(t, "apikey:3", p.Subject)
+	assert.True(t, p.CanAny(entityAuth.PermPrCreate))
+	assert.True(t, p.Can(entityAuth.PermPrCreate, 5))
+	assert.False(t, p.CanAny(entityAuth.PermPrMerge))
+	assert.False(t, p.IsAdmin())
+
+	// недавно использованный ключ не пишет last_used_at повторно
+	mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(&entityAuth.APIKey{Id: 3, LastUsedAt: &recent}, nil)
+	_, err = v.Verify(context.Background(), plain)
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(&entityAuth.APIKey{Id: 3, ExpiresAt: &expired}, nil)
+	_, err = v.Verify(context.Background(), plain)
+	assert.ErrorIs(t, err, entityAuth.ErrTokenExpired)
+
+	mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(nil, repos.ErrAPIKeyNotFound)
+	_, err = v.Verify(context.Background(), plain)
+	assert.ErrorIs(t, err, entityAuth.ErrInvalidToken)
+
+	// JWT передаётся дальше без обращения к ключам
+	token, _ := auth.SignHS256(jwtSecret, auth.NewClaims("u1", nil, time.Hour))
+	p, err = v.Verify(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", p.UserID)
+}
@@ -0,0 +1,173 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestPrService_ApplyAvailability_WindowStarts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	now := time.Now()
+	window := entityUser.Availability{Id: 7, UserID: "u2", From: now.Add(-time.Minute), To: now.Add(time.Hour), Reason: "vacation"}
+	prObj := entityPR.PullRequest{
+		Id:        "pr1",
+		Status:    "OPEN",
+		Author:    entityUser.User{Id: "u1", TeamID: 1},
+		Reviewers: []entityUser.User{{Id: "u2"}},
+	}
+	team := &entityTeam.Team{
+		Id:                1,
+		RequiredReviewers: 1,
+		Users: []entityUser.User{
+			{Id: "u1", IsActive: true},
+			{Id: "u2", IsActive: false},
+			{Id: "u3", IsActive: true},
+		},
+	}
+
+	mockRepo.EXPECT().GetStartingAvailability(gomock.Any(), now).Return([]entityUser.Availability{window}, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1, IsActive: true}, nil)
+	mockRepo.EXPECT().GetUsersPr(gomock.Any(), "u2", true).Return([]entityPR.PullRequest{prObj}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, p entityPR.PullRequest) error {
+			assert.Equal(t, []string{"u3"}, ids(p.Reviewers))
+			assert.False(t, p.NeedMoreReviewers)
+			return nil
+		},
+	)
+	mockRepo.EXPECT().RemoveReviewerFromAllPR(gomock.Any(), "u2").Return(nil)
+	mockRepo.EXPECT().MarkAvailabilityStarted(gomock.Any(), 7).Return(nil)
+	mockRepo.EXPECT().GetEndingAvailability(gomock.Any(), now).Return(nil, nil)
+
+	assert.NoError(t, svc.ApplyAvailability(context.Background(), now))
+}
+
+func TestPrService_ApplyAvailability_WindowEnds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	now := time.Now()
+	window := entityUser.Availability{Id: 7, UserID: "u2", From: now.Add(-time.Hour), To: now.Add(-time.Minute)}
+	prObj := entityPR.PullRequest{
+		Id:                "pr1",
+		Status:            "OPEN",
+		Author:            entityUser.User{Id: "u1", TeamID: 1},
+		NeedMoreReviewers: true,
+	}
+	team := &entityTeam.Team{
+		Id:                1,
+		RequiredReviewers: 1,
+		Users:             []entityUser.User{{Id: "u1", IsActive: true}, {Id: "u2", IsActive: true}},
+	}
+
+	mockRepo.EXPECT().GetStartingAvailability(gomock.Any(), now).Return(nil, nil)
+	mockRepo.EXPECT().GetEndingAvailability(gomock.Any(), now).Return([]entityUser.Availability{window}, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1, IsActive: true}, nil)
+	mockRepo.EXPECT().GetAvailability(gomock.Any(), "u2").Return(nil, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().GetTeamPr(gomock.Any(), 1).Return([]entityPR.PullRequest{prObj}, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, p entityPR.PullRequest) error {
+			assert.Equal(t, []string{"u2"}, ids(p.Reviewers))
+			assert.False(t, p.NeedMoreReviewers)
+			return nil
+		},
+	)
+	mockRepo.EXPECT().AddReviewerToPR(gomock.Any(), "pr1", "u2").Return(nil)
+	mockRepo.EXPECT().MarkAvailabilityEnded(gomock.Any(), 7).Return(nil)
+
+	assert.NoError(t, svc.ApplyAvailability(context.Background(), now))
+}
+
+func TestPrService_ApplyAvailability_EndsInsideAnotherWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	now := time.Now()
+	ended := entityUser.Availability{Id: 7, UserID: "u2", From: now.Add(-time.Hour), To: now.Add(-time.Minute)}
+	next := entityUser.Availability{Id: 8, UserID: "u2", From: now.Add(-time.Minute), To: now.Add(time.Hour)}
+
+	mockRepo.EXPECT().GetStartingAvailability(gomock.Any(), now).Return(nil, nil)
+	mockRepo.EXPECT().GetEndingAvailability(gomock.Any(), now).Return([]entityUser.Availability{ended}, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1, IsActive: true}, nil)
+	mockRepo.EXPECT().GetAvailability(gomock.Any(), "u2").Return([]entityUser.Availability{next}, nil)
+	mockRepo.EXPECT().MarkAvailabilityEnded(gomock.Any(), 7).Return(nil)
+
+	assert.NoError(t, svc.ApplyAvailability(context.Background(), now))
+}
+
+func TestPrService_ApplyAvailability_FailedWindowDoesNotStopOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	now := time.Now()
+	broken := entityUser.Availability{Id: 7, UserID: "u2", From: now.Add(-time.Minute), To: now.Add(time.Hour)}
+	vacation := entityUser.Availability{Id: 8, UserID: "u3", From: now.Add(-time.Minute), To: now.Add(time.Hour)}
+
+	mockRepo.EXPECT().GetStartingAvailability(gomock.Any(), now).Return([]entityUser.Availability{broken, vacation}, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(nil, errors.New("db is down"))
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u3").Return(&entityUser.User{Id: "u3", TeamID: 1, IsActive: false}, nil)
+	mockRepo.EXPECT().MarkAvailabilityStarted(gomock.Any(), 8).Return(nil)
+	mockRepo.EXPECT().GetEndingAvailability(gomock.Any(), now).Return(nil, nil)
+
+	err := svc.ApplyAvailability(context.Background(), now)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "availability window 7 of user u2")
+}
+
+func TestPrService_AddAvailability_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	now := time.Now()
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1, IsActive: true}, nil)
+
+	_, err := svc.AddAvailability(context.Background(), dto.AvailabilityRequest{
+		UserID: "u2",
+		From:   now.Add(time.Hour),
+		To:     now,
+	})
+	assert.ErrorIs(t, err, application.ErrInvalidAvailability)
+}
+
+func TestAvailabilityJob_SkipsWhileAnotherReplicaHoldsLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	job := application.NewAvailabilityJob(mockRepo, application.NewPrService(mockRepo), time.Minute, zap.NewNop())
+
+	// окна не читаются, пока блокировку держит другая реплика
+	mockRepo.EXPECT().RunExclusive(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	assert.NoError(t, job.ApplyDue(context.Background(), time.Now()))
+
+	now := time.Now()
+	mockRepo.EXPECT().RunExclusive(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(runExclusively)
+	mockRepo.EXPECT().GetStartingAvailability(gomock.Any(), now).Return(nil, nil)
+	mockRepo.EXPECT().GetEndingAvailability(gomock.Any(), now).Return(nil, nil)
+	assert.NoError(t, job.ApplyDue(context.Background(), now))
+}
@@ -0,0 +1,228 @@
+package application_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/codehost"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	githubSecret = "gh-webhook-secret"
+	gitlabToken  = "gl-webhook-token"
+)
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	assert.NoError(t, err)
+	return body
+}
+
+func githubHeaders(event, delivery string, body []byte, secret string) http.Header {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	h := http.Header{}
+	h.Set("X-GitHub-Event", event)
+	h.Set("X-GitHub-Delivery", delivery)
+	h.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return h
+}
+
+func gitlabHeaders(delivery, token string) http.Header {
+	h := http.Header{}
+	h.Set("X-Gitlab-Event", "Merge Request Hook")
+	h.Set("X-Gitlab-Event-UUID", delivery)
+	h.Set("X-Gitlab-Token", token)
+	return h
+}
+
+func TestGitHubReceiver_ParsesFixtures(t *testing.T) {
+	receiver := codehost.NewGitHubReceiver(githubSecret)
+	cases := []struct {
+		fixture string
+		action  entityHost.Action
+		sender  string
+		draft   bool
+	}{
+		{"github_pull_request_opened.json", entityHost.ActionOpened, "octo-alice", false},
+		{"github_pull_request_closed_merged.json", entityHost.ActionMerged, "octo-bob", false},
+		{"github_pull_request_converted_to_draft.json", entityHost.ActionDraft, "octo-alice", true},
+	}
+	for _, c := range cases {
+		t.Run(c.fixture, func(t *testing.T) {
+			body := fixture(t, c.fixture)
+			e, err := receiver.Parse(githubHeaders("pull_request", "d-1", body, githubSecret), body)
+			assert.NoError(t, err)
+			assert.Equal(t, c.action, e.Action)
+			assert.Equal(t, "github-1296269-42", e.PrID())
+			assert.Equal(t, "acme/backend", e.Repo)
+			assert.Equal(t, "d-1", e.DeliveryID)
+			assert.Equal(t, "octo-alice", e.AuthorLogin)
+			assert.Equal(t, c.sender, e.SenderLogin)
+			assert.Equal(t, "Add search endpoint", e.Title)
+			assert.Equal(t, c.draft, e.Draft)
+		})
+	}
+}
+
+func TestGitHubReceiver_RejectsWrongSignature(t *testing.T) {
+	body := fixture(t, "github_pull_request_opened.json")
+
+	_, err := codehost.NewGitHubReceiver(githubSecret).Parse(githubHeaders("pull_request", "d-1", body, "other"), body)
+	assert.ErrorIs(t, err, entityHost.ErrInvalidSignature)
+
+	_, err = codehost.NewGitHubReceiver("").Parse(githubHeaders("pull_request", "d-1", body, ""), body)
+	assert.ErrorIs(t, err, entityHost.ErrInvalidSignature)
+}
+
+func TestGitHubReceiver_IgnoresOtherEvents(t *testing.T) {
+	body := []byte(`{"zen":"Keep it logically awesome.","hook_id":1}`)
+
+	_, err := codehost.NewGitHubReceiver(githubSecret).Parse(githubHeaders("ping", "d-1", body, githubSecret), body)
+	assert.ErrorIs(t, err, entityHost.ErrIgnoredEvent)
+}
+
+func TestGitLabReceiver_ParsesFixtures(t *testing.T) {
+	receiver := codehost.NewGitLabReceiver(gitlabToken)
+	cases := []struct {
+		fixture string
+		action  entityHost.Action
+		sender  string
+	}{
+		{"gitlab_merge_request_open.json", entityHost.ActionOpened, "alice"},
+		{"gitlab_merge_request_update_draft.json", entityHost.ActionDraft, "alice"},
+		{"gitlab_merge_request_merge.json", entityHost.ActionMerged, "bob"},
+	}
+	for _, c := range cases {
+		t.Run(c.fixture, func(t *testing.T) {
+			e, err := receiver.Parse(gitlabHeaders("uuid-1", gitlabToken), fixture(t, c.fixture))
+			assert.NoError(t, err)
+			assert.Equal(t, c.action, e.Action)
+			assert.Equal(t, "gitlab-278964-7", e.PrID())
+			assert.Equal(t, "acme/backend", e.Repo)
+			assert.Equal(t, c.sender, e.SenderLogin)
+		})
+	}
+}
+
+func TestGitLabReceiver_RejectsWrongToken(t *testing.T) {
+	_, err := codehost.NewGitLabReceiver(gitlabToken).Parse(gitlabHeaders("uuid-1", "guess"), fixture(t, "gitlab_merge_request_open.json"))
+	assert.ErrorIs(t, err, entityHost.ErrInvalidSignature)
+}
+
+func githubEvent(action entityHost.Action) entityHost.PullRequestEvent {
+	return entityHost.PullRequestEvent{
+		Provider:    entityHost.ProviderGitHub,
+		DeliveryID:  "d-1",
+		Action:      action,
+		RepoID:      1296269,
+		Repo:        "acme/backend",
+		Number:      42,
+		Title:       "Add search endpoint",
+		AuthorLogin: "octo-alice",
+		SenderLogin: "octo-bob",
+		Draft:       true,
+	}
+}
+
+func TestPrService_HandleCodeHostEvent_OpenedCreatesPr(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	mockRepo.EXPECT().ClaimCodeHostDelivery(gomock.Any(), entityHost.ProviderGitHub, "d-1").Return(true, nil)
+	mockRepo.EXPECT().GetCodeHostUser(gomock.Any(), entityHost.ProviderGitHub, "octo-alice").
+		Return(&entityHost.UserMapping{Provider: entityHost.ProviderGitHub, Login: "octo-alice", UserID: "u1"}, nil)
+	mockRepo.EXPECT().GetPr(gomock.Any(), "github-1296269-42").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(lifecycleTeam(), nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().AddCodeHostLink(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, l entityHost.Link) error {
+		assert.Equal(t, "github-1296269-42", l.PrID)
+		assert.Equal(t, "acme/backend", l.Repo)
+		assert.Equal(t, 42, l.Number)
+		assert.Equal(t, entityHost.SyncPending, l.Status)
+		return nil
+	})
+
+	pr, err := svc.HandleCodeHostEvent(context.Background(), githubEvent(entityHost.ActionOpened))
+	assert.NoError(t, err)
+	assert.Equal(t, "github-1296269-42", pr.Id)
+	assert.Equal(t, "Add search endpoint", pr.Name)
+	assert.Equal(t, "u1", pr.Author.Id)
+	assert.Equal(t, entityPR.StatusDraft, pr.Status)
+	assert.Equal(t, entityHost.SyncPending, pr.CodeHost.Status)
+}
+
+func TestPrService_HandleCodeHostEvent_DuplicateDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().ClaimCodeHostDelivery(gomock.Any(), entityHost.ProviderGitHub, "d-1").Return(false, nil)
+
+	_, err := svc.HandleCodeHostEvent(context.Background(), githubEvent(entityHost.ActionOpened))
+	assert.ErrorIs(t, err, application.ErrDuplicateDelivery)
+}
+
+func TestPrService_HandleCodeHostEvent_UnmappedAuthorReleasesDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().ClaimCodeHostDelivery(gomock.Any(), entityHost.ProviderGitHub, "d-1").Return(true, nil)
+	mockRepo.EXPECT().GetCodeHostUser(gomock.Any(), entityHost.ProviderGitHub, "octo-alice").Return(nil, repos.ErrCodeHostUserNotFound)
+	mockRepo.EXPECT().ReleaseCodeHostDelivery(gomock.Any(), entityHost.ProviderGitHub, "d-1").Return(nil)
+
+	_, err := svc.HandleCodeHostEvent(context.Background(), githubEvent(entityHost.ActionOpened))
+	assert.ErrorIs(t, err, application.ErrCodeHostUserNotMapped)
+}
+
+func TestPrService_HandleCodeHostEvent_MergedUpstreamBypassesReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	pr := reviewedPr()
+	pr.Id = "github-1296269-42"
+	mockRepo.EXPECT().ClaimCodeHostDelivery(gomock.Any(), entityHost.ProviderGitHub, "d-1").Return(true, nil)
+	mockRepo.EXPECT().GetPr(gomock.Any(), pr.Id).Return(pr, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(lifecycleTeam(), nil)
+	mockRepo.EXPECT().GetReviews(gomock.Any(), pr.Id).Return([]entityPR.Review{}, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), pr.Id, gomock.Any()).Return(nil)
+
+	merged, err := svc.HandleCodeHostEvent(context.Background(), githubEvent(entityHost.ActionMerged))
+	assert.NoError(t, err)
+	assert.Equal(t, entityPR.StatusMerged, merged.Status)
+	assert.Equal(t, "github:octo-bob", merged.MergedBy)
+	assert.True(t, merged.ReviewBypassed)
+}
+
+func TestPrService_ToDraft_ReleasesReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(reviewedPr(), nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).Return(nil)
+
+	pr, err := svc.ToDraft(context.Background(), "pr1")
+	assert.NoError(t, err)
+	assert.Equal(t, entityPR.StatusDraft, pr.Status)
+	assert.Empty(t, pr.Reviewers)
+}
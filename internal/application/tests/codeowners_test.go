@@ -0,0 +1,142 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityOwners "github.com/JanArsMAI/PullRequestService/internal/domain/codeowners"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+const codeOwnersFile = `
+# default owner
+*            @u2
+/docs/       @org/docs
+*.sql        @u3
+/api/**/v1/  @u4
+`
+
+func TestCodeOwners_ParseAndMatch(t *testing.T) {
+	rules, err := entityOwners.Parse(strings.NewReader(codeOwnersFile))
+	assert.NoError(t, err)
+	assert.Len(t, rules, 4)
+
+	cases := []struct {
+		path  string
+		owner string
+	}{
+		{path: "main.go", owner: "u2"},
+		{path: "docs/guide/intro.md", owner: "docs"},
+		{path: "internal/docs/intro.md", owner: "u2"},
+		{path: "migrations/001.sql", owner: "u3"},
+		{path: "api/v1/handler.go", owner: "u4"},
+		{path: "api/public/v1/handler.go", owner: "u4"},
+		{path: "api/v2/handler.go", owner: "u2"},
+	}
+	for _, tc := range cases {
+		rule, ok := entityOwners.Match(rules, tc.path)
+		assert.True(t, ok, tc.path)
+		assert.Equal(t, tc.owner, rule.Owners[0].Name, tc.path)
+	}
+}
+
+func TestCodeOwners_ParseInvalid(t *testing.T) {
+	_, err := entityOwners.Parse(strings.NewReader("*.go\n"))
+	assert.ErrorIs(t, err, entityOwners.ErrInvalidSyntax)
+
+	_, err = entityOwners.Parse(strings.NewReader("*.go u1\n"))
+	assert.ErrorIs(t, err, entityOwners.ErrInvalidSyntax)
+}
+
+func TestPrService_CreatePR_CodeOwnersFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo,
+		application.WithSelector(entityTeam.StrategyRoundRobin, application.NewRoundRobinSelector(mockRepo)))
+	mockRepo.EXPECT().RotateRoundRobin(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(rotateInMemory()).AnyTimes()
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	team := &entityTeam.Team{
+		Id:               1,
+		Name:             "backend",
+		ReviewerStrategy: entityTeam.StrategyRoundRobin,
+		Users: []entityUser.User{
+			*author,
+			{Id: "u2", IsActive: true},
+			{Id: "u3", IsActive: true, TeamID: 1},
+		},
+	}
+	docs := &entityTeam.Team{
+		Id:    2,
+		Name:  "docs",
+		Users: []entityUser.User{{Id: "d1", IsActive: true}},
+	}
+	rules, err := entityOwners.Parse(strings.NewReader(codeOwnersFile))
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "backend", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(team, nil)
+	mockRepo.EXPECT().GetCodeOwners(gomock.Any(), 1).Return(rules, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u3").Return(&entityUser.User{Id: "u3", TeamID: 1, IsActive: true}, nil)
+	mockRepo.EXPECT().GetAvailability(gomock.Any(), "u3").Return(nil, nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "docs").Return(docs, nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).Return(nil)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{
+		PrID:         "pr1",
+		PrName:       "MyPR",
+		PrAuthor:     "u1",
+		ChangedFiles: []string{"migrations/001.sql", "docs/intro.md"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u3", "d1", "u2"}, ids(pr.Reviewers))
+	pool, ok := pr.PoolOf("d1")
+	assert.True(t, ok)
+	assert.Equal(t, 2, pool)
+	assert.False(t, pr.NeedMoreReviewers)
+}
+
+func TestPrService_UploadCodeOwners_UnknownOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(&entityTeam.Team{Id: 1, Name: "backend"}, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "ghost").Return(nil, repos.ErrNoUserWithId)
+
+	_, err := svc.UploadCodeOwners(context.Background(), dto.UploadCodeOwnersRequest{
+		TeamName: "backend",
+		Content:  "* @ghost\n",
+	})
+	assert.ErrorIs(t, err, application.ErrUnknownOwner)
+}
+
+func TestPrService_UploadCodeOwners(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(&entityTeam.Team{Id: 1, Name: "backend"}, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2"}, nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "docs").Return(&entityTeam.Team{Id: 2, Name: "docs"}, nil)
+	mockRepo.EXPECT().ReplaceCodeOwners(gomock.Any(), 1, gomock.Len(2)).Return(nil)
+
+	rules, err := svc.UploadCodeOwners(context.Background(), dto.UploadCodeOwnersRequest{
+		TeamName: "backend",
+		Content:  "* @u2\n/docs/ @org/docs @u2\n",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+}
@@ -0,0 +1,178 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/notify"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type failingDigestSender struct{}
+
+func (failingDigestSender) SendDigest(context.Context, entityNotify.Digest) error {
+	return errors.New("smtp server is down")
+}
+
+func newFakeSMTP(t *testing.T) (*notify.FakeSMTPServer, *notify.EmailNotifier) {
+	t.Helper()
+	srv, err := notify.NewFakeSMTPServer()
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = srv.Close() })
+	email, err := notify.NewEmailNotifier(config.EmailConfig{
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+		Username: "mailer",
+		Password: "secret",
+		From:     "pr-service@example.com",
+	})
+	assert.NoError(t, err)
+	return srv, email
+}
+
+func TestEmailNotifier_SendsAssignmentMails(t *testing.T) {
+	srv, email := newFakeSMTP(t)
+
+	err := email.Notify(context.Background(), entityNotify.Notification{
+		Kind: entityNotify.KindAssigned, PrID: "pr1", PrName: "Add search", AuthorID: "u1",
+		Recipients: []string{"u2", "u3"},
+		Emails:     map[string]string{"u2": "bob@example.com"},
+	})
+	assert.NoError(t, err)
+	// о мерже почта не пишет
+	err = email.Notify(context.Background(), entityNotify.Notification{
+		Kind: entityNotify.KindMerged, PrID: "pr1", Recipients: []string{"u2"},
+		Emails: map[string]string{"u2": "bob@example.com"},
+	})
+	assert.NoError(t, err)
+
+	mails := srv.Mails()
+	assert.Len(t, mails, 1)
+	assert.Equal(t, "pr-service@example.com", mails[0].From)
+	assert.Equal(t, []string{"bob@example.com"}, mails[0].To)
+	assert.Equal(t, "mailer", mails[0].Username)
+	assert.Contains(t, mails[0].Data, `Subject: Review requested: pr1 "Add search"`)
+	assert.Contains(t, mails[0].Data, `You were assigned to review pr1 "Add search" by u1.`)
+}
+
+func TestEmailNotifier_RejectsBadConfig(t *testing.T) {
+	_, err := notify.NewEmailNotifier(config.EmailConfig{From: "pr-service@example.com"})
+	assert.ErrorIs(t, err, notify.ErrInvalidConfig)
+
+	_, err = notify.NewEmailNotifier(config.EmailConfig{Host: "localhost", From: "PR Service"})
+	assert.ErrorIs(t, err, notify.ErrInvalidConfig)
+
+	_, err = notify.ParseDigestAt("9am")
+	assert.ErrorIs(t, err, notify.ErrInvalidConfig)
+
+	at, err := notify.ParseDigestAt("")
+	assert.NoError(t, err)
+	assert.Equal(t, 9*time.Hour, at)
+}
+
+func TestDigestJob_SendsOpenReviewsOldestFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	srv, email := newFakeSMTP(t)
+	job := application.NewDigestJob(mockRepo, email, 9*time.Hour, time.Minute, zap.NewNop())
+	ctx := context.Background()
+	day := time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC)
+
+	// до времени сводки БД не опрашивается
+	sent, err := job.SendDue(ctx, day.Add(8*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	newer := *prWithReviewers("pr-new", "u2")
+	newer.Name = "Fix login"
+	newer.CreatedAt = day.Add(-time.Hour)
+	older := *prWithReviewers("pr-old", "u2")
+	older.Name = "Add search"
+	older.CreatedAt = day.Add(-72 * time.Hour)
+
+	mockRepo.EXPECT().GetUsersWithEmail(gomock.Any()).Return([]entityUser.User{
+		{Id: "u2", Name: "Bob", Email: "bob@example.com"},
+		{Id: "u3", Name: "Carol", Email: "carol@example.com"},
+		{Id: "u4", Name: "Dave", Email: "dave@example.com"},
+	}, nil)
+	mockRepo.EXPECT().ClaimDigest(gomock.Any(), "u2", day).Return(true, nil)
+	mockRepo.EXPECT().GetUsersPr(gomock.Any(), "u2", true).Return([]entityPR.PullRequest{newer, older}, nil)
+	mockRepo.EXPECT().ClaimDigest(gomock.Any(), "u3", day).Return(true, nil)
+	mockRepo.EXPECT().GetUsersPr(gomock.Any(), "u3", true).Return(nil, nil)
+	// сводку u4 уже отправила другая реплика
+	mockRepo.EXPECT().ClaimDigest(gomock.Any(), "u4", day).Return(false, nil)
+
+	sent, err = job.SendDue(ctx, day.Add(9*time.Hour+30*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	mails := srv.Mails()
+	assert.Len(t, mails, 1)
+	assert.Equal(t, []string{"bob@example.com"}, mails[0].To)
+	assert.Contains(t, mails[0].Data, "Subject: Open reviews for 2025-12-03: 2")
+	oldIdx, newIdx := strings.Index(mails[0].Data, "pr-old"), strings.Index(mails[0].Data, "pr-new")
+	assert.True(t, oldIdx >= 0 && oldIdx < newIdx)
+	assert.Contains(t, mails[0].Data, `- pr-old "Add search" by u1, open since 2025-11-30`)
+
+	// за этот день всё разослано, повторный проход ничего не запрашивает
+	sent, err = job.SendDue(ctx, day.Add(10*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestDigestJob_ReleasesDigestWhenMailFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	job := application.NewDigestJob(mockRepo, failingDigestSender{}, 9*time.Hour, time.Minute, zap.NewNop())
+	day := time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().GetUsersWithEmail(gomock.Any()).Return([]entityUser.User{{Id: "u2", Email: "bob@example.com"}}, nil).Times(2)
+	mockRepo.EXPECT().ClaimDigest(gomock.Any(), "u2", day).Return(true, nil).Times(2)
+	mockRepo.EXPECT().GetUsersPr(gomock.Any(), "u2", true).Return([]entityPR.PullRequest{*prWithReviewers("pr1", "u2")}, nil).Times(2)
+	mockRepo.EXPECT().ReleaseDigest(gomock.Any(), "u2", day).Return(nil).Times(2)
+
+	// неудачная отправка повторяется на следующем проходе
+	for i := 0; i < 2; i++ {
+		sent, err := job.SendDue(context.Background(), day.Add(9*time.Hour+time.Duration(i)*time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+	}
+}
+
+func TestPrService_SetUserEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+	ctx := context.Background()
+
+	_, err := svc.SetUserEmail(ctx, "u2", "Bob <bob@example.com>")
+	assert.ErrorIs(t, err, application.ErrInvalidEmail)
+
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2"}, nil)
+	mockRepo.EXPECT().SetUserEmail(gomock.Any(), "u2", "bob@example.com").Return(nil)
+	user, err := svc.SetUserEmail(ctx, "u2", " bob@example.com ")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob@example.com", user.Email)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(nil, nil)
+	err = svc.AddTeam(ctx, &dto.AddTeamRequest{
+		TeamName: "team1",
+		Members:  []dto.MemberDto{{Id: "u2", Name: "Bob", IsActive: true, Email: "not-an-email"}},
+	})
+	assert.ErrorIs(t, err, application.ErrInvalidEmail)
+}
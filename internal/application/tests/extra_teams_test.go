@@ -0,0 +1,153 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPrService_CreatePR_ExtraTeams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	team := &entityTeam.Team{
+		Id:                1,
+		Name:              "backend",
+		RequiredReviewers: 1,
+		Users:             []entityUser.User{*author, {Id: "u2", IsActive: true}},
+	}
+	platform := &entityTeam.Team{
+		Id:    2,
+		Name:  "platform",
+		Users: []entityUser.User{{Id: "p1", IsActive: true}, {Id: "p2", IsActive: false}},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "backend", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(team, nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "platform").Return(platform, nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, p entityPR.PullRequest) error {
+			pool, ok := p.PoolOf("p1")
+			assert.True(t, ok)
+			assert.Equal(t, 2, pool)
+			pool, ok = p.PoolOf("u2")
+			assert.True(t, ok)
+			assert.Equal(t, 1, pool)
+			return nil
+		},
+	)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{
+		PrID:       "pr1",
+		PrName:     "MyPR",
+		PrAuthor:   "u1",
+		ExtraTeams: []dto.TeamQuota{{TeamName: "platform"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u2", "p1"}, ids(pr.Reviewers))
+	assert.False(t, pr.NeedMoreReviewers)
+}
+
+func TestPrService_CreatePR_ExtraTeamQuotaNotFilled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	team := &entityTeam.Team{
+		Id:                1,
+		Name:              "backend",
+		RequiredReviewers: 1,
+		Users:             []entityUser.User{*author, {Id: "u2", IsActive: true}},
+	}
+	platform := &entityTeam.Team{
+		Id:    2,
+		Name:  "platform",
+		Users: []entityUser.User{{Id: "p1", IsActive: true}},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "backend", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(team, nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "platform").Return(platform, nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).Return(nil)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{
+		PrID:       "pr1",
+		PrName:     "MyPR",
+		PrAuthor:   "u1",
+		ExtraTeams: []dto.TeamQuota{{TeamName: "platform", Reviewers: 2}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 2)
+	assert.True(t, pr.NeedMoreReviewers)
+}
+
+func TestPrService_CreatePR_ExtraTeamIsAuthorTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	team := &entityTeam.Team{Id: 1, Name: "backend", Users: []entityUser.User{*author}}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "backend", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(team, nil).Times(2)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{
+		PrID:       "pr1",
+		PrName:     "MyPR",
+		PrAuthor:   "u1",
+		ExtraTeams: []dto.TeamQuota{{TeamName: "backend"}},
+	})
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrInvalidExtraTeams)
+}
+
+func TestPrService_Reassign_FromReviewerPool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	author := entityUser.User{Id: "u1", TeamID: 1}
+	prObj := &entityPR.PullRequest{
+		Id:                "pr1",
+		Status:            "OPEN",
+		Author:            author,
+		Reviewers:         []entityUser.User{{Id: "u2"}, {Id: "p1"}},
+		ReviewerPools:     map[string]int{"u2": 1, "p1": 2},
+		NeedMoreReviewers: false,
+	}
+	platform := &entityTeam.Team{
+		Id:    2,
+		Users: []entityUser.User{{Id: "p1", IsActive: true}, {Id: "p2", IsActive: true}},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "p1").Return(&entityUser.User{Id: "p1", TeamID: 2}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 2).Return(platform, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).Return(nil)
+
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", "p1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "p2", newID)
+	assert.Equal(t, []string{"u2", "p2"}, ids(pr.Reviewers))
+	assert.False(t, pr.NeedMoreReviewers)
+}
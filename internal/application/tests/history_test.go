@@ -0,0 +1,89 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func TestAssignmentChanges_PairsSingleReplacement(t *testing.T) {
+	added, removed := entityPR.ReviewerDiff(*prWithReviewers("pr1", "u2", "u3"), *prWithReviewers("pr1", "u3", "u4"))
+	changes := entityPR.AssignmentChanges("pr1", added, removed, entityEvent.ReasonReassign, "admin")
+	assert.Len(t, changes, 2)
+	assert.Equal(t, entityPR.AssignmentReplaced, changes[0].Action)
+	assert.Equal(t, "u2", changes[0].ReviewerID)
+	assert.Equal(t, "u4", changes[0].ReplacedBy)
+	assert.Equal(t, entityPR.AssignmentAssigned, changes[1].Action)
+	assert.Equal(t, "u4", changes[1].ReviewerID)
+	assert.Equal(t, "admin", changes[1].Actor)
+
+	// PR закрыли - ревьюеров сняли без замены
+	added, removed = entityPR.ReviewerDiff(*prWithReviewers("pr1", "u2", "u3"), *prWithReviewers("pr1"))
+	changes = entityPR.AssignmentChanges("pr1", added, removed, entityEvent.ReasonClose, entityPR.SystemActor)
+	assert.Len(t, changes, 2)
+	for _, c := range changes {
+		assert.Equal(t, entityPR.AssignmentRemoved, c.Action)
+		assert.Empty(t, c.ReplacedBy)
+	}
+
+	assert.Empty(t, entityPR.AssignmentChanges("pr1", nil, nil, "", entityPR.SystemActor))
+}
+
+func TestPrService_GetPullRequestHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	history := []entityPR.Assignment{
+		{Id: 1, PrID: "pr1", ReviewerID: "u2", Action: entityPR.AssignmentAssigned, Reason: entityEvent.ReasonCreate, Actor: "admin"},
+		{Id: 2, PrID: "pr1", ReviewerID: "u2", Action: entityPR.AssignmentReplaced, ReplacedBy: "u3", Reason: entityEvent.ReasonStale, Actor: entityPR.SystemActor},
+		{Id: 3, PrID: "pr1", ReviewerID: "u3", Action: entityPR.AssignmentAssigned, Reason: entityEvent.ReasonStale, Actor: entityPR.SystemActor},
+	}
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prWithReviewers("pr1", "u3"), nil)
+	mockRepo.EXPECT().GetAssignments(gomock.Any(), "pr1").Return(history, nil)
+
+	got, err := svc.GetPullRequestHistory(context.Background(), "pr1")
+	assert.NoError(t, err)
+	assert.Equal(t, history, got)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "missing").Return(nil, repos.ErrPrNotFound)
+	_, err = svc.GetPullRequestHistory(context.Background(), "missing")
+	assert.ErrorIs(t, err, application.ErrPrNotFound)
+}
+
+func TestPrService_ReassignPullRequest_KeepsCallerReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	team := &entityTeam.Team{
+		Id:    1,
+		Users: []entityUser.User{{Id: "u1", IsActive: true}, {Id: "u2", IsActive: true}, {Id: "u3", IsActive: true}},
+	}
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil).Times(2)
+
+	var reasons []string
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, pr entityPR.PullRequest) error {
+			reasons = append(reasons, entityEvent.ReasonFrom(ctx))
+			assert.Equal(t, []string{"u3"}, ids(pr.Reviewers))
+			return nil
+		}).Times(2)
+
+	user := entityUser.User{Id: "u2", TeamID: 1}
+	moveCtx := entityEvent.WithReason(context.Background(), entityEvent.ReasonTeamMove)
+	assert.NoError(t, svc.ReassignPullRequest(moveCtx, *prWithReviewers("pr1", "u2"), user))
+	assert.NoError(t, svc.ReassignPullRequest(context.Background(), *prWithReviewers("pr1", "u2"), user))
+	assert.Equal(t, []string{entityEvent.ReasonTeamMove, entityEvent.ReasonDeactivate}, reasons)
+}
@@ -0,0 +1,112 @@
+package application_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/auth"
+	"github.com/go-openapi/testify/v2/assert"
+)
+
+var jwtSecret = []byte("test-secret")
+
+func TestJWTVerifier_HS256_Valid(t *testing.T) {
+	v, err := auth.NewHS256Verifier(jwtSecret)
+	assert.NoError(t, err)
+
+	token, err := auth.SignHS256(jwtSecret, auth.NewClaims("u1", []string{"admin"}, time.Hour))
+	assert.NoError(t, err)
+
+	p, err := v.Verify(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", p.UserID)
+	assert.True(t, p.IsAdmin())
+}
+
+func TestJWTVerifier_DefaultsToUserRole(t *testing.T) {
+	v, _ := auth.NewHS256Verifier(jwtSecret)
+	token, _ := auth.SignHS256(jwtSecret, auth.NewClaims("u1", []string{"superuser"}, time.Hour))
+
+	p, err := v.Verify(context.Background(), token)
+	assert.NoError(t, err)
+	assert.False(t, p.IsAdmin())
+	assert.True(t, p.HasRole(entityAuth.RoleUser))
+}
+
+func TestJWTVerifier_WrongSecret(t *testing.T) {
+	v, _ := auth.NewHS256Verifier(jwtSecret)
+	token, _ := auth.SignHS256([]byte("other"), auth.NewClaims("u1", nil, time.Hour))
+
+	_, err := v.Verify(context.Background(), token)
+	assert.ErrorIs(t, err, entityAuth.ErrInvalidToken)
+}
+
+func TestJWTVerifier_Expired(t *testing.T) {
+	v, _ := auth.NewHS256Verifier(jwtSecret)
+	token, _ := auth.SignHS256(jwtSecret, auth.NewClaims("u1", nil, -time.Minute))
+
+	_, err := v.Verify(context.Background(), token)
+	assert.ErrorIs(t, err, entityAuth.ErrTokenExpired)
+}
+
+func TestJWTVerifier_RejectsAlgNone(t *testing.T) {
+	v, _ := auth.NewHS256Verifier(jwtSecret)
+	token, _ := auth.SignHS256(jwtSecret, auth.NewClaims("u1", []string{"admin"}, time.Hour))
+	parts := strings.Split(token, ".")
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	_, err := v.Verify(context.Background(), none+"."+parts[1]+".")
+	assert.ErrorIs(t, err, entityAuth.ErrInvalidToken)
+}
+
+func TestJWTVerifier_Audience(t *testing.T) {
+	v, _ := auth.NewHS256Verifier(jwtSecret, auth.WithAudience("pr-service"), auth.WithIssuer("idp"))
+	claims := auth.NewClaims("u1", nil, time.Hour)
+	claims.Issuer = "idp"
+	claims.Audience = auth.Audience{"other"}
+	token, _ := auth.SignHS256(jwtSecret, claims)
+
+	_, err := v.Verify(context.Background(), token)
+	assert.ErrorIs(t, err, entityAuth.ErrInvalidToken)
+
+	claims.Audience = auth.Audience{"other", "pr-service"}
+	token, _ = auth.SignHS256(jwtSecret, claims)
+	_, err = v.Verify(context.Background(), token)
+	assert.NoError(t, err)
+}
+
+func TestJWTVerifier_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	v, err := auth.NewRS256Verifier(&key.PublicKey)
+	assert.NoError(t, err)
+
+	token, err := auth.SignRS256(key, auth.NewClaims("u2", []string{"user"}, time.Hour))
+	assert.NoError(t, err)
+	p, err := v.Verify(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, "u2", p.UserID)
+
+	// HS256 токен, подписанный открытым ключом как секретом, не должен проходить
+	hsToken, _ := auth.SignHS256(key.PublicKey.N.Bytes(), auth.NewClaims("u2", []string{"admin"}, time.Hour))
+	_, err = v.Verify(context.Background(), hsToken)
+	assert.ErrorIs(t, err, entityAuth.ErrInvalidToken)
+}
+
+func TestVerifierFromConfig_RejectsWeakSecret(t *testing.T) {
+	for _, secret := range []string{"", "change-me", "short-secret"} {
+		t.Setenv("JWT_SECRET", secret)
+		_, err := auth.NewVerifierFromConfig(config.AuthConfig{})
+		assert.ErrorIs(t, err, auth.ErrInvalidConfig)
+	}
+
+	_, err := auth.NewVerifierFromConfig(config.AuthConfig{Secret: strings.Repeat("k", 32)})
+	assert.NoError(t, err)
+}
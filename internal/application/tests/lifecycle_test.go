@@ -0,0 +1,198 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func lifecycleTeam() *entityTeam.Team {
+	return &entityTeam.Team{
+		Id:               1,
+		Name:             "team1",
+		ReviewerStrategy: entityTeam.StrategyRoundRobin,
+		Users: []entityUser.User{
+			{Id: "u1", TeamID: 1, IsActive: true},
+			{Id: "u2", TeamID: 1, IsActive: true},
+			{Id: "u3", TeamID: 1, IsActive: true},
+		},
+	}
+}
+
+func lifecycleService(ctrl *gomock.Controller) (*mock_interfaces.MockPullRequestRepo, interfaces.PrService) {
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo,
+		application.WithSelector(entityTeam.StrategyRoundRobin, application.NewRoundRobinSelector(mockRepo)))
+	mockRepo.EXPECT().RotateRoundRobin(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(rotateInMemory()).AnyTimes()
+	return mockRepo, svc
+}
+
+func TestPrService_CreatePR_DraftHasNoReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(lifecycleTeam(), nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, p entityPR.PullRequest) error {
+			assert.Equal(t, entityPR.StatusDraft, p.Status)
+			assert.Empty(t, p.Reviewers)
+			return nil
+		},
+	)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{PrID: "pr1", PrName: "MyPR", PrAuthor: "u1", Draft: true})
+	assert.NoError(t, err)
+	assert.Equal(t, entityPR.StatusDraft, pr.Status)
+	assert.False(t, pr.NeedMoreReviewers)
+}
+
+func TestPrService_Ready_AssignsReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(&entityPR.PullRequest{
+		Id:     "pr1",
+		Author: entityUser.User{Id: "u1", TeamID: 1},
+		Status: entityPR.StatusDraft,
+	}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(lifecycleTeam(), nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, p entityPR.PullRequest) error {
+			assert.Equal(t, entityPR.StatusOpen, p.Status)
+			assert.Len(t, p.Reviewers, 2)
+			return nil
+		},
+	)
+
+	pr, err := svc.Ready(context.Background(), dto.PullRequestLifecycleRequest{PrID: "pr1"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"u2", "u3"}, ids(pr.Reviewers))
+}
+
+func TestPrService_Ready_NotDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(&entityPR.PullRequest{
+		Id:     "pr1",
+		Status: entityPR.StatusOpen,
+	}, nil)
+
+	pr, err := svc.Ready(context.Background(), dto.PullRequestLifecycleRequest{PrID: "pr1"})
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrInvalidTransition)
+}
+
+func TestPrService_Close_FreesReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(&entityPR.PullRequest{
+		Id:                "pr1",
+		Author:            entityUser.User{Id: "u1", TeamID: 1},
+		Reviewers:         []entityUser.User{{Id: "u2"}},
+		NeedMoreReviewers: true,
+		NeedMoreReason:    entityPR.ReasonNotEnoughCandidates,
+		Status:            entityPR.StatusOpen,
+	}, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, p entityPR.PullRequest) error {
+			assert.Equal(t, entityPR.StatusClosed, p.Status)
+			assert.Empty(t, p.Reviewers)
+			return nil
+		},
+	)
+
+	pr, err := svc.Close(context.Background(), "pr1")
+	assert.NoError(t, err)
+	assert.False(t, pr.NeedMoreReviewers)
+	assert.Empty(t, pr.NeedMoreReason)
+}
+
+func TestPrService_Close_Merged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(&entityPR.PullRequest{
+		Id:     "pr1",
+		Status: entityPR.StatusMerged,
+	}, nil)
+
+	pr, err := svc.Close(context.Background(), "pr1")
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrInvalidTransition)
+}
+
+func TestPrService_Reopen_AssignsReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(&entityPR.PullRequest{
+		Id:     "pr1",
+		Author: entityUser.User{Id: "u1", TeamID: 1},
+		Status: entityPR.StatusClosed,
+	}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(lifecycleTeam(), nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).Return(nil)
+
+	pr, err := svc.Reopen(context.Background(), dto.PullRequestLifecycleRequest{PrID: "pr1"})
+	assert.NoError(t, err)
+	assert.Equal(t, entityPR.StatusOpen, pr.Status)
+	assert.ElementsMatch(t, []string{"u2", "u3"}, ids(pr.Reviewers))
+}
+
+func TestPrService_Merge_Draft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(&entityPR.PullRequest{
+		Id:     "pr1",
+		Status: entityPR.StatusDraft,
+	}, nil)
+
+	pr, err := svc.Merge(asAdmin(), "pr1")
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrInvalidTransition)
+}
+
+func TestPrService_Reassign_ClosedPr(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo, svc := lifecycleService(ctrl)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(&entityPR.PullRequest{
+		Id:     "pr1",
+		Status: entityPR.StatusClosed,
+	}, nil)
+
+	pr, _, err := svc.Reassign(context.Background(), "pr1", "u2", "")
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrPrNotOpen)
+}
+
+func TestStatus_Transitions(t *testing.T) {
+	assert.True(t, entityPR.StatusDraft.CanTransitionTo(entityPR.StatusOpen))
+	assert.True(t, entityPR.StatusClosed.CanTransitionTo(entityPR.StatusOpen))
+	assert.False(t, entityPR.StatusMerged.CanTransitionTo(entityPR.StatusOpen))
+	assert.False(t, entityPR.StatusDraft.CanTransitionTo(entityPR.StatusMerged))
+}
@@ -0,0 +1,133 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPrService_CreatePR_SkipsReviewersAtCapacity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	team := &entityTeam.Team{
+		Id:   1,
+		Name: "team1",
+		Users: []entityUser.User{
+			*author,
+			{Id: "u2", IsActive: true, MaxOpenReviews: 2},
+			{Id: "u3", IsActive: true},
+		},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(team, nil)
+	mockRepo.EXPECT().GetReviewLoad(gomock.Any(), []string{"u2"}).Return(map[string]entityUser.ReviewLoad{
+		"u2": {OpenReviews: 2},
+	}, nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).Return(nil)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{PrID: "pr1", PrName: "MyPR", PrAuthor: "u1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u3"}, ids(pr.Reviewers))
+	assert.True(t, pr.NeedMoreReviewers)
+	assert.Equal(t, entityPR.ReasonReviewersAtCapacity, pr.NeedMoreReason)
+}
+
+func TestPrService_CreatePR_NotEnoughCandidatesReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	team := &entityTeam.Team{
+		Id:    1,
+		Name:  "team1",
+		Users: []entityUser.User{*author, {Id: "u2", IsActive: true, MaxOpenReviews: 3}},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(team, nil)
+	mockRepo.EXPECT().GetReviewLoad(gomock.Any(), []string{"u2"}).Return(map[string]entityUser.ReviewLoad{
+		"u2": {OpenReviews: 1},
+	}, nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).Return(nil)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{PrID: "pr1", PrName: "MyPR", PrAuthor: "u1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, ids(pr.Reviewers))
+	assert.True(t, pr.NeedMoreReviewers)
+	assert.Equal(t, entityPR.ReasonNotEnoughCandidates, pr.NeedMoreReason)
+}
+
+func TestPrService_Reassign_PreferredCandidateAtCapacity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	prObj := &entityPR.PullRequest{
+		Id:        "pr1",
+		Status:    "OPEN",
+		Author:    entityUser.User{Id: "u1", TeamID: 1},
+		Reviewers: []entityUser.User{{Id: "u2"}},
+	}
+	team := &entityTeam.Team{
+		Id: 1,
+		Users: []entityUser.User{
+			{Id: "u2", IsActive: true},
+			{Id: "u3", IsActive: true, MaxOpenReviews: 1},
+		},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().GetReviewLoad(gomock.Any(), []string{"u3"}).Return(map[string]entityUser.ReviewLoad{
+		"u3": {OpenReviews: 1},
+	}, nil)
+
+	_, _, err := svc.Reassign(context.Background(), "pr1", "u2", "u3")
+	assert.ErrorIs(t, err, application.ErrInvalidCandidate)
+}
+
+func TestPrService_SetUserActive_StopsAtCapacity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	user := &entityUser.User{Id: "u2", TeamID: 1, IsActive: false, MaxOpenReviews: 2}
+	team := &entityTeam.Team{Id: 1, RequiredReviewers: 1, Users: []entityUser.User{{Id: "u1", IsActive: true}, {Id: "u2", IsActive: true}}}
+	prs := []entityPR.PullRequest{
+		{Id: "pr1", Status: "OPEN", Author: entityUser.User{Id: "u1"}, NeedMoreReviewers: true},
+		{Id: "pr2", Status: "OPEN", Author: entityUser.User{Id: "u1"}, NeedMoreReviewers: true},
+	}
+
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(user, nil)
+	mockRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().GetTeamPr(gomock.Any(), 1).Return(prs, nil)
+	mockRepo.EXPECT().GetReviewLoad(gomock.Any(), []string{"u2"}).Return(map[string]entityUser.ReviewLoad{
+		"u2": {OpenReviews: 1},
+	}, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).Return(nil)
+	mockRepo.EXPECT().AddReviewerToPR(gomock.Any(), "pr1", "u2").Return(nil)
+
+	assert.NoError(t, svc.SetUserActive(context.Background(), "u2", true))
+}
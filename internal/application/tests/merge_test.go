@@ -25,7 +25,7 @@ func TestPrService_Merge_PrNotFound(t *testing.T) {
 		GetPr(gomock.Any(), "pr1").
 		Return(nil, repos.ErrPrNotFound)
 
-	pr, err := svc.Merge(context.Background(), "user1", "pr1")
+	pr, err := svc.Merge(asUser("user1"), "pr1")
 	assert.Nil(t, pr)
 	assert.ErrorIs(t, err, application.ErrPrNotFound)
 }
@@ -47,7 +47,7 @@ func TestPrService_Merge_NotReviewer(t *testing.T) {
 		GetPr(gomock.Any(), "pr1").
 		Return(prObj, nil)
 
-	pr, err := svc.Merge(context.Background(), "user1", "pr1")
+	pr, err := svc.Merge(asUser("user1"), "pr1")
 	assert.Nil(t, pr)
 	assert.ErrorIs(t, err, application.ErrUnableToMerge)
 }
@@ -71,9 +71,9 @@ func TestPrService_Merge_PRAlreadyClosed(t *testing.T) {
 		GetPr(gomock.Any(), "pr1").
 		Return(prObj, nil)
 
-	pr, err := svc.Merge(context.Background(), "user1", "pr1")
+	pr, err := svc.Merge(asUser("user1"), "pr1")
 	assert.NoError(t, err)
-	assert.Equal(t, "MERGED", pr.Status)
+	assert.Equal(t, entityPR.StatusMerged, pr.Status)
 	assert.NotNil(t, pr.MergedAt)
 }
 func TestPrService_Merge_Success(t *testing.T) {
@@ -92,17 +92,23 @@ func TestPrService_Merge_Success(t *testing.T) {
 	mockRepo.EXPECT().
 		GetPr(gomock.Any(), "pr1").
 		Return(prObj, nil)
+	mockRepo.EXPECT().
+		GetTeam(gomock.Any(), 0).
+		Return(nil, repos.ErrTeamNotFound)
+	mockRepo.EXPECT().
+		GetReviews(gomock.Any(), "pr1").
+		Return([]entityPR.Review{{ReviewerID: "user1", Verdict: entityPR.VerdictApproved}}, nil)
 	mockRepo.EXPECT().
 		UpdatePr(gomock.Any(), "pr1", gomock.Any()).
 		DoAndReturn(func(_ context.Context, _ string, p entityPR.PullRequest) error {
-			assert.Equal(t, "MERGED", p.Status)
+			assert.Equal(t, entityPR.StatusMerged, p.Status)
 			assert.NotNil(t, p.MergedAt)
 			return nil
 		})
 
-	pr, err := svc.Merge(context.Background(), "user1", "pr1")
+	pr, err := svc.Merge(asUser("user1"), "pr1")
 	assert.NoError(t, err)
-	assert.Equal(t, "MERGED", pr.Status)
+	assert.Equal(t, entityPR.StatusMerged, pr.Status)
 	assert.NotNil(t, pr.MergedAt)
 }
 
@@ -122,16 +128,24 @@ func TestPrService_Merge_AdminCanMerge(t *testing.T) {
 	mockRepo.EXPECT().
 		GetPr(gomock.Any(), "pr1").
 		Return(prObj, nil)
+	mockRepo.EXPECT().
+		GetTeam(gomock.Any(), 0).
+		Return(nil, repos.ErrTeamNotFound)
+	mockRepo.EXPECT().
+		GetReviews(gomock.Any(), "pr1").
+		Return(nil, nil)
 	mockRepo.EXPECT().
 		UpdatePr(gomock.Any(), "pr1", gomock.Any()).
 		DoAndReturn(func(_ context.Context, _ string, p entityPR.PullRequest) error {
-			assert.Equal(t, "MERGED", p.Status)
+			assert.Equal(t, entityPR.StatusMerged, p.Status)
 			assert.NotNil(t, p.MergedAt)
 			return nil
 		})
 
-	pr, err := svc.Merge(context.Background(), "admin", "pr1")
+	pr, err := svc.Merge(asAdmin(), "pr1")
 	assert.NoError(t, err)
-	assert.Equal(t, "MERGED", pr.Status)
+	assert.Equal(t, entityPR.StatusMerged, pr.Status)
 	assert.NotNil(t, pr.MergedAt)
+	assert.True(t, pr.ReviewBypassed)
+	assert.Equal(t, "admin", pr.MergedBy)
 }
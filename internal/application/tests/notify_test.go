@@ -0,0 +1,166 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/notify"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(context.Context, entityNotify.Notification) error {
+	return errors.New("chat webhook responded with 500")
+}
+
+func notifyPr(reviewers ...string) entityPR.PullRequest {
+	return *prWithReviewers("pr1", reviewers...)
+}
+
+func TestNotifications_FromPrEvents(t *testing.T) {
+	created := notifyPr("u2", "u3")
+	created.Name = "Add search"
+	created.NeedMoreReviewers = true
+	ns := application.Notifications(entityEvent.NewPrEvent(entityEvent.PrCreated, created, ""))
+	assert.Len(t, ns, 2)
+	assert.Equal(t, entityNotify.KindAssigned, ns[0].Kind)
+	assert.Equal(t, []string{"u2", "u3"}, ns[0].Recipients)
+	assert.Equal(t, "Add search", ns[0].PrName)
+	assert.Equal(t, entityNotify.KindNeedMoreReviewers, ns[1].Kind)
+	assert.Equal(t, []string{"u1"}, ns[1].Recipients)
+
+	reassigned := application.Notifications(entityEvent.NewPrChange(notifyPr("u2", "u3"), notifyPr("u3", "u4"), entityEvent.ReasonReassign))
+	assert.Len(t, reassigned, 1)
+	assert.Equal(t, entityNotify.KindReassigned, reassigned[0].Kind)
+	assert.Equal(t, []string{"u4"}, reassigned[0].Recipients)
+	assert.Equal(t, []string{"u2"}, reassigned[0].Removed)
+
+	// ревьюера сняли при деактивации и замены не нашлось - назначать некого, но автору нужно знать
+	short := notifyPr()
+	short.NeedMoreReviewers = true
+	deactivated := application.Notifications(entityEvent.NewPrReviewersChanged(short, nil, []string{"u2"}, entityEvent.ReasonDeactivate))
+	assert.Len(t, deactivated, 1)
+	assert.Equal(t, entityNotify.KindNeedMoreReviewers, deactivated[0].Kind)
+
+	merged := notifyPr("u2")
+	merged.Status = entityPR.StatusMerged
+	merged.MergedBy = "u2"
+	ns = application.Notifications(entityEvent.NewPrChange(notifyPr("u2"), merged, ""))
+	assert.Len(t, ns, 1)
+	assert.Equal(t, entityNotify.KindMerged, ns[0].Kind)
+	assert.Equal(t, []string{"u1", "u2"}, ns[0].Recipients)
+
+	assert.Empty(t, application.Notifications(entityEvent.NewUserEvent(entityEvent.UserDeactivated, entityUser.User{Id: "u2"})))
+}
+
+func TestNotificationPublisher_SendsWithTeamName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	recorder := notify.NewRecorder()
+	publisher := application.NewNotificationPublisher(mockRepo, recorder, zap.NewNop())
+
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(&entityTeam.Team{Id: 1, Name: "backend"}, nil)
+	mockRepo.EXPECT().GetUserEmails(gomock.Any(), []string{"u2"}).Return(map[string]string{"u2": "bob@example.com"}, nil)
+
+	// события из outbox приходят с данными в JSON
+	e := entityEvent.NewPrEvent(entityEvent.PrCreated, notifyPr("u2"), "")
+	data, err := json.Marshal(e.Data)
+	assert.NoError(t, err)
+	e.Data = json.RawMessage(data)
+
+	assert.NoError(t, publisher.Publish(context.Background(), e))
+	// уведомления уходят не из Publish, а из очереди
+	assert.Empty(t, recorder.Notifications())
+	publisher.SendQueued(context.Background())
+	sent := recorder.Notifications()
+	assert.Len(t, sent, 1)
+	assert.Equal(t, "backend", sent[0].TeamName)
+	assert.Equal(t, []string{"u2"}, sent[0].Recipients)
+	assert.Equal(t, map[string]string{"u2": "bob@example.com"}, sent[0].Emails)
+}
+
+func TestNotificationPublisher_NotifierErrorDoesNotBlockOutbox(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	publisher := application.NewNotificationPublisher(mockRepo, failingNotifier{}, zap.NewNop())
+
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(&entityTeam.Team{Id: 1, Name: "backend"}, nil)
+	mockRepo.EXPECT().GetUserEmails(gomock.Any(), []string{"u2"}).Return(nil, errors.New("db is down"))
+
+	err := publisher.Publish(context.Background(), entityEvent.NewPrEvent(entityEvent.PrCreated, notifyPr("u2"), ""))
+	assert.NoError(t, err)
+	publisher.SendQueued(context.Background())
+}
+
+func TestSlackNotifier_UsesTeamChannelAndTemplates(t *testing.T) {
+	type message struct {
+		path string
+		body map[string]string
+	}
+	var got []message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		got = append(got, message{r.URL.Path, body})
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	slack, err := notify.NewSlackNotifier(srv.Client(), config.SlackConfig{
+		WebhookURL: srv.URL + "/default",
+		Username:   "PR Service",
+		Teams: map[string]config.SlackChannelConfig{
+			"backend":  {Channel: "#backend-reviews"},
+			"frontend": {WebhookURL: srv.URL + "/frontend"},
+		},
+		Templates: map[string]string{"merged": `:tada: {{.PrName}} merged by {{.MergedBy}}`},
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, slack.Notify(ctx, entityNotify.Notification{
+		Kind: entityNotify.KindAssigned, TeamName: "backend", PrID: "pr1", PrName: "Add search", AuthorID: "u1",
+		Recipients: []string{"u2", "u3"},
+	}))
+	assert.NoError(t, slack.Notify(ctx, entityNotify.Notification{
+		Kind: entityNotify.KindMerged, TeamName: "frontend", PrName: "Add search", MergedBy: "u2",
+	}))
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "/default", got[0].path)
+	assert.Equal(t, "#backend-reviews", got[0].body["channel"])
+	assert.Equal(t, "PR Service", got[0].body["username"])
+	assert.Equal(t, `u2, u3: you were assigned to review pr1 "Add search" by u1`, got[0].body["text"])
+	assert.Equal(t, "/frontend", got[1].path)
+	assert.Equal(t, ":tada: Add search merged by u2", got[1].body["text"])
+}
+
+func TestSlackNotifier_RejectsBadConfig(t *testing.T) {
+	_, err := notify.NewSlackNotifier(nil, config.SlackConfig{Templates: map[string]string{"approved": "x"}})
+	assert.ErrorIs(t, err, notify.ErrInvalidConfig)
+
+	_, err = notify.NewSlackNotifier(nil, config.SlackConfig{Templates: map[string]string{"merged": "{{.PrName"}})
+	assert.ErrorIs(t, err, notify.ErrInvalidConfig)
+
+	slack, err := notify.NewSlackNotifier(nil, config.SlackConfig{})
+	assert.NoError(t, err)
+	err = slack.Notify(context.Background(), entityNotify.Notification{Kind: entityNotify.KindMerged, TeamName: "backend"})
+	assert.ErrorIs(t, err, notify.ErrNoWebhook)
+}
@@ -0,0 +1,146 @@
+package application_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/events"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, entityEvent.Event) error {
+	return errors.New("subscriber is down")
+}
+
+func TestPrChange(t *testing.T) {
+	open := entityPR.PullRequest{Status: entityPR.StatusOpen, Reviewers: []entityUser.User{{Id: "u2"}, {Id: "u3"}}}
+
+	merged := open
+	merged.Status = entityPR.StatusMerged
+	assert.Equal(t, entityEvent.PrMerged, entityEvent.PrChange(open, merged))
+
+	swapped := open
+	swapped.Reviewers = []entityUser.User{{Id: "u3"}, {Id: "u4"}}
+	assert.Equal(t, entityEvent.PrReviewersChanged, entityEvent.PrChange(open, swapped))
+
+	reordered := open
+	reordered.Reviewers = []entityUser.User{{Id: "u3"}, {Id: "u2"}}
+	assert.Equal(t, entityEvent.PrUpdated, entityEvent.PrChange(open, reordered))
+
+	draft := entityPR.PullRequest{Status: entityPR.StatusDraft}
+	closed := entityPR.PullRequest{Status: entityPR.StatusClosed}
+	assert.Equal(t, entityEvent.PrStatusChanged, entityEvent.PrChange(draft, closed))
+}
+
+func TestOutboxRelay_DrainsFullBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	publisher := &recordingPublisher{}
+	relay := application.NewOutboxRelay(mockRepo, publisher, time.Second, zap.NewNop())
+
+	e := entityEvent.NewPrEvent(entityEvent.PrCreated, *reviewedPr(), "")
+	gomock.InOrder(
+		mockRepo.EXPECT().PublishOutbox(gomock.Any(), 100, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ int, publish func(context.Context, entityEvent.Event) error) (int, error) {
+				assert.NoError(t, publish(ctx, e))
+				return 100, nil
+			}),
+		mockRepo.EXPECT().PublishOutbox(gomock.Any(), 100, gomock.Any()).Return(3, nil),
+	)
+
+	assert.NoError(t, relay.Relay(context.Background()))
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, e.Id, publisher.events[0].Id)
+}
+
+func TestOutboxRelay_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	relay := application.NewOutboxRelay(mockRepo, &recordingPublisher{}, time.Second, zap.NewNop())
+
+	mockRepo.EXPECT().PublishOutbox(gomock.Any(), 100, gomock.Any()).Return(0, errors.New("db is down"))
+
+	assert.Error(t, relay.Relay(context.Background()))
+}
+
+func TestBus_PublishesToEverySubscriber(t *testing.T) {
+	first, second := &recordingPublisher{}, &recordingPublisher{}
+	bus := events.NewBus(first)
+	bus.Subscribe(failingPublisher{})
+	bus.Subscribe(second)
+
+	err := bus.Publish(context.Background(), entityEvent.NewPrEvent(entityEvent.PrMerged, *reviewedPr(), ""))
+	assert.Error(t, err)
+	assert.Len(t, first.events, 1)
+	assert.Len(t, second.events, 1)
+}
+
+func TestWriterPublisher_WritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := events.NewWriterPublisher(&buf)
+
+	assert.NoError(t, publisher.Publish(context.Background(), entityEvent.NewPrEvent(entityEvent.PrCreated, *reviewedPr(), "")))
+	assert.NoError(t, publisher.Publish(context.Background(), entityEvent.NewUserEvent(entityEvent.UserDeactivated, entityUser.User{Id: "u2", TeamID: 1})))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	var got struct {
+		Type        entityEvent.Type `json:"type"`
+		Aggregate   string           `json:"aggregate"`
+		AggregateID string           `json:"aggregate_id"`
+		TeamID      int              `json:"team_id"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	assert.Equal(t, entityEvent.UserDeactivated, got.Type)
+	assert.Equal(t, entityEvent.AggregateUser, got.Aggregate)
+	assert.Equal(t, "u2", got.AggregateID)
+	assert.Equal(t, 1, got.TeamID)
+}
+
+func TestPublisherFromConfig_FileKeepsBusSubscribers(t *testing.T) {
+	subscriber := &recordingPublisher{}
+	bus := events.NewBus(subscriber)
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	publisher, closePublisher, err := events.NewPublisherFromConfig(config.EventsConfig{Publisher: events.PublisherFile, Path: path}, bus)
+	assert.NoError(t, err)
+
+	assert.NoError(t, publisher.Publish(context.Background(), entityEvent.NewPrEvent(entityEvent.PrCreated, *reviewedPr(), "")))
+	assert.NoError(t, closePublisher())
+	assert.Len(t, subscriber.events, 1)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 1)
+}
+
+func TestNewAPIKeyEvent_OmitsSecrets(t *testing.T) {
+	e := entityEvent.NewAPIKeyEvent(entityEvent.APIKeyCreated, entityAuth.APIKey{
+		Id: 3, Name: "ci", Prefix: "prs_abcd", Hash: "deadbeef", Scopes: []entityAuth.Permission{entityAuth.PermTeamRead}, CreatedBy: "ops",
+	})
+	assert.Equal(t, entityEvent.AggregateAPIKey, e.Aggregate)
+	assert.Equal(t, "3", e.AggregateID)
+	assert.Equal(t, 0, e.TeamID)
+	data, err := json.Marshal(e.Data)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "deadbeef")
+	assert.NotContains(t, string(data), "prs_abcd")
+}
@@ -0,0 +1,139 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func asLead(userID string, teamID int) context.Context {
+	return entityAuth.WithPrincipal(context.Background(), entityAuth.Principal{
+		Subject: userID,
+		UserID:  userID,
+		Roles:   []entityAuth.Role{entityAuth.RoleUser},
+		LeadOf:  []int{teamID},
+	})
+}
+
+func TestPrincipal_Can(t *testing.T) {
+	lead := entityAuth.Principal{Roles: []entityAuth.Role{entityAuth.RoleUser}}.WithGrants([]entityAuth.Grant{
+		{Role: entityAuth.RoleTeamLead, TeamID: 1},
+	})
+	assert.True(t, lead.Can(entityAuth.PermTeamManage, 1))
+	assert.False(t, lead.Can(entityAuth.PermTeamManage, 2))
+	assert.False(t, lead.Can(entityAuth.PermTeamManage, 0))
+	assert.True(t, lead.CanAny(entityAuth.PermTeamManage))
+	assert.False(t, lead.CanAny(entityAuth.PermStatsRead))
+	assert.True(t, lead.Can(entityAuth.PermPrReview, 2))
+
+	user := entityAuth.Principal{Roles: []entityAuth.Role{entityAuth.RoleUser}}
+	assert.False(t, user.CanAny(entityAuth.PermPrCreate))
+
+	admin := user.WithGrants([]entityAuth.Grant{{Role: entityAuth.RoleAdmin}})
+	assert.True(t, admin.Can(entityAuth.PermRolesManage, 0))
+	assert.True(t, admin.Can(entityAuth.PermTeamManage, 2))
+}
+
+func TestPolicyService_LeadManagesOwnTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	team := &entityTeam.Team{Id: 1, Name: "team1", ReviewerStrategy: entityTeam.StrategyRandom}
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(team, nil).Times(2)
+	mockRepo.EXPECT().UpdateTeamSettings(gomock.Any(), gomock.Any()).Return(nil)
+
+	updated, err := svc.UpdateTeamSettings(asLead("lead", 1), dto.TeamSettingsRequest{TeamName: "team1", RequiredReviewers: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, updated.RequiredReviewers)
+}
+
+func TestPolicyService_LeadForbiddenInOtherTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 2, IsActive: true}, nil)
+
+	err := svc.SetUserActive(asLead("lead", 1), "u2", false)
+	assert.ErrorIs(t, err, application.ErrForbidden)
+}
+
+func TestPolicyService_UserForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(&entityTeam.Team{Id: 1, Name: "team1"}, nil)
+
+	_, err := svc.UpdateTeamSettings(asUser("u1"), dto.TeamSettingsRequest{TeamName: "team1"})
+	assert.ErrorIs(t, err, application.ErrForbidden)
+
+	_, _, err = svc.GetStatistics(asLead("lead", 1))
+	assert.ErrorIs(t, err, application.ErrForbidden)
+
+	_, err = svc.GrantRole(context.Background(), dto.RoleRequest{UserID: "u1", Role: "admin"})
+	assert.ErrorIs(t, err, application.ErrUnauthenticated)
+}
+
+func TestPolicyService_AddTeamOnlyAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	req := &dto.AddTeamRequest{TeamName: "team2", Members: []dto.MemberDto{{Id: "u2", Name: "user2", IsActive: true}}}
+
+	// тимлид может управлять своей командой, но не переносить пользователей в новую
+	assert.ErrorIs(t, svc.AddTeam(asLead("lead", 1), req), application.ErrForbidden)
+	assert.ErrorIs(t, svc.AddTeam(context.Background(), req), application.ErrUnauthenticated)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team2").Return(nil, repos.ErrTeamNotFound)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(nil, repos.ErrNoUserWithId)
+	mockRepo.EXPECT().AddTeam(gomock.Any(), gomock.Any()).Return(nil)
+	assert.NoError(t, svc.AddTeam(asAdmin(), req))
+}
+
+func TestPolicyService_AdminGrantsTeamLead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	grant := entityAuth.Grant{Role: entityAuth.RoleTeamLead, TeamID: 1, TeamName: "team1"}
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&entityUser.User{Id: "u1", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(&entityTeam.Team{Id: 1, Name: "team1"}, nil)
+	mockRepo.EXPECT().GrantRole(gomock.Any(), "u1", grant).Return(nil)
+	mockRepo.EXPECT().GetUserGrants(gomock.Any(), "u1").Return([]entityAuth.Grant{grant}, nil)
+
+	grants, err := svc.GrantRole(asAdmin(), dto.RoleRequest{UserID: "u1", Role: "team_lead", TeamName: "team1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []entityAuth.Grant{grant}, grants)
+}
+
+func TestPrService_GrantRole_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&entityUser.User{Id: "u1", TeamID: 1}, nil).Times(2)
+
+	_, err := svc.GrantRole(asAdmin(), dto.RoleRequest{UserID: "u1", Role: "team_lead"})
+	assert.ErrorIs(t, err, application.ErrInvalidRole)
+
+	_, err = svc.GrantRole(asAdmin(), dto.RoleRequest{UserID: "u1", Role: "admin", TeamName: "team1"})
+	assert.ErrorIs(t, err, application.ErrInvalidRole)
+}
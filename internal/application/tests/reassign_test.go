@@ -22,7 +22,7 @@ func TestPrService_Reassign_PrNotFound(t *testing.T) {
 
 	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
 
-	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1")
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1", "")
 	assert.Nil(t, pr)
 	assert.Empty(t, newID)
 	assert.ErrorIs(t, err, application.ErrPrNotFound)
@@ -43,7 +43,7 @@ func TestPrService_Reassign_PrMerged(t *testing.T) {
 
 	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
 
-	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1")
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1", "")
 	assert.Nil(t, pr)
 	assert.Empty(t, newID)
 	assert.ErrorIs(t, err, application.ErrPrIsMerged)
@@ -64,7 +64,7 @@ func TestPrService_Reassign_NotAssigned(t *testing.T) {
 
 	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
 
-	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1")
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1", "")
 	assert.Nil(t, pr)
 	assert.Empty(t, newID)
 	assert.ErrorIs(t, err, application.ErrNotAssigned)
@@ -88,10 +88,10 @@ func TestPrService_Reassign_NoCandidates(t *testing.T) {
 	}
 
 	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
-	mockRepo.EXPECT().GetUserByID(gomock.Any(), "author1").Return(&prObj.Author, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "user1").Return(&entityUser.User{Id: "user1", TeamID: 1}, nil)
 	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
 
-	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1")
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1", "")
 	assert.Nil(t, pr)
 	assert.Empty(t, newID)
 	assert.ErrorIs(t, err, application.ErrNoCandidate)
@@ -104,7 +104,7 @@ func TestPrService_Reassign_Success(t *testing.T) {
 	svc := application.NewPrService(mockRepo)
 
 	author := entityUser.User{Id: "author1", TeamID: 1}
-	oldReviewer := entityUser.User{Id: "user1"}
+	oldReviewer := entityUser.User{Id: "user1", TeamID: 1}
 	newReviewer := entityUser.User{Id: "user2", IsActive: true}
 
 	prObj := &entityPR.PullRequest{
@@ -120,7 +120,7 @@ func TestPrService_Reassign_Success(t *testing.T) {
 	}
 
 	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
-	mockRepo.EXPECT().GetUserByID(gomock.Any(), "author1").Return(&author, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "user1").Return(&oldReviewer, nil)
 	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
 	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
 		func(_ context.Context, _ string, p entityPR.PullRequest) error {
@@ -130,8 +130,102 @@ func TestPrService_Reassign_Success(t *testing.T) {
 		},
 	)
 
-	pr, newID, err := svc.Reassign(context.Background(), "pr1", oldReviewer.Id)
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", oldReviewer.Id, "")
 	assert.NoError(t, err)
 	assert.NotNil(t, pr)
 	assert.Equal(t, newReviewer.Id, newID)
 }
+
+func TestPrService_Reassign_UsesOldReviewerTeamAndSkipsAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	prObj := &entityPR.PullRequest{
+		Id:        "pr1",
+		Status:    "OPEN",
+		Reviewers: []entityUser.User{{Id: "user1"}, {Id: "user2"}},
+		Author:    entityUser.User{Id: "author1", TeamID: 1},
+	}
+	oldReviewerTeam := &entityTeam.Team{
+		Id: 2,
+		Users: []entityUser.User{
+			{Id: "user1", IsActive: true},
+			{Id: "user2", IsActive: true},
+			{Id: "author1", IsActive: true},
+			{Id: "user3", IsActive: true},
+		},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "user1").Return(&entityUser.User{Id: "user1", TeamID: 2}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 2).Return(oldReviewerTeam, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).Return(nil)
+
+	_, newID, err := svc.Reassign(context.Background(), "pr1", "user1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "user3", newID)
+}
+
+func TestPrService_Reassign_PreferredCandidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	prObj := &entityPR.PullRequest{
+		Id:        "pr1",
+		Status:    "OPEN",
+		Reviewers: []entityUser.User{{Id: "user1"}},
+		Author:    entityUser.User{Id: "author1", TeamID: 1},
+	}
+	team := &entityTeam.Team{
+		Id: 1,
+		Users: []entityUser.User{
+			{Id: "user1", IsActive: true},
+			{Id: "user2", IsActive: true},
+			{Id: "user3", IsActive: true},
+		},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "user1").Return(&entityUser.User{Id: "user1", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).Return(nil)
+
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1", "user3")
+	assert.NoError(t, err)
+	assert.Equal(t, "user3", newID)
+	assert.Len(t, pr.Reviewers, 1)
+}
+
+func TestPrService_Reassign_PreferredCandidateIsAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	prObj := &entityPR.PullRequest{
+		Id:        "pr1",
+		Status:    "OPEN",
+		Reviewers: []entityUser.User{{Id: "user1"}},
+		Author:    entityUser.User{Id: "author1", TeamID: 1},
+	}
+	team := &entityTeam.Team{
+		Id: 1,
+		Users: []entityUser.User{
+			{Id: "user1", IsActive: true},
+			{Id: "author1", IsActive: true},
+		},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "user1").Return(&entityUser.User{Id: "user1", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1", "author1")
+	assert.Nil(t, pr)
+	assert.Empty(t, newID)
+	assert.ErrorIs(t, err, application.ErrInvalidCandidate)
+}
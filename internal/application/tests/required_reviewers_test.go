@@ -0,0 +1,108 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPrService_CreatePR_RequiredReviewers(t *testing.T) {
+	cases := []struct {
+		name     string
+		required int
+		wantLen  int
+		wantMore bool
+	}{
+		{name: "single reviewer", required: 1, wantLen: 1, wantMore: false},
+		{name: "default", required: 0, wantLen: 2, wantMore: false},
+		{name: "three reviewers", required: 3, wantLen: 3, wantMore: false},
+		{name: "not enough candidates", required: 4, wantLen: 3, wantMore: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+			svc := application.NewPrService(mockRepo)
+
+			author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+			team := &entityTeam.Team{
+				Id:                1,
+				Name:              "team1",
+				RequiredReviewers: tc.required,
+				Users: []entityUser.User{
+					*author,
+					{Id: "u2", IsActive: true},
+					{Id: "u3", IsActive: true},
+					{Id: "u4", IsActive: true},
+					{Id: "u5", IsActive: false},
+				},
+			}
+
+			mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+			mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+			mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(team, nil)
+			mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).Return(nil)
+
+			pr, err := svc.CreatePR(context.Background(), dto.CreatePR{PrID: "pr1", PrName: "MyPR", PrAuthor: "u1"})
+			assert.NoError(t, err)
+			assert.Len(t, pr.Reviewers, tc.wantLen)
+			assert.Equal(t, tc.wantMore, pr.NeedMoreReviewers)
+		})
+	}
+}
+
+func TestPrService_Reassign_RequiredReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	author := entityUser.User{Id: "author1", TeamID: 1}
+	prObj := &entityPR.PullRequest{
+		Id:        "pr1",
+		Status:    "OPEN",
+		Reviewers: []entityUser.User{{Id: "user1"}},
+		Author:    author,
+	}
+	team := &entityTeam.Team{
+		Id:                1,
+		RequiredReviewers: 1,
+		Users:             []entityUser.User{{Id: "user1", IsActive: true}, {Id: "user2", IsActive: true}},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prObj, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "user1").Return(&entityUser.User{Id: "user1", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).Return(nil)
+
+	pr, newID, err := svc.Reassign(context.Background(), "pr1", "user1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "user2", newID)
+	assert.False(t, pr.NeedMoreReviewers)
+}
+
+func TestPrService_AddTeam_InvalidRequiredReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(nil, repos.ErrTeamNotFound)
+
+	err := svc.AddTeam(context.Background(), &dto.AddTeamRequest{
+		TeamName:          "team1",
+		RequiredReviewers: -1,
+		Members:           []dto.MemberDto{{Id: "u1", Name: "Alice", IsActive: true}},
+	})
+	assert.ErrorIs(t, err, application.ErrInvalidRequiredReviewers)
+}
@@ -0,0 +1,135 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func reviewedPr() *entityPR.PullRequest {
+	return &entityPR.PullRequest{
+		Id:        "pr1",
+		Author:    entityUser.User{Id: "u1", TeamID: 1},
+		Reviewers: []entityUser.User{{Id: "u2"}, {Id: "u3"}},
+		Status:    entityPR.StatusOpen,
+	}
+}
+
+func TestPrService_SubmitReview_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(reviewedPr(), nil)
+	mockRepo.EXPECT().AddReview(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, r entityPR.Review) (int, error) {
+			assert.Equal(t, "u2", r.ReviewerID)
+			assert.Equal(t, entityPR.VerdictApproved, r.Verdict)
+			return 7, nil
+		},
+	)
+
+	review, err := svc.SubmitReview(asUser("u2"), dto.ReviewRequest{PrID: "pr1", Verdict: "APPROVED"})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, review.Id)
+}
+
+func TestPrService_SubmitReview_InvalidVerdict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	review, err := svc.SubmitReview(asUser("u2"), dto.ReviewRequest{PrID: "pr1", Verdict: "LGTM"})
+	assert.Nil(t, review)
+	assert.ErrorIs(t, err, application.ErrInvalidVerdict)
+}
+
+func TestPrService_SubmitReview_NotAssigned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(reviewedPr(), nil)
+
+	review, err := svc.SubmitReview(asUser("u1"), dto.ReviewRequest{PrID: "pr1", Verdict: "APPROVED"})
+	assert.Nil(t, review)
+	assert.ErrorIs(t, err, application.ErrNotAssigned)
+}
+
+func TestPrService_Merge_NotEnoughApprovals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(reviewedPr(), nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(&entityTeam.Team{Id: 1, RequiredApprovals: 2}, nil)
+	mockRepo.EXPECT().GetReviews(gomock.Any(), "pr1").Return([]entityPR.Review{
+		{ReviewerID: "u2", Verdict: entityPR.VerdictApproved},
+		{ReviewerID: "u3", Verdict: entityPR.VerdictCommented},
+	}, nil)
+
+	pr, err := svc.Merge(asUser("u2"), "pr1")
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrNotEnoughApprovals)
+}
+
+func TestPrService_Merge_ChangesRequested(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(reviewedPr(), nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(&entityTeam.Team{Id: 1}, nil)
+	mockRepo.EXPECT().GetReviews(gomock.Any(), "pr1").Return([]entityPR.Review{
+		{ReviewerID: "u2", Verdict: entityPR.VerdictApproved},
+		{ReviewerID: "u3", Verdict: entityPR.VerdictChangesRequested},
+		{ReviewerID: "u3", Verdict: entityPR.VerdictCommented},
+	}, nil)
+
+	pr, err := svc.Merge(asUser("u2"), "pr1")
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrChangesRequested)
+}
+
+func TestPrService_Merge_ApprovalReplacesChangeRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(reviewedPr(), nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(&entityTeam.Team{Id: 1}, nil)
+	mockRepo.EXPECT().GetReviews(gomock.Any(), "pr1").Return([]entityPR.Review{
+		{ReviewerID: "u3", Verdict: entityPR.VerdictChangesRequested},
+		{ReviewerID: "u3", Verdict: entityPR.VerdictApproved},
+	}, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).Return(nil)
+
+	pr, err := svc.Merge(asUser("u2"), "pr1")
+	assert.NoError(t, err)
+	assert.Equal(t, entityPR.StatusMerged, pr.Status)
+	assert.Equal(t, "u2", pr.MergedBy)
+	assert.False(t, pr.ReviewBypassed)
+}
+
+func TestSummarize_IgnoresUnassignedReviewers(t *testing.T) {
+	state := entityPR.Summarize([]entityPR.Review{
+		{ReviewerID: "u9", Verdict: entityPR.VerdictChangesRequested},
+		{ReviewerID: "u2", Verdict: entityPR.VerdictApproved},
+	}, []string{"u2"})
+	assert.Equal(t, 1, state.Approvals)
+	assert.False(t, state.ChangesRequested)
+}
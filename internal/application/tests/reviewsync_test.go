@@ -0,0 +1,213 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/codehost"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func syncedLink() entityHost.Link {
+	return entityHost.Link{
+		PrID:            "github-1296269-42",
+		Provider:        entityHost.ProviderGitHub,
+		RepoID:          1296269,
+		Repo:            "acme/backend",
+		Number:          42,
+		Status:          entityHost.SyncPending,
+		Version:         3,
+		SyncedReviewers: []string{"u2", "u3"},
+	}
+}
+
+func prWithReviewers(id string, reviewers ...string) *entityPR.PullRequest {
+	pr := &entityPR.PullRequest{Id: id, Status: entityPR.StatusOpen, Author: entityUser.User{Id: "u1", TeamID: 1}}
+	for _, r := range reviewers {
+		pr.Reviewers = append(pr.Reviewers, entityUser.User{Id: r})
+	}
+	return pr
+}
+
+func TestReviewerDiff(t *testing.T) {
+	add, remove := entityHost.ReviewerDiff([]string{"u2", "u3"}, []string{"u3", "u4"})
+	assert.Equal(t, []string{"u4"}, add)
+	assert.Equal(t, []string{"u2"}, remove)
+
+	add, remove = entityHost.ReviewerDiff(nil, nil)
+	assert.Empty(t, add)
+	assert.Empty(t, remove)
+}
+
+func TestReviewerSyncPublisher_MarksOnlyReviewerChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	publisher := application.NewReviewerSyncPublisher(mockRepo)
+
+	pr := *prWithReviewers("pr1", "u2")
+	mockRepo.EXPECT().MarkReviewerSyncPending(gomock.Any(), "pr1", gomock.Any()).Return(nil).Times(2)
+
+	ctx := context.Background()
+	assert.NoError(t, publisher.Publish(ctx, entityEvent.NewPrEvent(entityEvent.PrCreated, pr, "")))
+	assert.NoError(t, publisher.Publish(ctx, entityEvent.NewPrEvent(entityEvent.PrReviewersChanged, pr, "")))
+	assert.NoError(t, publisher.Publish(ctx, entityEvent.NewPrEvent(entityEvent.PrMerged, pr, "")))
+	assert.NoError(t, publisher.Publish(ctx, entityEvent.NewUserEvent(entityEvent.UserUpdated, entityUser.User{Id: "u2"})))
+}
+
+func TestReviewerSyncJob_RequestsNewAndRemovesReplaced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	client := codehost.NewFakeClient()
+	job := application.NewReviewerSyncJob(mockRepo, map[entityHost.Provider]interfaces.CodeHostClient{
+		entityHost.ProviderGitHub: client,
+	}, time.Second, zap.NewNop())
+
+	now := time.Date(2025, 12, 2, 12, 0, 0, 0, time.UTC)
+	link := syncedLink()
+	mockRepo.EXPECT().ClaimDueReviewerSyncs(gomock.Any(), now, gomock.Any(), gomock.Any()).Return([]entityHost.Link{link}, nil)
+	mockRepo.EXPECT().GetPr(gomock.Any(), link.PrID).Return(prWithReviewers(link.PrID, "u3", "u4", "u5"), nil)
+	mockRepo.EXPECT().GetCodeHostLogins(gomock.Any(), entityHost.ProviderGitHub, gomock.Any()).
+		Return(map[string]string{"u2": "octo-bob", "u3": "octo-carol", "u4": "octo-dave"}, nil)
+	mockRepo.EXPECT().UpdateReviewerSync(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, l entityHost.Link) error {
+		assert.Equal(t, entityHost.SyncSynced, l.Status)
+		assert.Equal(t, 3, l.Version)
+		assert.Equal(t, []string{"u3", "u4"}, l.SyncedReviewers)
+		assert.Equal(t, "reviewers without github login: u5", l.LastError)
+		assert.Equal(t, now, *l.SyncedAt)
+		return nil
+	})
+
+	assert.NoError(t, job.SyncDue(context.Background(), now))
+	assert.Equal(t, []codehost.FakeCall{
+		{Method: "remove", PrID: link.PrID, Logins: []string{"octo-bob"}},
+		{Method: "request", PrID: link.PrID, Logins: []string{"octo-dave"}},
+	}, client.Calls())
+	assert.Equal(t, []string{"octo-dave"}, client.Reviewers(link.PrID))
+}
+
+func TestReviewerSyncJob_RetriesWithBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	client := codehost.NewFakeClient()
+	client.Err = errors.New("provider responded with 502 Bad Gateway")
+	job := application.NewReviewerSyncJob(mockRepo, map[entityHost.Provider]interfaces.CodeHostClient{
+		entityHost.ProviderGitHub: client,
+	}, time.Second, zap.NewNop())
+
+	now := time.Date(2025, 12, 2, 12, 0, 0, 0, time.UTC)
+	link := syncedLink()
+	link.Attempts = 1
+	mockRepo.EXPECT().ClaimDueReviewerSyncs(gomock.Any(), now, gomock.Any(), gomock.Any()).Return([]entityHost.Link{link}, nil)
+	mockRepo.EXPECT().GetPr(gomock.Any(), link.PrID).Return(prWithReviewers(link.PrID, "u2", "u3", "u4"), nil)
+	mockRepo.EXPECT().GetCodeHostLogins(gomock.Any(), entityHost.ProviderGitHub, gomock.Any()).
+		Return(map[string]string{"u2": "octo-bob", "u3": "octo-carol", "u4": "octo-dave"}, nil)
+	mockRepo.EXPECT().UpdateReviewerSync(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, l entityHost.Link) error {
+		assert.Equal(t, entityHost.SyncPending, l.Status)
+		assert.Equal(t, 2, l.Attempts)
+		assert.Equal(t, now.Add(time.Minute), l.NextAttemptAt)
+		assert.Equal(t, []string{"u2", "u3"}, l.SyncedReviewers)
+		assert.Contains(t, l.LastError, "502")
+		return nil
+	})
+
+	assert.NoError(t, job.SyncDue(context.Background(), now))
+}
+
+func TestReviewerSyncJob_NoClientFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	job := application.NewReviewerSyncJob(mockRepo, nil, time.Second, zap.NewNop())
+
+	now := time.Date(2025, 12, 2, 12, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().ClaimDueReviewerSyncs(gomock.Any(), now, gomock.Any(), gomock.Any()).Return([]entityHost.Link{syncedLink()}, nil)
+	mockRepo.EXPECT().UpdateReviewerSync(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, l entityHost.Link) error {
+		assert.Equal(t, entityHost.SyncFailed, l.Status)
+		assert.Equal(t, "no API token configured for github", l.LastError)
+		return nil
+	})
+
+	assert.NoError(t, job.SyncDue(context.Background(), now))
+}
+
+func TestGitHubClient_RequestsAndRemovesReviewers(t *testing.T) {
+	type call struct {
+		method, path, auth string
+		reviewers          []string
+	}
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		calls = append(calls, call{r.Method, r.URL.Path, r.Header.Get("Authorization"), body.Reviewers})
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := codehost.NewGitHubClient(srv.Client(), srv.URL, "gh-token")
+	link := syncedLink()
+	assert.NoError(t, client.RequestReviewers(context.Background(), link, []string{"octo-dave"}))
+	assert.NoError(t, client.RemoveReviewers(context.Background(), link, []string{"octo-bob"}))
+
+	path := "/repos/acme/backend/pulls/42/requested_reviewers"
+	assert.Equal(t, []call{
+		{http.MethodPost, path, "Bearer gh-token", []string{"octo-dave"}},
+		{http.MethodDelete, path, "Bearer gh-token", []string{"octo-bob"}},
+	}, calls)
+
+	link.Repo = ""
+	assert.ErrorIs(t, client.RequestReviewers(context.Background(), link, []string{"octo-dave"}), codehost.ErrUnknownRepo)
+}
+
+func TestGitLabClient_ReplacesReviewerIDs(t *testing.T) {
+	var put []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gl-token", r.Header.Get("PRIVATE-TOKEN"))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/278964/merge_requests/7":
+			_, _ = w.Write([]byte(`{"iid": 7, "reviewers": [{"id": 11, "username": "bob"}, {"id": 12, "username": "carol"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/users":
+			assert.Equal(t, "dave", r.URL.Query().Get("username"))
+			_, _ = w.Write([]byte(`[{"id": 13, "username": "dave"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/projects/278964/merge_requests/7":
+			var body struct {
+				ReviewerIDs []int64 `json:"reviewer_ids"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			put = body.ReviewerIDs
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := codehost.NewGitLabClient(srv.Client(), srv.URL, "gl-token")
+	link := entityHost.Link{PrID: "gitlab-278964-7", Provider: entityHost.ProviderGitLab, RepoID: 278964, Number: 7}
+
+	assert.NoError(t, client.RequestReviewers(context.Background(), link, []string{"dave", "carol"}))
+	assert.Equal(t, []int64{11, 12, 13}, put)
+
+	assert.NoError(t, client.RemoveReviewers(context.Background(), link, []string{"bob"}))
+	assert.Equal(t, []int64{12}, put)
+}
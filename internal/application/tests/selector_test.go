@@ -0,0 +1,141 @@
+package application_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func ids(users []entityUser.User) []string {
+	res := make([]string, 0, len(users))
+	for _, u := range users {
+		res = append(res, u.Id)
+	}
+	return res
+}
+
+// rotateInMemory - RotateRoundRobin без БД: последний выбранный хранится в замыкании
+func rotateInMemory() func(context.Context, int, func(string) (string, error)) error {
+	last := make(map[int]string)
+	return func(_ context.Context, teamID int, next func(string) (string, error)) error {
+		selected, err := next(last[teamID])
+		if err != nil {
+			return err
+		}
+		last[teamID] = selected
+		return nil
+	}
+}
+
+func TestRoundRobinSelector_Cycles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	mockRepo.EXPECT().RotateRoundRobin(gomock.Any(), 1, gomock.Any()).DoAndReturn(rotateInMemory()).Times(3)
+	sel := application.NewRoundRobinSelector(mockRepo)
+	team := entityTeam.Team{Id: 1}
+	candidates := []entityUser.User{{Id: "u3"}, {Id: "u1"}, {Id: "u2"}}
+
+	first, err := sel.Select(context.Background(), team, candidates, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, ids(first))
+
+	second, err := sel.Select(context.Background(), team, candidates, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u3", "u1"}, ids(second))
+
+	// очередь хранится в репозитории: другой экземпляр (реплика, перезапуск) продолжает её
+	third, err := application.NewRoundRobinSelector(mockRepo).Select(context.Background(), team, candidates, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u2", "u3"}, ids(third))
+}
+
+func TestRandomSelector_Seeded(t *testing.T) {
+	candidates := []entityUser.User{{Id: "u1"}, {Id: "u2"}, {Id: "u3"}, {Id: "u4"}}
+
+	a, err := application.NewRandomSelector(rand.New(rand.NewSource(42))).Select(context.Background(), entityTeam.Team{}, candidates, 2)
+	assert.NoError(t, err)
+	b, err := application.NewRandomSelector(rand.New(rand.NewSource(42))).Select(context.Background(), entityTeam.Team{}, candidates, 2)
+	assert.NoError(t, err)
+	assert.Len(t, a, 2)
+	assert.Equal(t, ids(a), ids(b))
+}
+
+func TestLeastLoadedSelector_PicksLeastBusy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+
+	mockRepo.EXPECT().GetReviewLoad(gomock.Any(), []string{"u1", "u2", "u3"}).Return(map[string]entityUser.ReviewLoad{
+		"u1": {OpenReviews: 2},
+		"u2": {OpenReviews: 1},
+	}, nil)
+
+	sel := application.NewLeastLoadedSelector(mockRepo)
+	candidates := []entityUser.User{{Id: "u1"}, {Id: "u2"}, {Id: "u3"}}
+	selected, err := sel.Select(context.Background(), entityTeam.Team{}, candidates, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u3", "u2"}, ids(selected))
+}
+
+func TestLeastLoadedSelector_TieBrokenByLastAssignment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+
+	older := time.Now().Add(-48 * time.Hour)
+	newer := time.Now().Add(-time.Hour)
+	mockRepo.EXPECT().GetReviewLoad(gomock.Any(), gomock.Any()).Return(map[string]entityUser.ReviewLoad{
+		"u1": {OpenReviews: 1, LastAssignedAt: &newer},
+		"u2": {OpenReviews: 1, LastAssignedAt: &older},
+		"u3": {OpenReviews: 0, LastAssignedAt: &newer},
+	}, nil)
+
+	sel := application.NewLeastLoadedSelector(mockRepo)
+	candidates := []entityUser.User{{Id: "u1"}, {Id: "u2"}, {Id: "u3"}}
+	selected, err := sel.Select(context.Background(), entityTeam.Team{}, candidates, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u3", "u2"}, ids(selected))
+}
+
+func TestPrService_CreatePR_UsesTeamStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo,
+		application.WithSelector(entityTeam.StrategyRoundRobin, application.NewRoundRobinSelector(mockRepo)))
+	mockRepo.EXPECT().RotateRoundRobin(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(rotateInMemory()).AnyTimes()
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	team := &entityTeam.Team{
+		Id:               1,
+		Name:             "team1",
+		ReviewerStrategy: entityTeam.StrategyRoundRobin,
+		Users: []entityUser.User{
+			*author,
+			{Id: "u4", IsActive: true},
+			{Id: "u2", IsActive: true},
+			{Id: "u3", IsActive: true},
+		},
+	}
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(team, nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).Return(nil)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{PrID: "pr1", PrName: "MyPR", PrAuthor: "u1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u2", "u3"}, ids(pr.Reviewers))
+	assert.False(t, pr.NeedMoreReviewers)
+}
@@ -0,0 +1,188 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+var slaNow = time.Date(2025, 12, 4, 12, 0, 0, 0, time.UTC)
+
+func breach(action string, assignedHoursAgo int) entityPR.SLABreach {
+	return entityPR.SLABreach{
+		PrID:       "pr1",
+		AuthorID:   "u1",
+		TeamID:     1,
+		ReviewerID: "u2",
+		AssignedAt: slaNow.Add(-time.Duration(assignedHoursAgo) * time.Hour),
+		SLA:        entityTeam.SLA{ReviewHours: 24, EscalateHours: 48, Action: action},
+	}
+}
+
+func TestSLABreach_Deadlines(t *testing.T) {
+	b := breach(entityTeam.SLAActionEscalate, 30)
+	assert.Equal(t, slaNow.Add(-6*time.Hour), b.Deadline())
+	assert.True(t, b.NeedsReminder())
+	assert.False(t, b.NeedsEscalation(slaNow))
+	assert.True(t, b.NeedsEscalation(slaNow.Add(18*time.Hour)))
+
+	b.SLA.EscalateHours = 0
+	assert.True(t, b.EscalationDeadline().IsZero())
+	assert.False(t, b.NeedsEscalation(slaNow.Add(100*time.Hour)))
+}
+
+func TestPrService_CheckReviewSLA_RemindsOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	fresh := breach(entityTeam.SLAActionEscalate, 30)
+	reminded := breach(entityTeam.SLAActionEscalate, 30)
+	reminded.ReviewerID = "u3"
+	reminded.RemindedAt = &slaNow
+
+	mockRepo.EXPECT().GetSLABreaches(gomock.Any(), slaNow, nil).Return([]entityPR.SLABreach{fresh, reminded}, nil)
+	mockRepo.EXPECT().MarkReviewOverdue(gomock.Any(), fresh, entityEvent.StageReminder, nil, slaNow).Return(true, nil)
+
+	assert.NoError(t, svc.CheckReviewSLA(context.Background(), slaNow))
+}
+
+func TestPrService_CheckReviewSLA_EscalatesToLeads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	b := breach(entityTeam.SLAActionEscalate, 50)
+	b.RemindedAt = &slaNow
+
+	mockRepo.EXPECT().GetSLABreaches(gomock.Any(), slaNow, nil).Return([]entityPR.SLABreach{b}, nil)
+	mockRepo.EXPECT().GetTeamLeads(gomock.Any(), 1).Return([]string{"lead"}, nil)
+	mockRepo.EXPECT().MarkReviewOverdue(gomock.Any(), b, entityEvent.StageEscalation, []string{"lead"}, slaNow).Return(true, nil)
+
+	assert.NoError(t, svc.CheckReviewSLA(context.Background(), slaNow))
+}
+
+func TestPrService_CheckReviewSLA_ReassignsOverdueReviewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	b := breach(entityTeam.SLAActionReassign, 50)
+	team := &entityTeam.Team{
+		Id:    1,
+		Users: []entityUser.User{{Id: "u1", IsActive: true}, {Id: "u2", IsActive: true}, {Id: "u3", IsActive: true}},
+	}
+
+	mockRepo.EXPECT().GetSLABreaches(gomock.Any(), slaNow, nil).Return([]entityPR.SLABreach{b}, nil)
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prWithReviewers("pr1", "u2"), nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, pr entityPR.PullRequest) error {
+			assert.Equal(t, entityEvent.ReasonSLA, entityEvent.ReasonFrom(ctx))
+			assert.Equal(t, []string{"u3"}, ids(pr.Reviewers))
+			return nil
+		})
+
+	assert.NoError(t, svc.CheckReviewSLA(context.Background(), slaNow))
+}
+
+func TestPrService_CheckReviewSLA_NoCandidateFallsBackToEscalation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	b := breach(entityTeam.SLAActionReassign, 50)
+	team := &entityTeam.Team{Id: 1, Users: []entityUser.User{{Id: "u1", IsActive: true}, {Id: "u2", IsActive: true}}}
+
+	mockRepo.EXPECT().GetSLABreaches(gomock.Any(), slaNow, nil).Return([]entityPR.SLABreach{b}, nil)
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prWithReviewers("pr1", "u2"), nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().MarkReviewOverdue(gomock.Any(), b, entityEvent.StageReminder, nil, slaNow).Return(true, nil)
+	mockRepo.EXPECT().GetTeamLeads(gomock.Any(), 1).Return([]string{"lead"}, nil)
+	mockRepo.EXPECT().MarkReviewOverdue(gomock.Any(), b, entityEvent.StageEscalation, []string{"lead"}, slaNow).Return(true, nil)
+
+	assert.NoError(t, svc.CheckReviewSLA(context.Background(), slaNow))
+}
+
+func TestNotifications_FromReviewOverdue(t *testing.T) {
+	b := breach(entityTeam.SLAActionEscalate, 50)
+	e := entityEvent.NewReviewOverdue(notifyPr("u2"), b, entityEvent.StageEscalation, []string{"lead"})
+	data, err := json.Marshal(e.Data)
+	assert.NoError(t, err)
+	e.Data = json.RawMessage(data)
+
+	ns := application.Notifications(e)
+	assert.Len(t, ns, 1)
+	assert.Equal(t, entityNotify.KindReviewEscalated, ns[0].Kind)
+	assert.Equal(t, []string{"lead"}, ns[0].Recipients)
+	assert.Equal(t, "u2", ns[0].ReviewerID)
+	assert.True(t, b.AssignedAt.Equal(ns[0].AssignedAt))
+
+	ns = application.Notifications(entityEvent.NewReviewOverdue(notifyPr("u2"), b, entityEvent.StageReminder, nil))
+	assert.Len(t, ns, 1)
+	assert.Equal(t, entityNotify.KindReviewReminder, ns[0].Kind)
+	assert.Equal(t, []string{"u2"}, ns[0].Recipients)
+}
+
+func TestPrService_UpdateTeamSettings_InvalidSLA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(&entityTeam.Team{Id: 1, Name: "backend"}, nil).Times(2)
+
+	_, err := svc.UpdateTeamSettings(context.Background(), dto.TeamSettingsRequest{
+		TeamName: "backend",
+		SLA:      &dto.TeamSLA{ReviewHours: 24, EscalateHours: 12},
+	})
+	assert.ErrorIs(t, err, application.ErrInvalidSLA)
+
+	_, err = svc.UpdateTeamSettings(context.Background(), dto.TeamSettingsRequest{
+		TeamName: "backend",
+		SLA:      &dto.TeamSLA{ReviewHours: 24, Action: "ping"},
+	})
+	assert.ErrorIs(t, err, application.ErrInvalidSLA)
+}
+
+func TestPolicyService_GetSLABreaches_Visibility(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u5").Return(&entityUser.User{Id: "u5", TeamID: 2}, nil).Times(2)
+	// участник видит только просрочки своей команды
+	own, other := breach(entityTeam.SLAActionEscalate, 30), breach(entityTeam.SLAActionEscalate, 30)
+	own.TeamID = 2
+	mockRepo.EXPECT().GetSLABreaches(gomock.Any(), gomock.Any(), nil).Return([]entityPR.SLABreach{own, other}, nil)
+	breaches, err := svc.GetSLABreaches(asUser("u5"), "")
+	assert.NoError(t, err)
+	assert.Equal(t, []entityPR.SLABreach{own}, breaches)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(&entityTeam.Team{Id: 1, Name: "backend"}, nil)
+	_, err = svc.GetSLABreaches(asUser("u5"), "backend")
+	assert.ErrorIs(t, err, application.ErrForbidden)
+
+	mockRepo.EXPECT().GetSLABreaches(gomock.Any(), gomock.Any(), nil).Return(nil, nil)
+	_, err = svc.GetSLABreaches(asAdmin(), "")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,116 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func runExclusively(ctx context.Context, _ int64, fn func(context.Context) error) (bool, error) {
+	return true, fn(ctx)
+}
+
+func TestStaleReviewJob_ReplacesReviewerWithoutReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	job := application.NewStaleReviewJob(mockRepo, application.NewPrService(mockRepo), time.Minute, zap.NewNop())
+
+	now := time.Date(2025, 12, 5, 12, 0, 0, 0, time.UTC)
+	team := &entityTeam.Team{
+		Id:               1,
+		StaleReviewHours: 24,
+		Users:            []entityUser.User{{Id: "u1", IsActive: true}, {Id: "u2", IsActive: true}, {Id: "u3", IsActive: true}},
+	}
+
+	mockRepo.EXPECT().RunExclusive(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(runExclusively)
+	mockRepo.EXPECT().GetStaleReviews(gomock.Any(), now).Return([]entityPR.StaleReview{
+		{PrID: "pr1", TeamID: 1, ReviewerID: "u2", AssignedAt: now.Add(-25 * time.Hour)},
+		// PR смержили после выборки
+		{PrID: "pr2", TeamID: 1, ReviewerID: "u2", AssignedAt: now.Add(-30 * time.Hour)},
+	}, nil)
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prWithReviewers("pr1", "u2"), nil)
+	merged := prWithReviewers("pr2", "u2")
+	merged.Status = entityPR.StatusMerged
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr2").Return(merged, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, pr entityPR.PullRequest) error {
+			assert.Equal(t, entityEvent.ReasonStale, entityEvent.ReasonFrom(ctx))
+			assert.Equal(t, []string{"u3"}, ids(pr.Reviewers))
+			return nil
+		})
+
+	replaced, err := job.ReassignDue(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, replaced)
+}
+
+func TestStaleReviewJob_SkipsWhenAnotherReplicaHoldsLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	job := application.NewStaleReviewJob(mockRepo, application.NewPrService(mockRepo), time.Minute, zap.NewNop())
+
+	// GetStaleReviews не вызывается
+	mockRepo.EXPECT().RunExclusive(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+
+	replaced, err := job.ReassignDue(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, replaced)
+}
+
+func TestPrService_ReassignStaleReviews_NoCandidateKeepsReviewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	now := time.Date(2025, 12, 5, 12, 0, 0, 0, time.UTC)
+	team := &entityTeam.Team{Id: 1, Users: []entityUser.User{{Id: "u1", IsActive: true}, {Id: "u2", IsActive: true}}}
+
+	mockRepo.EXPECT().GetStaleReviews(gomock.Any(), now).Return([]entityPR.StaleReview{{PrID: "pr1", TeamID: 1, ReviewerID: "u2"}}, nil)
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(prWithReviewers("pr1", "u2"), nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&entityUser.User{Id: "u2", TeamID: 1}, nil)
+	mockRepo.EXPECT().GetTeam(gomock.Any(), 1).Return(team, nil)
+
+	replaced, err := svc.ReassignStaleReviews(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, replaced)
+}
+
+func TestPrService_UpdateTeamSettings_StaleReviewHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "backend").Return(&entityTeam.Team{Id: 1, Name: "backend", StaleReviewHours: 24}, nil).Times(3)
+
+	negative := -1
+	_, err := svc.UpdateTeamSettings(context.Background(), dto.TeamSettingsRequest{TeamName: "backend", StaleReviewHours: &negative})
+	assert.ErrorIs(t, err, application.ErrInvalidStaleReviewHours)
+
+	// без поля настройка не меняется, 0 выключает замену
+	mockRepo.EXPECT().UpdateTeamSettings(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	team, err := svc.UpdateTeamSettings(context.Background(), dto.TeamSettingsRequest{TeamName: "backend"})
+	assert.NoError(t, err)
+	assert.Equal(t, 24, team.StaleReviewHours)
+
+	off := 0
+	team, err = svc.UpdateTeamSettings(context.Background(), dto.TeamSettingsRequest{TeamName: "backend", StaleReviewHours: &off})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, team.StaleReviewHours)
+}
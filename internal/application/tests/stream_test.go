@@ -0,0 +1,133 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/events"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func seqEvent(seq int64, t entityEvent.Type) entityEvent.Event {
+	pr := entityPR.PullRequest{Id: "pr1", Author: entityUser.User{Id: "u1", TeamID: 1}, Reviewers: []entityUser.User{{Id: "u2"}}}
+	e := entityEvent.NewPrEvent(t, pr, "")
+	e.Seq = seq
+	return e
+}
+
+func TestFilter_MatchesTeamUserAndType(t *testing.T) {
+	e := seqEvent(1, entityEvent.PrReviewersChanged)
+	assert.True(t, entityEvent.Filter{}.Matches(e))
+	assert.True(t, entityEvent.Filter{TeamIDs: []int{1}, UserID: "u2"}.Matches(e))
+	assert.False(t, entityEvent.Filter{TeamIDs: []int{2}}.Matches(e))
+	assert.False(t, entityEvent.Filter{UserID: "u3"}.Matches(e))
+	assert.False(t, entityEvent.Filter{Types: []entityEvent.Type{entityEvent.PrMerged}}.Matches(e))
+
+	// события из outbox приходят с данными в JSON
+	data, err := json.Marshal(e.Data)
+	assert.NoError(t, err)
+	e.Data = json.RawMessage(data)
+	assert.True(t, entityEvent.Filter{UserID: "u1"}.Matches(e))
+	assert.True(t, entityEvent.Filter{UserID: "u2"}.Matches(e))
+
+	u := entityEvent.NewUserEvent(entityEvent.UserDeactivated, entityUser.User{Id: "u3", TeamID: 1})
+	assert.True(t, entityEvent.Filter{UserID: "u3"}.Matches(u))
+}
+
+func TestStream_ResumesFromLastEventID(t *testing.T) {
+	stream := events.NewStream(3)
+	ctx := context.Background()
+	for seq := int64(1); seq <= 4; seq++ {
+		assert.NoError(t, stream.Publish(ctx, seqEvent(seq, entityEvent.PrCreated)))
+	}
+	// повтор того же события после ошибки другого подписчика отбрасывается
+	assert.NoError(t, stream.Publish(ctx, seqEvent(4, entityEvent.PrCreated)))
+
+	backlog, found, _, cancel := stream.Subscribe(2)
+	cancel()
+	assert.True(t, found)
+	assert.Len(t, backlog, 2)
+	assert.Equal(t, int64(3), backlog[0].Seq)
+	assert.Equal(t, int64(4), backlog[1].Seq)
+
+	// событие 1 уже вытеснено из журнала
+	backlog, found, _, cancel = stream.Subscribe(1)
+	cancel()
+	assert.False(t, found)
+	assert.Empty(t, backlog)
+}
+
+func TestStream_DeliversLiveAndDropsSlowSubscriber(t *testing.T) {
+	stream := events.NewStream(10)
+	ctx := context.Background()
+
+	_, found, ch, cancel := stream.Subscribe(0)
+	defer cancel()
+	assert.True(t, found)
+	assert.NoError(t, stream.Publish(ctx, seqEvent(1, entityEvent.PrMerged)))
+	e := <-ch
+	assert.Equal(t, entityEvent.PrMerged, e.Type)
+
+	for seq := int64(2); seq <= 100; seq++ {
+		assert.NoError(t, stream.Publish(ctx, seqEvent(seq, entityEvent.PrCreated)))
+	}
+	open := true
+	for open {
+		_, open = <-ch
+	}
+	assert.False(t, open)
+}
+
+func TestPolicyService_EventFilter_MemberSeesOwnTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&entityUser.User{Id: "u1", TeamID: 1}, nil).AnyTimes()
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team2").Return(&entityTeam.Team{Id: 2, Name: "team2"}, nil)
+
+	f, err := svc.EventFilter(asUser("u1"), dto.EventStreamRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, f.TeamIDs)
+	assert.Contains(t, f.Types, entityEvent.PrReviewersChanged)
+
+	_, err = svc.EventFilter(asUser("u1"), dto.EventStreamRequest{TeamName: "team2"})
+	assert.ErrorIs(t, err, application.ErrForbidden)
+
+	_, err = svc.EventFilter(asUser("u1"), dto.EventStreamRequest{Types: []string{"pr.unknown"}})
+	assert.ErrorIs(t, err, application.ErrInvalidEventType)
+}
+
+func TestPolicyService_EventFilter_LeadAndAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPolicyService(application.NewPrService(mockRepo), mockRepo)
+
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "lead").Return(&entityUser.User{Id: "lead", TeamID: 1}, nil).AnyTimes()
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u5").Return(&entityUser.User{Id: "u5", TeamID: 2}, nil).AnyTimes()
+
+	f, err := svc.EventFilter(asLead("lead", 2), dto.EventStreamRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, f.TeamIDs)
+
+	f, err = svc.EventFilter(asLead("lead", 2), dto.EventStreamRequest{UserID: "u5", Types: []string{"pr.reviewed"}})
+	assert.NoError(t, err)
+	assert.Nil(t, f.TeamIDs)
+	assert.Equal(t, "u5", f.UserID)
+	assert.Equal(t, []entityEvent.Type{entityEvent.PrReviewed}, f.Types)
+
+	f, err = svc.EventFilter(asAdmin(), dto.EventStreamRequest{})
+	assert.NoError(t, err)
+	assert.Nil(t, f.TeamIDs)
+}
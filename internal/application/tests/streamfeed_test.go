@@ -0,0 +1,91 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/events"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func seqs(es []entityEvent.Event) []int64 {
+	out := make([]int64, 0, len(es))
+	for _, e := range es {
+		out = append(out, e.Seq)
+	}
+	return out
+}
+
+func TestStreamFeed_StartsFromRecentOutbox(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	stream := events.NewStream(10)
+	feed := application.NewStreamFeed(mockRepo, stream, 2, time.Second, zap.NewNop())
+	now := time.Now()
+
+	mockRepo.EXPECT().GetLastOutboxSeq(gomock.Any()).Return(int64(5), nil)
+	mockRepo.EXPECT().GetOutboxEvents(gomock.Any(), int64(3), gomock.Any()).
+		Return([]entityEvent.Event{{Seq: 4}, {Seq: 5}}, nil)
+	assert.NoError(t, feed.Poll(context.Background(), now))
+
+	mockRepo.EXPECT().GetOutboxEvents(gomock.Any(), int64(5), gomock.Any()).
+		Return([]entityEvent.Event{{Seq: 6}}, nil)
+	assert.NoError(t, feed.Poll(context.Background(), now))
+
+	backlog, found, _, cancel := stream.Subscribe(4)
+	defer cancel()
+	assert.True(t, found)
+	assert.Equal(t, []int64{5, 6}, seqs(backlog))
+}
+
+func TestStreamFeed_FillsGapsFromLateCommits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	stream := events.NewStream(10)
+	feed := application.NewStreamFeed(mockRepo, stream, 10, time.Second, zap.NewNop())
+	now := time.Now()
+
+	// 2 закоммитилась позже 3
+	mockRepo.EXPECT().GetLastOutboxSeq(gomock.Any()).Return(int64(0), nil)
+	mockRepo.EXPECT().GetOutboxEvents(gomock.Any(), int64(0), gomock.Any()).
+		Return([]entityEvent.Event{{Seq: 1}, {Seq: 3}}, nil)
+	assert.NoError(t, feed.Poll(context.Background(), now))
+
+	mockRepo.EXPECT().GetOutboxEvents(gomock.Any(), int64(1), gomock.Any()).
+		Return([]entityEvent.Event{{Seq: 2}, {Seq: 3}, {Seq: 4}}, nil)
+	assert.NoError(t, feed.Poll(context.Background(), now))
+
+	// пропуск больше не ждём
+	mockRepo.EXPECT().GetOutboxEvents(gomock.Any(), int64(4), gomock.Any()).Return(nil, nil)
+	assert.NoError(t, feed.Poll(context.Background(), now))
+
+	backlog, found, _, cancel := stream.Subscribe(1)
+	defer cancel()
+	assert.True(t, found)
+	assert.Equal(t, []int64{3, 2, 4}, seqs(backlog))
+}
+
+func TestStreamFeed_ForgetsGapAfterTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	feed := application.NewStreamFeed(mockRepo, events.NewStream(10), 10, time.Second, zap.NewNop())
+	now := time.Now()
+
+	mockRepo.EXPECT().GetLastOutboxSeq(gomock.Any()).Return(int64(0), nil)
+	mockRepo.EXPECT().GetOutboxEvents(gomock.Any(), int64(0), gomock.Any()).
+		Return([]entityEvent.Event{{Seq: 1}, {Seq: 3}}, nil)
+	assert.NoError(t, feed.Poll(context.Background(), now))
+
+	// транзакция с номером 2 откатилась
+	mockRepo.EXPECT().GetOutboxEvents(gomock.Any(), int64(3), gomock.Any()).Return(nil, nil)
+	assert.NoError(t, feed.Poll(context.Background(), now.Add(2*time.Minute)))
+}
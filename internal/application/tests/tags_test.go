@@ -0,0 +1,125 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func tagsTeam(author *entityUser.User) *entityTeam.Team {
+	return &entityTeam.Team{
+		Id:               1,
+		Name:             "team1",
+		ReviewerStrategy: entityTeam.StrategyRoundRobin,
+		Users: []entityUser.User{
+			*author,
+			{Id: "u2", IsActive: true},
+			{Id: "u3", IsActive: true},
+			{Id: "u4", IsActive: true},
+			{Id: "u5", IsActive: false},
+		},
+	}
+}
+
+func TestPrService_CreatePR_PrefersTaggedReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo,
+		application.WithSelector(entityTeam.StrategyRoundRobin, application.NewRoundRobinSelector(mockRepo)))
+	mockRepo.EXPECT().RotateRoundRobin(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(rotateInMemory()).AnyTimes()
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(tagsTeam(author), nil)
+	mockRepo.EXPECT().GetUserTags(gomock.Any(), []string{"u2", "u3", "u4"}).Return(map[string][]string{
+		"u3": {"go"},
+		"u4": {"go", "sql"},
+	}, nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, p entityPR.PullRequest) error {
+			assert.Equal(t, []string{"go", "sql"}, p.RequiredTags)
+			return nil
+		},
+	)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{
+		PrID:         "pr1",
+		PrName:       "MyPR",
+		PrAuthor:     "u1",
+		RequiredTags: []string{"SQL", "go", "go"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u4", "u3"}, ids(pr.Reviewers))
+	assert.False(t, pr.NeedMoreReviewers)
+}
+
+func TestPrService_CreatePR_NoTaggedCandidateFallsBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo,
+		application.WithSelector(entityTeam.StrategyRoundRobin, application.NewRoundRobinSelector(mockRepo)))
+	mockRepo.EXPECT().RotateRoundRobin(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(rotateInMemory()).AnyTimes()
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(tagsTeam(author), nil)
+	mockRepo.EXPECT().GetUserTags(gomock.Any(), gomock.Any()).Return(map[string][]string{}, nil)
+	mockRepo.EXPECT().AddPR(gomock.Any(), gomock.Any()).Return(nil)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{
+		PrID:         "pr1",
+		PrName:       "MyPR",
+		PrAuthor:     "u1",
+		RequiredTags: []string{"frontend"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u2", "u3"}, ids(pr.Reviewers))
+}
+
+func TestPrService_CreatePR_InvalidTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	author := &entityUser.User{Id: "u1", TeamID: 1, IsActive: true}
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(nil, repos.ErrPrNotFound)
+	mockRepo.EXPECT().GetUserWithTeam(gomock.Any(), "u1").Return(author, "team1", nil)
+	mockRepo.EXPECT().GetTeamByName(gomock.Any(), "team1").Return(tagsTeam(author), nil)
+
+	pr, err := svc.CreatePR(context.Background(), dto.CreatePR{
+		PrID:         "pr1",
+		PrName:       "MyPR",
+		PrAuthor:     "u1",
+		RequiredTags: []string{" "},
+	})
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, application.ErrInvalidTags)
+}
+
+func TestPrService_SetUserTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&entityUser.User{Id: "u1"}, nil)
+	mockRepo.EXPECT().SetUserTags(gomock.Any(), "u1", []string{"frontend", "go"}).Return(nil)
+
+	user, err := svc.SetUserTags(context.Background(), dto.UserTagsRequest{UserID: "u1", Tags: []string{"Go", "frontend"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"frontend", "go"}, user.Tags)
+}
@@ -0,0 +1,197 @@
+package application_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	mock_interfaces "github.com/JanArsMAI/PullRequestService/internal/domain/interfaces/mocks"
+	entityPR "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/webhook"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type recordingPublisher struct {
+	events []entityEvent.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e entityEvent.Event) error {
+	p.events = append(p.events, e)
+	return nil
+}
+
+type fakeSender struct {
+	sent []int
+	fail map[int]bool
+}
+
+func (s *fakeSender) Send(_ context.Context, d entityHook.Delivery) (int, error) {
+	s.sent = append(s.sent, d.Id)
+	if s.fail[d.Id] {
+		return http.StatusBadGateway, errors.New("subscriber responded with 502")
+	}
+	return http.StatusOK, nil
+}
+
+func TestSubscription_Matches(t *testing.T) {
+	e := entityEvent.Event{Type: entityEvent.PrMerged, TeamID: 1}
+
+	assert.True(t, entityHook.Subscription{}.Matches(e))
+	assert.True(t, entityHook.Subscription{TeamID: 1, Events: []entityEvent.Type{entityEvent.PrMerged}}.Matches(e))
+	assert.False(t, entityHook.Subscription{TeamID: 2}.Matches(e))
+	assert.False(t, entityHook.Subscription{Events: []entityEvent.Type{entityEvent.PrCreated}}.Matches(e))
+}
+
+func TestDelivery_BackoffAndFailure(t *testing.T) {
+	assert.Equal(t, 30*time.Second, entityHook.Backoff(1))
+	assert.Equal(t, time.Minute, entityHook.Backoff(2))
+	assert.Equal(t, 4*time.Minute, entityHook.Backoff(4))
+	assert.Equal(t, time.Hour, entityHook.Backoff(20))
+
+	now := time.Date(2025, 11, 29, 12, 0, 0, 0, time.UTC)
+	d := entityHook.Delivery{Status: entityHook.DeliveryPending}
+	d.Failed(now, 500, "boom")
+	assert.Equal(t, entityHook.DeliveryPending, d.Status)
+	assert.Equal(t, now.Add(30*time.Second), d.NextAttemptAt)
+
+	for d.Status == entityHook.DeliveryPending {
+		d.Failed(now, 500, "boom")
+	}
+	assert.Equal(t, entityHook.DeliveryFailed, d.Status)
+	assert.Equal(t, entityHook.MaxAttempts, d.Attempts)
+
+	d.Redeliver(now)
+	assert.Equal(t, entityHook.DeliveryPending, d.Status)
+	assert.Equal(t, 0, d.Attempts)
+}
+
+func TestWebhookPublisher_FansOutToMatchingSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	publisher := application.NewWebhookPublisher(mockRepo)
+
+	mockRepo.EXPECT().GetWebhooks(gomock.Any()).Return([]entityHook.Subscription{
+		{Id: 1},
+		{Id: 2, TeamID: 2},
+		{Id: 3, TeamID: 1, Events: []entityEvent.Type{entityEvent.PrMerged}},
+		{Id: 4, Events: []entityEvent.Type{entityEvent.PrCreated}},
+	}, nil)
+	mockRepo.EXPECT().AddDeliveries(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, deliveries []entityHook.Delivery) error {
+			assert.Len(t, deliveries, 2)
+			assert.Equal(t, 1, deliveries[0].SubscriptionID)
+			assert.Equal(t, 3, deliveries[1].SubscriptionID)
+			var payload map[string]any
+			assert.NoError(t, json.Unmarshal(deliveries[0].Payload, &payload))
+			assert.Equal(t, "pr.merged", payload["type"])
+			return nil
+		})
+
+	pr := entityPR.PullRequest{Id: "pr1", Status: entityPR.StatusMerged}
+	pr.Author.TeamID = 1
+	err := publisher.Publish(context.Background(), entityEvent.NewPrEvent(entityEvent.PrMerged, pr, ""))
+	assert.NoError(t, err)
+}
+
+func TestWebhookJob_RetriesFailedDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	sender := &fakeSender{fail: map[int]bool{2: true}}
+	job := application.NewWebhookJob(mockRepo, sender, time.Second, zap.NewNop())
+
+	now := time.Date(2025, 11, 29, 12, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().ClaimDueDeliveries(gomock.Any(), now, gomock.Any(), gomock.Any()).Return([]entityHook.Delivery{
+		{Id: 1, Status: entityHook.DeliveryPending},
+		{Id: 2, Status: entityHook.DeliveryPending, Attempts: 2},
+	}, nil)
+	updated := make(map[int]entityHook.Delivery)
+	mockRepo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, d entityHook.Delivery) error {
+			updated[d.Id] = d
+			return nil
+		}).Times(2)
+
+	assert.NoError(t, job.DeliverDue(context.Background(), now))
+	assert.Equal(t, []int{1, 2}, sender.sent)
+	assert.Equal(t, entityHook.DeliveryDelivered, updated[1].Status)
+	assert.Equal(t, entityHook.DeliveryPending, updated[2].Status)
+	assert.Equal(t, 3, updated[2].Attempts)
+	assert.Equal(t, now.Add(2*time.Minute), updated[2].NextAttemptAt)
+	assert.Equal(t, http.StatusBadGateway, updated[2].ResponseCode)
+}
+
+func TestHTTPSender_SignsPayload(t *testing.T) {
+	payload := []byte(`{"type":"pr.created"}`)
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	code, err := webhook.NewHTTPSender(srv.Client()).Send(context.Background(), entityHook.Delivery{
+		URL:       srv.URL,
+		Secret:    "0123456789abcdef",
+		EventID:   "ev1",
+		EventType: entityEvent.PrCreated,
+		Payload:   payload,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "pr.created", got.Header.Get(entityHook.HeaderEvent))
+	assert.Equal(t, "ev1", got.Header.Get(entityHook.HeaderDelivery))
+
+	// подписчик проверяет подпись так же, как описано в README
+	parts := strings.Split(got.Header.Get(entityHook.HeaderSignature), ",")
+	ts := strings.TrimPrefix(parts[0], "t=")
+	mac := hmac.New(sha256.New, []byte("0123456789abcdef"))
+	mac.Write([]byte(ts + "." + string(body)))
+	assert.Equal(t, "v1="+hex.EncodeToString(mac.Sum(nil)), parts[1])
+}
+
+func TestHTTPSender_Non2xxIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	code, err := webhook.NewHTTPSender(srv.Client()).Send(context.Background(), entityHook.Delivery{URL: srv.URL})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestPrService_Close_PassesReasonToRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_interfaces.NewMockPullRequestRepo(ctrl)
+	svc := application.NewPrService(mockRepo)
+
+	mockRepo.EXPECT().GetPr(gomock.Any(), "pr1").Return(reviewedPr(), nil)
+	mockRepo.EXPECT().UpdatePr(gomock.Any(), "pr1", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, pr entityPR.PullRequest) error {
+			assert.Equal(t, entityEvent.ReasonClose, entityEvent.ReasonFrom(ctx))
+			assert.Empty(t, pr.Reviewers)
+			return nil
+		})
+
+	_, err := svc.Close(context.Background(), "pr1")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,214 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"go.uber.org/zap"
+)
+
+// minWebhookSecret - минимальная длина секрета подписи
+const minWebhookSecret = 16
+
+var (
+	ErrInvalidWebhook        = errors.New("webhook needs http(s) url, secret of at least 16 chars and known events")
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrDeliveryNotFound      = errors.New("webhook delivery not found")
+	ErrDeliveryNotFailed     = errors.New("only failed deliveries can be redelivered")
+	ErrInvalidDeliveryStatus = errors.New("unknown delivery status")
+)
+
+// AddWebhook регистрирует подписчика на события команды team_name или, если она не указана, всех команд
+func (s *PrService) AddWebhook(ctx context.Context, req dto.WebhookRequest) (*entityHook.Subscription, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(req.Secret) < minWebhookSecret {
+		return nil, ErrInvalidWebhook
+	}
+	sub := entityHook.Subscription{URL: req.URL, Secret: req.Secret, CreatedAt: time.Now().UTC()}
+	for _, e := range req.Events {
+		t := entityEvent.Type(strings.TrimSpace(e))
+		if !t.IsValid() {
+			return nil, ErrInvalidWebhook
+		}
+		sub.Events = append(sub.Events, t)
+	}
+	if req.TeamName != "" {
+		team, err := s.repo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			if errors.Is(err, repos.ErrTeamNotFound) {
+				return nil, ErrTeamNotFound
+			}
+			return nil, err
+		}
+		sub.TeamID = team.Id
+		sub.TeamName = team.Name
+	}
+	if sub.Id, err = s.repo.AddWebhook(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to add webhook: %w", err)
+	}
+	return &sub, nil
+}
+
+func (s *PrService) GetWebhooks(ctx context.Context) ([]entityHook.Subscription, error) {
+	return s.repo.GetWebhooks(ctx)
+}
+
+func (s *PrService) DeleteWebhook(ctx context.Context, id int) error {
+	if err := s.repo.DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, repos.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// deliveriesPageSize - сколько последних доставок возвращает список
+const deliveriesPageSize = 100
+
+// GetDeliveries возвращает последние доставки со статусом status (по умолчанию FAILED)
+func (s *PrService) GetDeliveries(ctx context.Context, status string) ([]entityHook.Delivery, error) {
+	st := entityHook.DeliveryFailed
+	if status != "" {
+		st = entityHook.DeliveryStatus(strings.ToUpper(status))
+	}
+	switch st {
+	case entityHook.DeliveryPending, entityHook.DeliveryDelivered, entityHook.DeliveryFailed:
+	default:
+		return nil, ErrInvalidDeliveryStatus
+	}
+	return s.repo.GetDeliveries(ctx, st, deliveriesPageSize)
+}
+
+// Redeliver ставит проваленную доставку в очередь заново
+func (s *PrService) Redeliver(ctx context.Context, id int) (*entityHook.Delivery, error) {
+	d, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		if errors.Is(err, repos.ErrDeliveryNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if d.Status != entityHook.DeliveryFailed {
+		return nil, ErrDeliveryNotFailed
+	}
+	d.Redeliver(time.Now().UTC())
+	if err := s.repo.UpdateDelivery(ctx, *d); err != nil {
+		return nil, fmt.Errorf("failed to redeliver: %w", err)
+	}
+	return d, nil
+}
+
+// WebhookPublisher раскладывает событие в доставки подходящим подписчикам,
+// сами запросы отправляет WebhookJob
+type WebhookPublisher struct {
+	repo interfaces.PullRequestRepo
+}
+
+func NewWebhookPublisher(repo interfaces.PullRequestRepo) *WebhookPublisher {
+	return &WebhookPublisher{repo: repo}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, e entityEvent.Event) error {
+	subs, err := p.repo.GetWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	var payload []byte
+	deliveries := make([]entityHook.Delivery, 0)
+	for _, sub := range subs {
+		if !sub.Matches(e) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(e); err != nil {
+				return fmt.Errorf("failed to encode event %s: %w", e.Id, err)
+			}
+		}
+		deliveries = append(deliveries, entityHook.Delivery{
+			SubscriptionID: sub.Id,
+			EventID:        e.Id,
+			EventType:      e.Type,
+			Payload:        payload,
+			Status:         entityHook.DeliveryPending,
+			NextAttemptAt:  e.OccurredAt,
+		})
+	}
+	return p.repo.AddDeliveries(ctx, deliveries)
+}
+
+// параметры отправки: сколько доставок забирается за раз и на сколько они откладываются,
+// чтобы другие реплики не отправили их одновременно
+const (
+	deliveryBatch = 50
+	deliveryLease = time.Minute
+)
+
+// WebhookJob периодически отправляет доставки, время которых наступило
+type WebhookJob struct {
+	repo     interfaces.PullRequestRepo
+	sender   interfaces.WebhookSender
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewWebhookJob(repo interfaces.PullRequestRepo, sender interfaces.WebhookSender, interval time.Duration, logger *zap.Logger) *WebhookJob {
+	return &WebhookJob{
+		repo:     repo,
+		sender:   sender,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (j *WebhookJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := j.DeliverDue(ctx, now); err != nil {
+				j.logger.Error("failed to deliver webhooks", zap.Error(err))
+			}
+		}
+	}
+}
+
+// DeliverDue отправляет доставки, время попытки которых наступило, неудачные откладываются с экспоненциальной паузой
+func (j *WebhookJob) DeliverDue(ctx context.Context, now time.Time) error {
+	due, err := j.repo.ClaimDueDeliveries(ctx, now, deliveryLease, deliveryBatch)
+	if err != nil {
+		return err
+	}
+	for _, d := range due {
+		code, err := j.sender.Send(ctx, d)
+		if err != nil {
+			d.Failed(now, code, err.Error())
+			j.logger.Warn("webhook delivery failed",
+				zap.Int("delivery_id", d.Id),
+				zap.String("url", d.URL),
+				zap.Int("attempt", d.Attempts),
+				zap.Error(err),
+			)
+		} else {
+			d.Succeeded(now, code)
+		}
+		if err := j.repo.UpdateDelivery(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
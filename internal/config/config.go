@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,8 +13,12 @@ type Config struct {
 }
 
 type AppConfig struct {
-	Server  ServerConfig  `yaml:"server"`
-	Logging LoggingConfig `yaml:"logging"`
+	Server        ServerConfig        `yaml:"server"`
+	Logging       LoggingConfig       `yaml:"logging"`
+	Auth          AuthConfig          `yaml:"auth"`
+	Events        EventsConfig        `yaml:"events"`
+	CodeHost      CodeHostConfig      `yaml:"code_host"`
+	Notifications NotificationsConfig `yaml:"notifications"`
 }
 
 type ServerConfig struct {
@@ -24,6 +29,59 @@ type LoggingConfig struct {
 	Level string `yaml:"level"`
 }
 
+// AuthConfig - настройки проверки JWT. Для HS256 секрет можно не хранить в файле,
+// а передать через переменную окружения JWT_SECRET
+type AuthConfig struct {
+	Algorithm     string        `yaml:"algorithm"`
+	Secret        string        `yaml:"secret"`
+	PublicKeyPath string        `yaml:"public_key_path"`
+	Issuer        string        `yaml:"issuer"`
+	Audience      string        `yaml:"audience"`
+	Leeway        time.Duration `yaml:"leeway"`
+}
+
+// EventsConfig - куда relay публикует доменные события из outbox: всегда подписчикам внутри сервиса, stdout и file - ещё и копией JSON строками (в файл Path)
+type EventsConfig struct {
+	Publisher string `yaml:"publisher"`
+	Path      string `yaml:"path"`
+}
+
+// CodeHostConfig - секреты входящих вебхуков GitHub и GitLab, если пусто,
+// то берутся из GITHUB_WEBHOOK_SECRET и GITLAB_WEBHOOK_TOKEN.
+// API токены нужны для переноса ревьюеров к провайдеру, если пусто, то берутся
+// из GITHUB_API_TOKEN и GITLAB_API_TOKEN, без токена ревьюеры к провайдеру не переносятся
+type CodeHostConfig struct {
+	GitHubSecret   string `yaml:"github_secret"`
+	GitLabToken    string `yaml:"gitlab_token"`
+	GitHubAPIURL   string `yaml:"github_api_url"`
+	GitHubAPIToken string `yaml:"github_api_token"`
+	GitLabAPIURL   string `yaml:"gitlab_api_url"`
+	GitLabAPIToken string `yaml:"gitlab_api_token"`
+}
+
+// NotificationsConfig - куда отправляются уведомления ревьюерам: none (по умолчанию), slack, email
+// или несколько через запятую, например "slack,email"
+type NotificationsConfig struct {
+	Notifier string      `yaml:"notifier"`
+	Slack    SlackConfig `yaml:"slack"`
+	Email    EmailConfig `yaml:"email"`
+}
+
+// SlackConfig - incoming webhook Slack или Mattermost. WebhookURL, если пусто, берётся из SLACK_WEBHOOK_URL.
+// Teams - канал (и при необходимости свой webhook) для команды по её имени, Templates - шаблоны
+// text/template для assigned, reassigned, merged, need_more_reviewers, review_reminder и review_escalated вместо встроенных
+type SlackConfig struct {
+	WebhookURL string                        `yaml:"webhook_url"`
+	Username   string                        `yaml:"username"`
+	Teams      map[string]SlackChannelConfig `yaml:"teams"`
+	Templates  map[string]string             `yaml:"templates"`
+}
+
+type SlackChannelConfig struct {
+	Channel    string `yaml:"channel"`
+	WebhookURL string `yaml:"webhook_url"`
+}
+
 func MustLoad(path string) (*AppConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -37,3 +95,15 @@ func MustLoad(path string) (*AppConfig, error) {
 
 	return &cfg.App, nil
 }
+
+// EmailConfig - SMTP сервер для писем о назначениях и ежедневной сводки. Password, если пусто, берётся
+// из SMTP_PASSWORD, без Username письма отправляются без авторизации. DigestAt - время сводки по UTC
+// в формате 15:04, по умолчанию 09:00
+type EmailConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+	DigestAt string `yaml:"digest_at"`
+}
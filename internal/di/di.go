@@ -1,15 +1,53 @@
 package di
 
 import (
+	"context"
+	"time"
+
 	"github.com/JanArsMAI/PullRequestService/internal/application"
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/auth"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/codehost"
 	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/db"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/events"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/notify"
 	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/webhook"
 	rest "github.com/JanArsMAI/PullRequestService/internal/presentation/gin"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func ConfigureApp(r *gin.Engine, logger *zap.Logger) func() {
+// availabilityJobInterval - как часто проверяются начавшиеся и закончившиеся окна недоступности
+const availabilityJobInterval = time.Minute
+
+// webhookJobInterval - как часто отправляются доставки событий подписчикам
+const webhookJobInterval = 5 * time.Second
+
+// outboxRelayInterval - как часто relay публикует события из outbox
+const outboxRelayInterval = time.Second
+
+// streamLogSize - сколько последних событий хранится для продолжения SSE потока по Last-Event-ID
+const streamLogSize = 1000
+
+// streamFeedInterval - как часто каждая реплика читает новые события outbox для SSE потока
+const streamFeedInterval = time.Second
+
+// reviewerSyncJobInterval - как часто ревьюеры переносятся к GitHub/GitLab
+const reviewerSyncJobInterval = 5 * time.Second
+
+// digestJobInterval - как часто проверяется, наступило ли время ежедневной сводки ревью
+const digestJobInterval = time.Minute
+
+// slaJobInterval - как часто проверяются сроки ревью
+const slaJobInterval = time.Minute
+
+// staleReviewJobInterval - как часто заменяются ревьюеры, не оставившие ревью за stale_review_hours команды
+const staleReviewJobInterval = time.Minute
+
+func ConfigureApp(r *gin.Engine, cfg *config.AppConfig, logger *zap.Logger) func() {
 	logger.Info("Starting configuring app...")
 
 	db, err := db.NewPostgresConnection(db.ReadConfig())
@@ -18,8 +56,53 @@ func ConfigureApp(r *gin.Engine, logger *zap.Logger) func() {
 	}
 	repo := repos.NewPostgresRepo(db)
 	svc := application.NewPrService(repo)
-	rest.InitRoutes(r, svc, logger)
+	notifier, err := notify.NewNotifierFromConfig(cfg.Notifications)
+	if err != nil {
+		logger.Fatal("failed to configure notifier", zap.Error(err))
+	}
+	digest, digestAt, err := notify.NewDigestFromConfig(cfg.Notifications)
+	if err != nil {
+		logger.Fatal("failed to configure review digest", zap.Error(err))
+	}
+	stream := events.NewStream(streamLogSize)
+	notifications := application.NewNotificationPublisher(repo, notifier, logger)
+	bus := events.NewBus(
+		application.NewWebhookPublisher(repo),
+		application.NewReviewerSyncPublisher(repo),
+		notifications,
+	)
+	publisher, closePublisher, err := events.NewPublisherFromConfig(cfg.Events, bus)
+	if err != nil {
+		logger.Fatal("failed to configure event publisher", zap.Error(err))
+	}
+	jwtVerifier, err := auth.NewVerifierFromConfig(cfg.Auth)
+	if err != nil {
+		logger.Fatal("failed to configure token verifier", zap.Error(err))
+	}
+	verifier := auth.WithAPIKeys(auth.WithStoredGrants(jwtVerifier, repo), repo, time.Now)
+	github, gitlab := codehost.NewReceiversFromConfig(cfg.CodeHost)
+	receivers := map[entityHost.Provider]interfaces.CodeHostReceiver{
+		entityHost.ProviderGitHub: github,
+		entityHost.ProviderGitLab: gitlab,
+	}
+	rest.InitRoutes(r, application.NewPolicyService(svc, repo), verifier, receivers, stream, logger)
+
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+	go application.NewAvailabilityJob(repo, svc, availabilityJobInterval, logger).Run(jobsCtx)
+	go application.NewWebhookJob(repo, webhook.NewHTTPSender(nil), webhookJobInterval, logger).Run(jobsCtx)
+	go application.NewOutboxRelay(repo, publisher, outboxRelayInterval, logger).Run(jobsCtx)
+	go notifications.Run(jobsCtx)
+	go application.NewStreamFeed(repo, stream, streamLogSize, streamFeedInterval, logger).Run(jobsCtx)
+	go application.NewReviewerSyncJob(repo, codehost.NewClientsFromConfig(cfg.CodeHost), reviewerSyncJobInterval, logger).Run(jobsCtx)
+	go application.NewSLAJob(repo, svc, slaJobInterval, logger).Run(jobsCtx)
+	go application.NewStaleReviewJob(repo, svc, staleReviewJobInterval, logger).Run(jobsCtx)
+	if digest != nil {
+		go application.NewDigestJob(repo, digest, digestAt, digestJobInterval, logger).Run(jobsCtx)
+	}
 	return func() {
+		cancelJobs()
+		stream.Close()
+		_ = closePublisher()
 		_ = logger.Sync()
 	}
 }
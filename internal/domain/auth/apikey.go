@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// APIKeyPrefix - с него начинается каждый API ключ, по нему ключ отличается от JWT
+const APIKeyPrefix = "prs_"
+
+// длина случайной части ключа в байтах и видимой части ключа в символах
+const (
+	apiKeySecretBytes = 32
+	apiKeyVisibleLen  = len(APIKeyPrefix) + 8
+)
+
+// APIKey - ключ доступа для ботов и CI. Сам ключ не хранится, только его SHA-256
+type APIKey struct {
+	Id   int
+	Name string
+	// Prefix - начало ключа, по которому его можно узнать в списке
+	Prefix     string
+	Hash       string
+	Scopes     []Permission
+	CreatedBy  string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// GenerateAPIKey создаёт новый ключ, возвращается один раз при выпуске
+func GenerateAPIKey() (string, error) {
+	b := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + hex.EncodeToString(b), nil
+}
+
+// HashAPIKey возвращает хеш ключа, по которому он ищется в БД.
+// Ключ случайный и длинный, поэтому соль и медленный хеш не нужны
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// VisiblePrefix возвращает начало ключа, которое безопасно показывать
+func VisiblePrefix(key string) string {
+	if len(key) < apiKeyVisibleLen {
+		return key
+	}
+	return key[:apiKeyVisibleLen]
+}
+
+func (k APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// Principal возвращает вызывающего для ключа: права ключа действуют во всей организации
+func (k APIKey) Principal() Principal {
+	return Principal{
+		Subject: "apikey:" + strconv.Itoa(k.Id),
+		Scopes:  k.Scopes,
+	}
+}
+
+// IsValidScope сообщает, можно ли выдать право ключу. Ключ не может управлять ролями и другими ключами
+func IsValidScope(perm Permission) bool {
+	if perm == PermRolesManage || perm == PermAPIKeysManage {
+		return false
+	}
+	return roleAllows(RoleAdmin, perm)
+}
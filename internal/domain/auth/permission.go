@@ -0,0 +1,73 @@
+package entity
+
+// Permission - действие, на которое нужно право. Каждая ручка объявляет нужное ей право
+type Permission string
+
+const (
+	PermTeamRead         Permission = "team:read"
+	PermTeamManage       Permission = "team:manage"
+	PermUserRead         Permission = "user:read"
+	PermUserManage       Permission = "user:manage"
+	PermPrCreate         Permission = "pr:create"
+	PermPrManage         Permission = "pr:manage"
+	PermPrReassign       Permission = "pr:reassign"
+	PermPrMerge          Permission = "pr:merge"
+	PermPrReview         Permission = "pr:review"
+	PermCodeOwnersManage Permission = "codeowners:manage"
+	PermStatsRead        Permission = "stats:read"
+	PermRolesManage      Permission = "roles:manage"
+	PermAPIKeysManage    Permission = "apikeys:manage"
+	PermWebhooksManage   Permission = "webhooks:manage"
+)
+
+// rolePermissions - права ролей. Права тимлида действуют только в его командах,
+// права администратора и участника - во всей организации
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermTeamRead, PermTeamManage, PermUserRead, PermUserManage,
+		PermPrCreate, PermPrManage, PermPrReassign, PermPrMerge, PermPrReview,
+		PermCodeOwnersManage, PermStatsRead, PermRolesManage, PermAPIKeysManage,
+		PermWebhooksManage,
+	},
+	RoleTeamLead: {
+		PermTeamRead, PermTeamManage, PermUserRead, PermUserManage,
+		PermPrCreate, PermPrManage, PermPrReassign, PermPrMerge, PermPrReview,
+		PermCodeOwnersManage,
+	},
+	RoleUser: {PermTeamRead, PermUserRead, PermPrMerge, PermPrReview},
+}
+
+func roleAllows(role Role, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Principal) hasGlobal(perm Permission) bool {
+	if p.HasScope(perm) {
+		return true
+	}
+	for _, r := range p.Roles {
+		if roleAllows(r, perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAny сообщает, есть ли у вызывающего право хотя бы в одной команде,
+// по нему проверяется доступ к ручке, а конкретная команда проверяется в сервисе
+func (p Principal) CanAny(perm Permission) bool {
+	return p.hasGlobal(perm) || (len(p.LeadOf) > 0 && roleAllows(RoleTeamLead, perm))
+}
+
+// Can сообщает, есть ли у вызывающего право в команде teamID
+func (p Principal) Can(perm Permission, teamID int) bool {
+	if p.hasGlobal(perm) {
+		return true
+	}
+	return teamID != 0 && p.IsLeadOf(teamID) && roleAllows(RoleTeamLead, perm)
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token is expired")
+)
+
+// Role - роль вызывающего в сервисе
+type Role string
+
+const (
+	// RoleAdmin - администратор организации, имеет все права
+	RoleAdmin Role = "admin"
+	// RoleTeamLead - тимлид, управляет участниками и PR своей команды
+	RoleTeamLead Role = "team_lead"
+	// RoleUser - участник команды
+	RoleUser Role = "user"
+)
+
+// Grant - роль пользователя, хранящаяся в БД. TeamID задан только для роли тимлида
+type Grant struct {
+	Role     Role
+	TeamID   int
+	TeamName string
+}
+
+// Principal - тот, от чьего имени выполняется запрос.
+// Subject - идентификатор вызывающего для аудита, UserID - пользователь сервиса,
+// с которым он связан (пустой, если вызывающий не является пользователем, например админский токен)
+type Principal struct {
+	Subject string
+	UserID  string
+	// Roles - роли, действующие на всю организацию
+	Roles []Role
+	// LeadOf - команды, в которых вызывающий - тимлид
+	LeadOf []int
+	// Scopes - права API ключа, действуют во всей организации
+	Scopes []Permission
+}
+
+func (p Principal) HasRole(role Role) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Principal) HasScope(perm Permission) bool {
+	for _, s := range p.Scopes {
+		if s == perm {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+func (p Principal) IsLeadOf(teamID int) bool {
+	for _, id := range p.LeadOf {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+// WithGrants добавляет вызывающему роли, выданные ему в БД
+func (p Principal) WithGrants(grants []Grant) Principal {
+	for _, g := range grants {
+		switch {
+		case g.Role == RoleTeamLead && g.TeamID != 0:
+			if !p.IsLeadOf(g.TeamID) {
+				p.LeadOf = append(p.LeadOf, g.TeamID)
+			}
+		case g.Role == RoleAdmin && !p.HasRole(RoleAdmin):
+			p.Roles = append(p.Roles, RoleAdmin)
+		}
+	}
+	return p
+}
+
+// IsUser сообщает, что вызывающий - пользователь сервиса с данным id
+func (p Principal) IsUser(userID string) bool {
+	return p.UserID != "" && p.UserID == userID
+}
+
+type principalKey struct{}
+
+// WithPrincipal кладёт вызывающего в контекст запроса
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext достаёт вызывающего из контекста запроса
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Provider - хостинг кода, от которого приходят вебхуки
+type Provider string
+
+const (
+	ProviderGitHub Provider = "github"
+	ProviderGitLab Provider = "gitlab"
+)
+
+func (p Provider) IsValid() bool {
+	return p == ProviderGitHub || p == ProviderGitLab
+}
+
+// Action - что произошло с PR у провайдера, приведённое к общему виду
+type Action string
+
+const (
+	ActionOpened   Action = "opened"
+	ActionMerged   Action = "merged"
+	ActionClosed   Action = "closed"
+	ActionReopened Action = "reopened"
+	ActionDraft    Action = "converted_to_draft"
+	ActionReady    Action = "ready_for_review"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrMalformedPayload = errors.New("malformed webhook payload")
+	// ErrIgnoredEvent - вебхук корректный, но сервису он не нужен (ping, другие события и действия)
+	ErrIgnoredEvent = errors.New("webhook event is ignored")
+)
+
+// PullRequestEvent - вебхук о PR (merge request в GitLab) после проверки подписи.
+// Логины - пользователи провайдера, в users.user_id они переводятся через UserMapping
+type PullRequestEvent struct {
+	Provider   Provider
+	DeliveryID string
+	Action     Action
+	RepoID     int64
+	// Repo - полное имя репозитория, нужно для запросов к API GitHub
+	Repo        string
+	Number      int
+	Title       string
+	AuthorLogin string
+	// SenderLogin - кто совершил действие (для мержа - кто смержил)
+	SenderLogin string
+	Draft       bool
+}
+
+// PrID - id PR в сервисе, одинаковый для всех вебхуков одного PR
+func (e PullRequestEvent) PrID() string {
+	return fmt.Sprintf("%s-%d-%d", e.Provider, e.RepoID, e.Number)
+}
+
+// UserMapping связывает логин у провайдера с пользователем сервиса
+type UserMapping struct {
+	Provider Provider
+	Login    string
+	UserID   string
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"time"
+)
+
+// SyncStatus - состояние переноса ревьюеров PR к провайдеру
+type SyncStatus string
+
+const (
+	SyncPending SyncStatus = "PENDING"
+	SyncSynced  SyncStatus = "SYNCED"
+	SyncFailed  SyncStatus = "FAILED"
+)
+
+// Параметры повторных попыток: 30s, 1m, 2m, ... но не больше часа между попытками
+const (
+	MaxSyncAttempts = 8
+	baseBackoff     = 30 * time.Second
+	maxBackoff      = time.Hour
+)
+
+// Link связывает PR сервиса с PR (merge request) у провайдера и хранит состояние синхронизации ревьюеров.
+// Repo - полное имя репозитория ("owner/name") для GitHub, для GitLab запросы идут по RepoID
+type Link struct {
+	PrID     string
+	Provider Provider
+	RepoID   int64
+	Repo     string
+	Number   int
+	Status   SyncStatus
+	// Version растёт при каждом изменении ревьюеров, попытка, начатая до изменения, не отмечает PR синхронизированным
+	Version       int
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	// SyncedReviewers - user_id ревьюеров, которые уже запрошены у провайдера
+	SyncedReviewers []string
+	SyncedAt        *time.Time
+}
+
+// NewLink - связь для PR, открытого у провайдера, ревьюеры переносятся сразу
+func NewLink(e PullRequestEvent, now time.Time) Link {
+	return Link{
+		PrID:          e.PrID(),
+		Provider:      e.Provider,
+		RepoID:        e.RepoID,
+		Repo:          e.Repo,
+		Number:        e.Number,
+		Status:        SyncPending,
+		Version:       1,
+		NextAttemptAt: now,
+	}
+}
+
+// Backoff возвращает паузу перед следующей попыткой после attempt неудачных
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+// Succeeded запоминает перенесённых ревьюеров, note - пояснение, например о ревьюерах без логина у провайдера
+func (l *Link) Succeeded(now time.Time, reviewers []string, note string) {
+	l.Status = SyncSynced
+	l.Attempts = 0
+	l.LastError = note
+	l.SyncedReviewers = reviewers
+	l.SyncedAt = &now
+}
+
+// Failed записывает неудачную попытку, после MaxSyncAttempts синхронизация считается проваленной
+// до следующего изменения ревьюеров
+func (l *Link) Failed(now time.Time, reason string) {
+	l.Attempts++
+	l.LastError = reason
+	if l.Attempts >= MaxSyncAttempts {
+		l.Status = SyncFailed
+		return
+	}
+	l.Status = SyncPending
+	l.NextAttemptAt = now.Add(Backoff(l.Attempts))
+}
+
+// Abandon отмечает синхронизацию проваленной без повторов, например если для провайдера нет токена
+func (l *Link) Abandon(reason string) {
+	l.Attempts++
+	l.LastError = reason
+	l.Status = SyncFailed
+}
+
+// ReviewerDiff возвращает, кого запросить у провайдера и с кого снять запрос,
+// чтобы от synced прийти к desired
+func ReviewerDiff(synced, desired []string) (add, remove []string) {
+	inSynced := make(map[string]struct{}, len(synced))
+	for _, id := range synced {
+		inSynced[id] = struct{}{}
+	}
+	inDesired := make(map[string]struct{}, len(desired))
+	for _, id := range desired {
+		inDesired[id] = struct{}{}
+		if _, ok := inSynced[id]; !ok {
+			add = append(add, id)
+		}
+	}
+	for _, id := range synced {
+		if _, ok := inDesired[id]; !ok {
+			remove = append(remove, id)
+		}
+	}
+	return add, remove
+}
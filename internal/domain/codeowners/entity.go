@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	OwnerUser = "user"
+	OwnerTeam = "team"
+)
+
+// Owner - владелец пути: пользователь (@u1) или команда (@org/Platform)
+type Owner struct {
+	Kind string
+	Name string
+}
+
+type Rule struct {
+	Pattern string
+	Owners  []Owner
+	re      *regexp.Regexp
+}
+
+func NewRule(pattern string, owners []Owner) (Rule, error) {
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Pattern: pattern, Owners: owners, re: re}, nil
+}
+
+func (r Rule) Matches(path string) bool {
+	re := r.re
+	if re == nil {
+		var err error
+		if re, err = compilePattern(r.Pattern); err != nil {
+			return false
+		}
+	}
+	return re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// Match возвращает правило для пути, как и в CODEOWNERS побеждает последнее подходящее правило
+func Match(rules []Rule, path string) (Rule, bool) {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Matches(path) {
+			return rules[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+// compilePattern переводит glob в стиле gitignore в регулярное выражение:
+// шаблон с "/" в начале или середине привязан к корню, иначе ищется на любой глубине,
+// "/" в конце означает всё содержимое директории, "**" - любое число директорий
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimSpace(pattern)
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(strings.Trim(p, "/"), "/")
+	p = strings.TrimPrefix(p, "/")
+	if strings.HasSuffix(p, "/") {
+		p += "**"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c == '*' && strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if !strings.HasSuffix(p, "*") {
+		sb.WriteString("(?:/.*)?")
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var ErrInvalidSyntax = errors.New("invalid CODEOWNERS syntax")
+
+// Parse разбирает файл в формате CODEOWNERS:
+//
+//	# комментарий
+//	*            @u1
+//	/docs/       @org/Docs
+//	*.sql        @u2 @org/Platform
+//
+// @name - пользователь (user_id), @org/name - команда (team_name, org игнорируется)
+func Parse(r io.Reader) ([]Rule, error) {
+	rules := make([]Rule, 0)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%w: line %d: no owners for %q", ErrInvalidSyntax, lineNum, fields[0])
+		}
+		owners := make([]Owner, 0, len(fields)-1)
+		for _, f := range fields[1:] {
+			owner, err := parseOwner(f)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidSyntax, lineNum, err)
+			}
+			owners = append(owners, owner)
+		}
+		rule, err := NewRule(fields[0], owners)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad pattern %q", ErrInvalidSyntax, lineNum, fields[0])
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+	return rules, nil
+}
+
+func parseOwner(token string) (Owner, error) {
+	if !strings.HasPrefix(token, "@") || len(token) == 1 {
+		return Owner{}, fmt.Errorf("owner %q must start with @", token)
+	}
+	name := token[1:]
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		if idx == len(name)-1 {
+			return Owner{}, fmt.Errorf("empty team name in %q", token)
+		}
+		return Owner{Kind: OwnerTeam, Name: name[idx+1:]}, nil
+	}
+	return Owner{Kind: OwnerUser, Name: name}, nil
+}
@@ -0,0 +1,353 @@
+package entity
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityPr "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+)
+
+// Type - тип доменного события
+type Type string
+
+const (
+	PrCreated          Type = "pr.created"
+	PrReviewersChanged Type = "pr.reviewers_changed"
+	PrStatusChanged    Type = "pr.status_changed"
+	PrMerged           Type = "pr.merged"
+	PrReviewed         Type = "pr.reviewed"
+	PrUpdated          Type = "pr.updated"
+	PrReviewOverdue    Type = "pr.review_overdue"
+	UserActivated      Type = "user.activated"
+	UserDeactivated    Type = "user.deactivated"
+	UserUpdated        Type = "user.updated"
+	TeamCreated        Type = "team.created"
+	TeamUpdated        Type = "team.updated"
+	APIKeyCreated      Type = "api_key.created"
+	APIKeyRevoked      Type = "api_key.revoked"
+	WebhookCreated     Type = "webhook.created"
+	WebhookDeleted     Type = "webhook.deleted"
+)
+
+var types = map[Type]struct{}{
+	PrCreated:          {},
+	PrReviewersChanged: {},
+	PrStatusChanged:    {},
+	PrMerged:           {},
+	PrReviewed:         {},
+	PrUpdated:          {},
+	PrReviewOverdue:    {},
+	UserActivated:      {},
+	UserDeactivated:    {},
+	UserUpdated:        {},
+	TeamCreated:        {},
+	TeamUpdated:        {},
+	APIKeyCreated:      {},
+	APIKeyRevoked:      {},
+	WebhookCreated:     {},
+	WebhookDeleted:     {},
+}
+
+func (t Type) IsValid() bool {
+	_, ok := types[t]
+	return ok
+}
+
+// Причины изменения ревьюеров в pr.reviewers_changed
+const (
+	ReasonReassign   = "reassign"
+	ReasonDeactivate = "deactivation"
+	ReasonActivate   = "activation"
+	ReasonReady      = "ready"
+	ReasonReopen     = "reopen"
+	ReasonClose      = "close"
+	ReasonDraft      = "draft"
+	ReasonSLA        = "sla"
+	ReasonStale      = "stale"
+	ReasonTeamMove   = "team_move"
+	// ReasonCreate - ревьюеры назначены при создании PR, встречается только в истории ревьюеров
+	ReasonCreate = "create"
+)
+
+// IsReplacement - ревьюера заменили другим, а не просто сняли или назначили
+func IsReplacement(reason string) bool {
+	return reason == ReasonReassign || reason == ReasonSLA || reason == ReasonStale
+}
+
+// Стадии просрочки ревью в pr.review_overdue
+const (
+	// StageReminder - прошёл срок первого ревью, ревьюеру напоминают
+	StageReminder = "reminder"
+	// StageEscalation - прошёл второй срок, о просрочке сообщают тимлидам
+	StageEscalation = "escalation"
+)
+
+// Агрегаты, к которым относятся события. Порядок доставки гарантируется в пределах одного агрегата
+const (
+	AggregatePr      = "pull_request"
+	AggregateUser    = "user"
+	AggregateTeam    = "team"
+	AggregateAPIKey  = "api_key"
+	AggregateWebhook = "webhook"
+)
+
+type reasonKey struct{}
+
+// WithReason запоминает в контексте причину изменения, репозиторий кладёт её в событие
+func WithReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// ReasonFrom возвращает причину изменения из контекста или пустую строку
+func ReasonFrom(ctx context.Context) string {
+	reason, _ := ctx.Value(reasonKey{}).(string)
+	return reason
+}
+
+// Event - доменное событие. TeamID - команда, к которой относится событие
+// (для PR - команда автора), по ней подписчики фильтруют события своей команды.
+// Seq - номер записи в outbox, заполняется при чтении оттуда
+type Event struct {
+	Id          string    `json:"id"`
+	Seq         int64     `json:"seq,omitempty"`
+	Type        Type      `json:"type"`
+	Aggregate   string    `json:"aggregate"`
+	AggregateID string    `json:"aggregate_id"`
+	TeamID      int       `json:"team_id"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	Data        any       `json:"data"`
+}
+
+type PrData struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	NeedMoreReviewers bool     `json:"need_more_reviewers"`
+	NeedMoreReason    string   `json:"need_more_reason,omitempty"`
+	MergedBy          string   `json:"merged_by,omitempty"`
+	Reason            string   `json:"reason,omitempty"`
+	// AddedReviewers и RemovedReviewers - кого назначили и кого сняли, заполняются для pr.reviewers_changed
+	AddedReviewers   []string `json:"added_reviewers,omitempty"`
+	RemovedReviewers []string `json:"removed_reviewers,omitempty"`
+}
+
+// ReviewData - данные pr.reviewed: состояние PR и оставленное ревью
+type ReviewData struct {
+	PrData
+	ReviewerID string `json:"reviewer_id"`
+	Verdict    string `json:"verdict"`
+}
+
+// OverdueData - данные pr.review_overdue: состояние PR, просрочивший ревьюер и кому сообщить об эскалации
+type OverdueData struct {
+	PrData
+	ReviewerID string    `json:"reviewer_id"`
+	AssignedAt time.Time `json:"assigned_at"`
+	Stage      string    `json:"stage"`
+	Leads      []string  `json:"leads,omitempty"`
+}
+
+type UserData struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type TeamData struct {
+	TeamName string   `json:"team_name"`
+	Members  []string `json:"members"`
+}
+
+// APIKeyData - данные событий API ключа, без самого ключа и его хеша
+type APIKeyData struct {
+	KeyID     int      `json:"key_id"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	CreatedBy string   `json:"created_by"`
+}
+
+// WebhookData - данные событий подписки, без URL и секрета подписи
+type WebhookData struct {
+	WebhookID int      `json:"webhook_id"`
+	Events    []string `json:"events"`
+}
+
+func newEvent(t Type, aggregate, aggregateID string, teamID int, data any) Event {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return Event{
+		Id:          hex.EncodeToString(b),
+		Type:        t,
+		Aggregate:   aggregate,
+		AggregateID: aggregateID,
+		TeamID:      teamID,
+		OccurredAt:  time.Now().UTC(),
+		Data:        data,
+	}
+}
+
+// NewPrEvent описывает событие PR, reason заполняется для pr.reviewers_changed
+func NewPrEvent(t Type, pr entityPr.PullRequest, reason string) Event {
+	return newEvent(t, AggregatePr, pr.Id, pr.Author.TeamID, prData(pr, reason))
+}
+
+// NewPrChange описывает изменение PR событием, которое выбирает PrChange, и запоминает,
+// кого из ревьюеров назначили и кого сняли
+func NewPrChange(before, after entityPr.PullRequest, reason string) Event {
+	data := prData(after, reason)
+	data.AddedReviewers, data.RemovedReviewers = entityPr.ReviewerDiff(before, after)
+	return newEvent(PrChange(before, after), AggregatePr, after.Id, after.Author.TeamID, data)
+}
+
+// NewPrReviewersChanged - pr.reviewers_changed, когда известно только, кого назначили или сняли
+func NewPrReviewersChanged(pr entityPr.PullRequest, added, removed []string, reason string) Event {
+	data := prData(pr, reason)
+	data.AddedReviewers, data.RemovedReviewers = added, removed
+	return newEvent(PrReviewersChanged, AggregatePr, pr.Id, pr.Author.TeamID, data)
+}
+
+// NewPrReviewed описывает ревью, оставленное на PR
+func NewPrReviewed(pr entityPr.PullRequest, r entityPr.Review) Event {
+	return newEvent(PrReviewed, AggregatePr, pr.Id, pr.Author.TeamID, ReviewData{
+		PrData:     prData(pr, ""),
+		ReviewerID: r.ReviewerID,
+		Verdict:    string(r.Verdict),
+	})
+}
+
+// NewReviewOverdue описывает просрочку ревью, для StageEscalation leads - тимлиды команды PR
+func NewReviewOverdue(pr entityPr.PullRequest, b entityPr.SLABreach, stage string, leads []string) Event {
+	return newEvent(PrReviewOverdue, AggregatePr, pr.Id, pr.Author.TeamID, OverdueData{
+		PrData:     prData(pr, ""),
+		ReviewerID: b.ReviewerID,
+		AssignedAt: b.AssignedAt,
+		Stage:      stage,
+		Leads:      leads,
+	})
+}
+
+func prData(pr entityPr.PullRequest, reason string) PrData {
+	reviewers := make([]string, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		reviewers = append(reviewers, r.Id)
+	}
+	return PrData{
+		PullRequestID:     pr.Id,
+		PullRequestName:   pr.Name,
+		AuthorID:          pr.Author.Id,
+		Status:            string(pr.Status),
+		AssignedReviewers: reviewers,
+		NeedMoreReviewers: pr.NeedMoreReviewers,
+		NeedMoreReason:    pr.NeedMoreReason,
+		MergedBy:          pr.MergedBy,
+		Reason:            reason,
+	}
+}
+
+func NewUserEvent(t Type, u entityUser.User) Event {
+	return newEvent(t, AggregateUser, u.Id, u.TeamID, UserData{UserID: u.Id, Username: u.Name, IsActive: u.IsActive})
+}
+
+func NewTeamEvent(t Type, team entityTeam.Team) Event {
+	members := make([]string, 0, len(team.Users))
+	for _, u := range team.Users {
+		members = append(members, u.Id)
+	}
+	return newEvent(t, AggregateTeam, strconv.Itoa(team.Id), team.Id, TeamData{TeamName: team.Name, Members: members})
+}
+
+// NewAPIKeyEvent описывает выпуск или отзыв API ключа, ключи не относятся к команде
+func NewAPIKeyEvent(t Type, k entityAuth.APIKey) Event {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return newEvent(t, AggregateAPIKey, strconv.Itoa(k.Id), 0, APIKeyData{KeyID: k.Id, Name: k.Name, Scopes: scopes, CreatedBy: k.CreatedBy})
+}
+
+// NewWebhookEvent описывает добавление или удаление подписки на события команды teamID (0 - всех команд)
+func NewWebhookEvent(t Type, id, teamID int, events []Type) Event {
+	names := make([]string, 0, len(events))
+	for _, e := range events {
+		names = append(names, string(e))
+	}
+	return newEvent(t, AggregateWebhook, strconv.Itoa(id), teamID, WebhookData{WebhookID: id, Events: names})
+}
+
+// PrChange определяет, каким событием описать изменение PR: мерж важнее смены ревьюеров,
+// смена ревьюеров важнее смены статуса, остальное - pr.updated
+func PrChange(before, after entityPr.PullRequest) Type {
+	if after.Status == entityPr.StatusMerged && before.Status != entityPr.StatusMerged {
+		return PrMerged
+	}
+	if !sameReviewers(before, after) {
+		return PrReviewersChanged
+	}
+	if before.Status != after.Status {
+		return PrStatusChanged
+	}
+	return PrUpdated
+}
+
+func sameReviewers(a, b entityPr.PullRequest) bool {
+	if len(a.Reviewers) != len(b.Reviewers) {
+		return false
+	}
+	ids := make(map[string]struct{}, len(a.Reviewers))
+	for _, r := range a.Reviewers {
+		ids[r.Id] = struct{}{}
+	}
+	for _, r := range b.Reviewers {
+		if _, ok := ids[r.Id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// PrDataOf возвращает данные события PR (для pr.reviewed - без ревью),
+// данные прочитанных из outbox событий разбираются из JSON
+func PrDataOf(e Event) (PrData, bool) {
+	switch d := e.Data.(type) {
+	case PrData:
+		return d, true
+	case ReviewData:
+		return d.PrData, true
+	case OverdueData:
+		return d.PrData, true
+	case json.RawMessage:
+		var data PrData
+		if err := json.Unmarshal(d, &data); err != nil || data.PullRequestID == "" {
+			return PrData{}, false
+		}
+		return data, true
+	}
+	return PrData{}, false
+}
+
+// OverdueDataOf возвращает данные pr.review_overdue, данные прочитанных из outbox событий разбираются из JSON
+func OverdueDataOf(e Event) (OverdueData, bool) {
+	if e.Type != PrReviewOverdue {
+		return OverdueData{}, false
+	}
+	switch d := e.Data.(type) {
+	case OverdueData:
+		return d, true
+	case json.RawMessage:
+		var data OverdueData
+		if err := json.Unmarshal(d, &data); err != nil || data.ReviewerID == "" {
+			return OverdueData{}, false
+		}
+		return data, true
+	}
+	return OverdueData{}, false
+}
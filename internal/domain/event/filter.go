@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"encoding/json"
+)
+
+// Filter отбирает события для подписчика потока. TeamIDs = nil - события всех команд,
+// пустой UserID - события всех пользователей, пустой Types - события всех типов
+type Filter struct {
+	TeamIDs []int
+	UserID  string
+	Types   []Type
+}
+
+func (f Filter) Matches(e Event) bool {
+	if len(f.Types) > 0 && !containsType(f.Types, e.Type) {
+		return false
+	}
+	if f.TeamIDs != nil && !containsTeam(f.TeamIDs, e.TeamID) {
+		return false
+	}
+	if f.UserID == "" {
+		return true
+	}
+	for _, id := range e.Users() {
+		if id == f.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(types []Type, t Type) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTeam(teams []int, id int) bool {
+	for _, v := range teams {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// eventUsers - поля данных событий, в которых упоминаются пользователи
+type eventUsers struct {
+	UserID            string   `json:"user_id"`
+	AuthorID          string   `json:"author_id"`
+	ReviewerID        string   `json:"reviewer_id"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	RemovedReviewers  []string `json:"removed_reviewers"`
+	Members           []string `json:"members"`
+}
+
+// Users возвращает пользователей, которых касается событие: автора и ревьюеров PR (в том числе снятых),
+// самого пользователя, участников команды. Данные прочитанных из outbox событий разбираются из JSON
+func (e Event) Users() []string {
+	var u eventUsers
+	switch d := e.Data.(type) {
+	case PrData:
+		u = eventUsers{AuthorID: d.AuthorID, AssignedReviewers: d.AssignedReviewers, RemovedReviewers: d.RemovedReviewers}
+	case ReviewData:
+		u = eventUsers{AuthorID: d.AuthorID, ReviewerID: d.ReviewerID, AssignedReviewers: d.AssignedReviewers}
+	case OverdueData:
+		u = eventUsers{AuthorID: d.AuthorID, ReviewerID: d.ReviewerID, AssignedReviewers: d.AssignedReviewers}
+	case UserData:
+		u = eventUsers{UserID: d.UserID}
+	case TeamData:
+		u = eventUsers{Members: d.Members}
+	case json.RawMessage:
+		if err := json.Unmarshal(d, &u); err != nil {
+			return nil
+		}
+	}
+	res := make([]string, 0, 3+len(u.AssignedReviewers)+len(u.RemovedReviewers)+len(u.Members))
+	for _, id := range []string{u.UserID, u.AuthorID, u.ReviewerID} {
+		if id != "" {
+			res = append(res, id)
+		}
+	}
+	res = append(res, u.AssignedReviewers...)
+	res = append(res, u.RemovedReviewers...)
+	return append(res, u.Members...)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+)
+
+// CodeHostClient запрашивает ревью у пользователей провайдера в PR, на который указывает link.
+// Логины - пользователи провайдера, уже запрошенные и не запрошенные ревьюеры не считаются ошибкой
+type CodeHostClient interface {
+	RequestReviewers(ctx context.Context, link entityHost.Link, logins []string) error
+	RemoveReviewers(ctx context.Context, link entityHost.Link, logins []string) error
+}
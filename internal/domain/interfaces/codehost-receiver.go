@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"net/http"
+
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+)
+
+// CodeHostReceiver проверяет подпись входящего вебхука GitHub/GitLab и приводит его к PullRequestEvent.
+// Неподходящие события возвращают entityHost.ErrIgnoredEvent
+type CodeHostReceiver interface {
+	Parse(header http.Header, body []byte) (*entityHost.PullRequestEvent, error)
+}
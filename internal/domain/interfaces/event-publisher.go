@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+)
+
+// EventPublisher получает доменные события сервиса (создание PR, смена ревьюеров, мерж и т.д.)
+type EventPublisher interface {
+	Publish(ctx context.Context, e entityEvent.Event) error
+}
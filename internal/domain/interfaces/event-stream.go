@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+)
+
+// EventStream раздаёт опубликованные события подписчикам потока. Subscribe возвращает события,
+// опубликованные после события с номером lastSeq (found = false, если его уже нет в журнале),
+// канал новых событий и функцию отписки. Канал закрывается, если подписчик не успевает читать
+type EventStream interface {
+	Subscribe(lastSeq int64) (backlog []entityEvent.Event, found bool, events <-chan entityEvent.Event, cancel func())
+}
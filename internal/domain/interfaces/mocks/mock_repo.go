@@ -7,10 +7,16 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
-	entity "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
-	entity0 "github.com/JanArsMAI/PullRequestService/internal/domain/team"
-	entity1 "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	entity "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entity0 "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entity1 "github.com/JanArsMAI/PullRequestService/internal/domain/codeowners"
+	entity2 "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	entity3 "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entity4 "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entity5 "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	entity6 "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -37,8 +43,66 @@ func (m *MockPullRequestRepo) EXPECT() *MockPullRequestRepoMockRecorder {
 	return m.recorder
 }
 
+// AddAPIKey mocks base method.
+func (m *MockPullRequestRepo) AddAPIKey(ctx context.Context, k entity.APIKey) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAPIKey", ctx, k)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAPIKey indicates an expected call of AddAPIKey.
+func (mr *MockPullRequestRepoMockRecorder) AddAPIKey(ctx, k interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAPIKey", reflect.TypeOf((*MockPullRequestRepo)(nil).AddAPIKey), ctx, k)
+}
+
+// AddAvailability mocks base method.
+func (m *MockPullRequestRepo) AddAvailability(ctx context.Context, w entity5.Availability) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAvailability", ctx, w)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAvailability indicates an expected call of AddAvailability.
+func (mr *MockPullRequestRepoMockRecorder) AddAvailability(ctx, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAvailability", reflect.TypeOf((*MockPullRequestRepo)(nil).AddAvailability), ctx, w)
+}
+
+// AddCodeHostLink mocks base method.
+func (m *MockPullRequestRepo) AddCodeHostLink(ctx context.Context, l entity0.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCodeHostLink", ctx, l)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCodeHostLink indicates an expected call of AddCodeHostLink.
+func (mr *MockPullRequestRepoMockRecorder) AddCodeHostLink(ctx, l interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCodeHostLink", reflect.TypeOf((*MockPullRequestRepo)(nil).AddCodeHostLink), ctx, l)
+}
+
+// AddDeliveries mocks base method.
+func (m *MockPullRequestRepo) AddDeliveries(ctx context.Context, deliveries []entity6.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeliveries indicates an expected call of AddDeliveries.
+func (mr *MockPullRequestRepoMockRecorder) AddDeliveries(ctx, deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeliveries", reflect.TypeOf((*MockPullRequestRepo)(nil).AddDeliveries), ctx, deliveries)
+}
+
 // AddPR mocks base method.
-func (m *MockPullRequestRepo) AddPR(ctx context.Context, pr entity.PullRequest) error {
+func (m *MockPullRequestRepo) AddPR(ctx context.Context, pr entity3.PullRequest) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddPR", ctx, pr)
 	ret0, _ := ret[0].(error)
@@ -51,6 +115,21 @@ func (mr *MockPullRequestRepoMockRecorder) AddPR(ctx, pr interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPR", reflect.TypeOf((*MockPullRequestRepo)(nil).AddPR), ctx, pr)
 }
 
+// AddReview mocks base method.
+func (m *MockPullRequestRepo) AddReview(ctx context.Context, r entity3.Review) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReview", ctx, r)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReview indicates an expected call of AddReview.
+func (mr *MockPullRequestRepoMockRecorder) AddReview(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReview", reflect.TypeOf((*MockPullRequestRepo)(nil).AddReview), ctx, r)
+}
+
 // AddReviewerToPR mocks base method.
 func (m *MockPullRequestRepo) AddReviewerToPR(ctx context.Context, prId, reviewerID string) error {
 	m.ctrl.T.Helper()
@@ -66,24 +145,171 @@ func (mr *MockPullRequestRepoMockRecorder) AddReviewerToPR(ctx, prId, reviewerID
 }
 
 // AddTeam mocks base method.
-func (m *MockPullRequestRepo) AddTeam(ctx context.Context, name string, users []entity1.User) error {
+func (m *MockPullRequestRepo) AddTeam(ctx context.Context, team entity4.Team) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddTeam", ctx, name, users)
+	ret := m.ctrl.Call(m, "AddTeam", ctx, team)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddTeam indicates an expected call of AddTeam.
-func (mr *MockPullRequestRepoMockRecorder) AddTeam(ctx, name, users interface{}) *gomock.Call {
+func (mr *MockPullRequestRepoMockRecorder) AddTeam(ctx, team interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeam", reflect.TypeOf((*MockPullRequestRepo)(nil).AddTeam), ctx, team)
+}
+
+// AddWebhook mocks base method.
+func (m *MockPullRequestRepo) AddWebhook(ctx context.Context, w entity6.Subscription) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWebhook", ctx, w)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWebhook indicates an expected call of AddWebhook.
+func (mr *MockPullRequestRepoMockRecorder) AddWebhook(ctx, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWebhook", reflect.TypeOf((*MockPullRequestRepo)(nil).AddWebhook), ctx, w)
+}
+
+// ClaimCodeHostDelivery mocks base method.
+func (m *MockPullRequestRepo) ClaimCodeHostDelivery(ctx context.Context, provider entity0.Provider, deliveryID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimCodeHostDelivery", ctx, provider, deliveryID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimCodeHostDelivery indicates an expected call of ClaimCodeHostDelivery.
+func (mr *MockPullRequestRepoMockRecorder) ClaimCodeHostDelivery(ctx, provider, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimCodeHostDelivery", reflect.TypeOf((*MockPullRequestRepo)(nil).ClaimCodeHostDelivery), ctx, provider, deliveryID)
+}
+
+// ClaimDigest mocks base method.
+func (m *MockPullRequestRepo) ClaimDigest(ctx context.Context, userID string, day time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDigest", ctx, userID, day)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDigest indicates an expected call of ClaimDigest.
+func (mr *MockPullRequestRepoMockRecorder) ClaimDigest(ctx, userID, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDigest", reflect.TypeOf((*MockPullRequestRepo)(nil).ClaimDigest), ctx, userID, day)
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockPullRequestRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity6.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]entity6.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockPullRequestRepoMockRecorder) ClaimDueDeliveries(ctx, now, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockPullRequestRepo)(nil).ClaimDueDeliveries), ctx, now, lease, limit)
+}
+
+// ClaimDueReviewerSyncs mocks base method.
+func (m *MockPullRequestRepo) ClaimDueReviewerSyncs(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity0.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueReviewerSyncs", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]entity0.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueReviewerSyncs indicates an expected call of ClaimDueReviewerSyncs.
+func (mr *MockPullRequestRepoMockRecorder) ClaimDueReviewerSyncs(ctx, now, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueReviewerSyncs", reflect.TypeOf((*MockPullRequestRepo)(nil).ClaimDueReviewerSyncs), ctx, now, lease, limit)
+}
+
+// DeleteAvailability mocks base method.
+func (m *MockPullRequestRepo) DeleteAvailability(ctx context.Context, userID string, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailability", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAvailability indicates an expected call of DeleteAvailability.
+func (mr *MockPullRequestRepoMockRecorder) DeleteAvailability(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailability", reflect.TypeOf((*MockPullRequestRepo)(nil).DeleteAvailability), ctx, userID, id)
+}
+
+// DeleteCodeHostUser mocks base method.
+func (m *MockPullRequestRepo) DeleteCodeHostUser(ctx context.Context, provider entity0.Provider, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCodeHostUser", ctx, provider, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCodeHostUser indicates an expected call of DeleteCodeHostUser.
+func (mr *MockPullRequestRepoMockRecorder) DeleteCodeHostUser(ctx, provider, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCodeHostUser", reflect.TypeOf((*MockPullRequestRepo)(nil).DeleteCodeHostUser), ctx, provider, login)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockPullRequestRepo) DeleteWebhook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockPullRequestRepoMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockPullRequestRepo)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockPullRequestRepo) GetAPIKeyByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockPullRequestRepoMockRecorder) GetAPIKeyByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockPullRequestRepo)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockPullRequestRepo) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx)
+	ret0, _ := ret[0].([]entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockPullRequestRepoMockRecorder) GetAPIKeys(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeam", reflect.TypeOf((*MockPullRequestRepo)(nil).AddTeam), ctx, name, users)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockPullRequestRepo)(nil).GetAPIKeys), ctx)
 }
 
 // GetAllPRs mocks base method.
-func (m *MockPullRequestRepo) GetAllPRs(ctx context.Context) ([]entity.PullRequest, error) {
+func (m *MockPullRequestRepo) GetAllPRs(ctx context.Context) ([]entity3.PullRequest, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAllPRs", ctx)
-	ret0, _ := ret[0].([]entity.PullRequest)
+	ret0, _ := ret[0].([]entity3.PullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -94,11 +320,176 @@ func (mr *MockPullRequestRepoMockRecorder) GetAllPRs(ctx interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPRs", reflect.TypeOf((*MockPullRequestRepo)(nil).GetAllPRs), ctx)
 }
 
+// GetAssignments mocks base method.
+func (m *MockPullRequestRepo) GetAssignments(ctx context.Context, prID string) ([]entity3.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignments", ctx, prID)
+	ret0, _ := ret[0].([]entity3.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignments indicates an expected call of GetAssignments.
+func (mr *MockPullRequestRepoMockRecorder) GetAssignments(ctx, prID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignments", reflect.TypeOf((*MockPullRequestRepo)(nil).GetAssignments), ctx, prID)
+}
+
+// GetAvailability mocks base method.
+func (m *MockPullRequestRepo) GetAvailability(ctx context.Context, userID string) ([]entity5.Availability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailability", ctx, userID)
+	ret0, _ := ret[0].([]entity5.Availability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailability indicates an expected call of GetAvailability.
+func (mr *MockPullRequestRepoMockRecorder) GetAvailability(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailability", reflect.TypeOf((*MockPullRequestRepo)(nil).GetAvailability), ctx, userID)
+}
+
+// GetCodeHostLogins mocks base method.
+func (m *MockPullRequestRepo) GetCodeHostLogins(ctx context.Context, provider entity0.Provider, userIDs []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeHostLogins", ctx, provider, userIDs)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeHostLogins indicates an expected call of GetCodeHostLogins.
+func (mr *MockPullRequestRepoMockRecorder) GetCodeHostLogins(ctx, provider, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeHostLogins", reflect.TypeOf((*MockPullRequestRepo)(nil).GetCodeHostLogins), ctx, provider, userIDs)
+}
+
+// GetCodeHostUser mocks base method.
+func (m *MockPullRequestRepo) GetCodeHostUser(ctx context.Context, provider entity0.Provider, login string) (*entity0.UserMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeHostUser", ctx, provider, login)
+	ret0, _ := ret[0].(*entity0.UserMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeHostUser indicates an expected call of GetCodeHostUser.
+func (mr *MockPullRequestRepoMockRecorder) GetCodeHostUser(ctx, provider, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeHostUser", reflect.TypeOf((*MockPullRequestRepo)(nil).GetCodeHostUser), ctx, provider, login)
+}
+
+// GetCodeHostUsers mocks base method.
+func (m *MockPullRequestRepo) GetCodeHostUsers(ctx context.Context, provider entity0.Provider) ([]entity0.UserMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeHostUsers", ctx, provider)
+	ret0, _ := ret[0].([]entity0.UserMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeHostUsers indicates an expected call of GetCodeHostUsers.
+func (mr *MockPullRequestRepoMockRecorder) GetCodeHostUsers(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeHostUsers", reflect.TypeOf((*MockPullRequestRepo)(nil).GetCodeHostUsers), ctx, provider)
+}
+
+// GetCodeOwners mocks base method.
+func (m *MockPullRequestRepo) GetCodeOwners(ctx context.Context, teamID int) ([]entity1.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeOwners", ctx, teamID)
+	ret0, _ := ret[0].([]entity1.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeOwners indicates an expected call of GetCodeOwners.
+func (mr *MockPullRequestRepoMockRecorder) GetCodeOwners(ctx, teamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeOwners", reflect.TypeOf((*MockPullRequestRepo)(nil).GetCodeOwners), ctx, teamID)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockPullRequestRepo) GetDeliveries(ctx context.Context, status entity6.DeliveryStatus, limit int) ([]entity6.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, status, limit)
+	ret0, _ := ret[0].([]entity6.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockPullRequestRepoMockRecorder) GetDeliveries(ctx, status, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockPullRequestRepo)(nil).GetDeliveries), ctx, status, limit)
+}
+
+// GetDelivery mocks base method.
+func (m *MockPullRequestRepo) GetDelivery(ctx context.Context, id int) (*entity6.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, id)
+	ret0, _ := ret[0].(*entity6.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockPullRequestRepoMockRecorder) GetDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockPullRequestRepo)(nil).GetDelivery), ctx, id)
+}
+
+// GetEndingAvailability mocks base method.
+func (m *MockPullRequestRepo) GetEndingAvailability(ctx context.Context, now time.Time) ([]entity5.Availability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEndingAvailability", ctx, now)
+	ret0, _ := ret[0].([]entity5.Availability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEndingAvailability indicates an expected call of GetEndingAvailability.
+func (mr *MockPullRequestRepoMockRecorder) GetEndingAvailability(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEndingAvailability", reflect.TypeOf((*MockPullRequestRepo)(nil).GetEndingAvailability), ctx, now)
+}
+
+// GetLastOutboxSeq mocks base method.
+func (m *MockPullRequestRepo) GetLastOutboxSeq(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastOutboxSeq", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastOutboxSeq indicates an expected call of GetLastOutboxSeq.
+func (mr *MockPullRequestRepoMockRecorder) GetLastOutboxSeq(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastOutboxSeq", reflect.TypeOf((*MockPullRequestRepo)(nil).GetLastOutboxSeq), ctx)
+}
+
+// GetOutboxEvents mocks base method.
+func (m *MockPullRequestRepo) GetOutboxEvents(ctx context.Context, afterSeq int64, limit int) ([]entity2.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxEvents", ctx, afterSeq, limit)
+	ret0, _ := ret[0].([]entity2.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxEvents indicates an expected call of GetOutboxEvents.
+func (mr *MockPullRequestRepoMockRecorder) GetOutboxEvents(ctx, afterSeq, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxEvents", reflect.TypeOf((*MockPullRequestRepo)(nil).GetOutboxEvents), ctx, afterSeq, limit)
+}
+
 // GetPr mocks base method.
-func (m *MockPullRequestRepo) GetPr(ctx context.Context, prID string) (*entity.PullRequest, error) {
+func (m *MockPullRequestRepo) GetPr(ctx context.Context, prID string) (*entity3.PullRequest, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPr", ctx, prID)
-	ret0, _ := ret[0].(*entity.PullRequest)
+	ret0, _ := ret[0].(*entity3.PullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -109,11 +500,86 @@ func (mr *MockPullRequestRepoMockRecorder) GetPr(ctx, prID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPr", reflect.TypeOf((*MockPullRequestRepo)(nil).GetPr), ctx, prID)
 }
 
+// GetReviewLoad mocks base method.
+func (m *MockPullRequestRepo) GetReviewLoad(ctx context.Context, userIDs []string) (map[string]entity5.ReviewLoad, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewLoad", ctx, userIDs)
+	ret0, _ := ret[0].(map[string]entity5.ReviewLoad)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewLoad indicates an expected call of GetReviewLoad.
+func (mr *MockPullRequestRepoMockRecorder) GetReviewLoad(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewLoad", reflect.TypeOf((*MockPullRequestRepo)(nil).GetReviewLoad), ctx, userIDs)
+}
+
+// GetReviews mocks base method.
+func (m *MockPullRequestRepo) GetReviews(ctx context.Context, prID string) ([]entity3.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", ctx, prID)
+	ret0, _ := ret[0].([]entity3.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockPullRequestRepoMockRecorder) GetReviews(ctx, prID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockPullRequestRepo)(nil).GetReviews), ctx, prID)
+}
+
+// GetSLABreaches mocks base method.
+func (m *MockPullRequestRepo) GetSLABreaches(ctx context.Context, now time.Time, teamIDs []int) ([]entity3.SLABreach, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSLABreaches", ctx, now, teamIDs)
+	ret0, _ := ret[0].([]entity3.SLABreach)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSLABreaches indicates an expected call of GetSLABreaches.
+func (mr *MockPullRequestRepoMockRecorder) GetSLABreaches(ctx, now, teamIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSLABreaches", reflect.TypeOf((*MockPullRequestRepo)(nil).GetSLABreaches), ctx, now, teamIDs)
+}
+
+// GetStaleReviews mocks base method.
+func (m *MockPullRequestRepo) GetStaleReviews(ctx context.Context, now time.Time) ([]entity3.StaleReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaleReviews", ctx, now)
+	ret0, _ := ret[0].([]entity3.StaleReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaleReviews indicates an expected call of GetStaleReviews.
+func (mr *MockPullRequestRepoMockRecorder) GetStaleReviews(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaleReviews", reflect.TypeOf((*MockPullRequestRepo)(nil).GetStaleReviews), ctx, now)
+}
+
+// GetStartingAvailability mocks base method.
+func (m *MockPullRequestRepo) GetStartingAvailability(ctx context.Context, now time.Time) ([]entity5.Availability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStartingAvailability", ctx, now)
+	ret0, _ := ret[0].([]entity5.Availability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStartingAvailability indicates an expected call of GetStartingAvailability.
+func (mr *MockPullRequestRepoMockRecorder) GetStartingAvailability(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStartingAvailability", reflect.TypeOf((*MockPullRequestRepo)(nil).GetStartingAvailability), ctx, now)
+}
+
 // GetTeam mocks base method.
-func (m *MockPullRequestRepo) GetTeam(ctx context.Context, id int) (*entity0.Team, error) {
+func (m *MockPullRequestRepo) GetTeam(ctx context.Context, id int) (*entity4.Team, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTeam", ctx, id)
-	ret0, _ := ret[0].(*entity0.Team)
+	ret0, _ := ret[0].(*entity4.Team)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -125,10 +591,10 @@ func (mr *MockPullRequestRepoMockRecorder) GetTeam(ctx, id interface{}) *gomock.
 }
 
 // GetTeamByName mocks base method.
-func (m *MockPullRequestRepo) GetTeamByName(ctx context.Context, name string) (*entity0.Team, error) {
+func (m *MockPullRequestRepo) GetTeamByName(ctx context.Context, name string) (*entity4.Team, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTeamByName", ctx, name)
-	ret0, _ := ret[0].(*entity0.Team)
+	ret0, _ := ret[0].(*entity4.Team)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -139,11 +605,26 @@ func (mr *MockPullRequestRepoMockRecorder) GetTeamByName(ctx, name interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamByName", reflect.TypeOf((*MockPullRequestRepo)(nil).GetTeamByName), ctx, name)
 }
 
+// GetTeamLeads mocks base method.
+func (m *MockPullRequestRepo) GetTeamLeads(ctx context.Context, teamID int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamLeads", ctx, teamID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamLeads indicates an expected call of GetTeamLeads.
+func (mr *MockPullRequestRepoMockRecorder) GetTeamLeads(ctx, teamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamLeads", reflect.TypeOf((*MockPullRequestRepo)(nil).GetTeamLeads), ctx, teamID)
+}
+
 // GetTeamPr mocks base method.
-func (m *MockPullRequestRepo) GetTeamPr(ctx context.Context, teamID int) ([]entity.PullRequest, error) {
+func (m *MockPullRequestRepo) GetTeamPr(ctx context.Context, teamID int) ([]entity3.PullRequest, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTeamPr", ctx, teamID)
-	ret0, _ := ret[0].([]entity.PullRequest)
+	ret0, _ := ret[0].([]entity3.PullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -155,10 +636,10 @@ func (mr *MockPullRequestRepoMockRecorder) GetTeamPr(ctx, teamID interface{}) *g
 }
 
 // GetUserByID mocks base method.
-func (m *MockPullRequestRepo) GetUserByID(ctx context.Context, userID string) (*entity1.User, error) {
+func (m *MockPullRequestRepo) GetUserByID(ctx context.Context, userID string) (*entity5.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
-	ret0, _ := ret[0].(*entity1.User)
+	ret0, _ := ret[0].(*entity5.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -169,11 +650,56 @@ func (mr *MockPullRequestRepoMockRecorder) GetUserByID(ctx, userID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockPullRequestRepo)(nil).GetUserByID), ctx, userID)
 }
 
+// GetUserEmails mocks base method.
+func (m *MockPullRequestRepo) GetUserEmails(ctx context.Context, userIDs []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserEmails", ctx, userIDs)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserEmails indicates an expected call of GetUserEmails.
+func (mr *MockPullRequestRepoMockRecorder) GetUserEmails(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEmails", reflect.TypeOf((*MockPullRequestRepo)(nil).GetUserEmails), ctx, userIDs)
+}
+
+// GetUserGrants mocks base method.
+func (m *MockPullRequestRepo) GetUserGrants(ctx context.Context, userID string) ([]entity.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGrants", ctx, userID)
+	ret0, _ := ret[0].([]entity.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGrants indicates an expected call of GetUserGrants.
+func (mr *MockPullRequestRepoMockRecorder) GetUserGrants(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGrants", reflect.TypeOf((*MockPullRequestRepo)(nil).GetUserGrants), ctx, userID)
+}
+
+// GetUserTags mocks base method.
+func (m *MockPullRequestRepo) GetUserTags(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTags", ctx, userIDs)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTags indicates an expected call of GetUserTags.
+func (mr *MockPullRequestRepoMockRecorder) GetUserTags(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTags", reflect.TypeOf((*MockPullRequestRepo)(nil).GetUserTags), ctx, userIDs)
+}
+
 // GetUserWithTeam mocks base method.
-func (m *MockPullRequestRepo) GetUserWithTeam(ctx context.Context, userID string) (*entity1.User, string, error) {
+func (m *MockPullRequestRepo) GetUserWithTeam(ctx context.Context, userID string) (*entity5.User, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUserWithTeam", ctx, userID)
-	ret0, _ := ret[0].(*entity1.User)
+	ret0, _ := ret[0].(*entity5.User)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
@@ -186,10 +712,10 @@ func (mr *MockPullRequestRepoMockRecorder) GetUserWithTeam(ctx, userID interface
 }
 
 // GetUsersPr mocks base method.
-func (m *MockPullRequestRepo) GetUsersPr(ctx context.Context, userId string, onlyActive bool) ([]entity.PullRequest, error) {
+func (m *MockPullRequestRepo) GetUsersPr(ctx context.Context, userId string, onlyActive bool) ([]entity3.PullRequest, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUsersPr", ctx, userId, onlyActive)
-	ret0, _ := ret[0].([]entity.PullRequest)
+	ret0, _ := ret[0].([]entity3.PullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -200,6 +726,150 @@ func (mr *MockPullRequestRepoMockRecorder) GetUsersPr(ctx, userId, onlyActive in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersPr", reflect.TypeOf((*MockPullRequestRepo)(nil).GetUsersPr), ctx, userId, onlyActive)
 }
 
+// GetUsersWithEmail mocks base method.
+func (m *MockPullRequestRepo) GetUsersWithEmail(ctx context.Context) ([]entity5.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersWithEmail", ctx)
+	ret0, _ := ret[0].([]entity5.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersWithEmail indicates an expected call of GetUsersWithEmail.
+func (mr *MockPullRequestRepoMockRecorder) GetUsersWithEmail(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersWithEmail", reflect.TypeOf((*MockPullRequestRepo)(nil).GetUsersWithEmail), ctx)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockPullRequestRepo) GetWebhooks(ctx context.Context) ([]entity6.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx)
+	ret0, _ := ret[0].([]entity6.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockPullRequestRepoMockRecorder) GetWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockPullRequestRepo)(nil).GetWebhooks), ctx)
+}
+
+// GrantRole mocks base method.
+func (m *MockPullRequestRepo) GrantRole(ctx context.Context, userID string, g entity.Grant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantRole", ctx, userID, g)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantRole indicates an expected call of GrantRole.
+func (mr *MockPullRequestRepoMockRecorder) GrantRole(ctx, userID, g interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantRole", reflect.TypeOf((*MockPullRequestRepo)(nil).GrantRole), ctx, userID, g)
+}
+
+// MarkAvailabilityEnded mocks base method.
+func (m *MockPullRequestRepo) MarkAvailabilityEnded(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAvailabilityEnded", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAvailabilityEnded indicates an expected call of MarkAvailabilityEnded.
+func (mr *MockPullRequestRepoMockRecorder) MarkAvailabilityEnded(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAvailabilityEnded", reflect.TypeOf((*MockPullRequestRepo)(nil).MarkAvailabilityEnded), ctx, id)
+}
+
+// MarkAvailabilityStarted mocks base method.
+func (m *MockPullRequestRepo) MarkAvailabilityStarted(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAvailabilityStarted", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAvailabilityStarted indicates an expected call of MarkAvailabilityStarted.
+func (mr *MockPullRequestRepoMockRecorder) MarkAvailabilityStarted(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAvailabilityStarted", reflect.TypeOf((*MockPullRequestRepo)(nil).MarkAvailabilityStarted), ctx, id)
+}
+
+// MarkReviewOverdue mocks base method.
+func (m *MockPullRequestRepo) MarkReviewOverdue(ctx context.Context, b entity3.SLABreach, stage string, leads []string, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReviewOverdue", ctx, b, stage, leads, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkReviewOverdue indicates an expected call of MarkReviewOverdue.
+func (mr *MockPullRequestRepoMockRecorder) MarkReviewOverdue(ctx, b, stage, leads, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReviewOverdue", reflect.TypeOf((*MockPullRequestRepo)(nil).MarkReviewOverdue), ctx, b, stage, leads, now)
+}
+
+// MarkReviewerSyncPending mocks base method.
+func (m *MockPullRequestRepo) MarkReviewerSyncPending(ctx context.Context, prID string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReviewerSyncPending", ctx, prID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkReviewerSyncPending indicates an expected call of MarkReviewerSyncPending.
+func (mr *MockPullRequestRepoMockRecorder) MarkReviewerSyncPending(ctx, prID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReviewerSyncPending", reflect.TypeOf((*MockPullRequestRepo)(nil).MarkReviewerSyncPending), ctx, prID, now)
+}
+
+// PublishOutbox mocks base method.
+func (m *MockPullRequestRepo) PublishOutbox(ctx context.Context, limit int, publish func(context.Context, entity2.Event) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishOutbox", ctx, limit, publish)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishOutbox indicates an expected call of PublishOutbox.
+func (mr *MockPullRequestRepoMockRecorder) PublishOutbox(ctx, limit, publish interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutbox", reflect.TypeOf((*MockPullRequestRepo)(nil).PublishOutbox), ctx, limit, publish)
+}
+
+// ReleaseCodeHostDelivery mocks base method.
+func (m *MockPullRequestRepo) ReleaseCodeHostDelivery(ctx context.Context, provider entity0.Provider, deliveryID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseCodeHostDelivery", ctx, provider, deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseCodeHostDelivery indicates an expected call of ReleaseCodeHostDelivery.
+func (mr *MockPullRequestRepoMockRecorder) ReleaseCodeHostDelivery(ctx, provider, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseCodeHostDelivery", reflect.TypeOf((*MockPullRequestRepo)(nil).ReleaseCodeHostDelivery), ctx, provider, deliveryID)
+}
+
+// ReleaseDigest mocks base method.
+func (m *MockPullRequestRepo) ReleaseDigest(ctx context.Context, userID string, day time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseDigest", ctx, userID, day)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseDigest indicates an expected call of ReleaseDigest.
+func (mr *MockPullRequestRepoMockRecorder) ReleaseDigest(ctx, userID, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseDigest", reflect.TypeOf((*MockPullRequestRepo)(nil).ReleaseDigest), ctx, userID, day)
+}
+
 // RemoveReviewerFromAllPR mocks base method.
 func (m *MockPullRequestRepo) RemoveReviewerFromAllPR(ctx context.Context, reviewerID string) error {
 	m.ctrl.T.Helper()
@@ -214,8 +884,163 @@ func (mr *MockPullRequestRepoMockRecorder) RemoveReviewerFromAllPR(ctx, reviewer
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReviewerFromAllPR", reflect.TypeOf((*MockPullRequestRepo)(nil).RemoveReviewerFromAllPR), ctx, reviewerID)
 }
 
+// ReplaceCodeOwners mocks base method.
+func (m *MockPullRequestRepo) ReplaceCodeOwners(ctx context.Context, teamID int, rules []entity1.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceCodeOwners", ctx, teamID, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceCodeOwners indicates an expected call of ReplaceCodeOwners.
+func (mr *MockPullRequestRepoMockRecorder) ReplaceCodeOwners(ctx, teamID, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceCodeOwners", reflect.TypeOf((*MockPullRequestRepo)(nil).ReplaceCodeOwners), ctx, teamID, rules)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockPullRequestRepo) RevokeAPIKey(ctx context.Context, id int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockPullRequestRepoMockRecorder) RevokeAPIKey(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockPullRequestRepo)(nil).RevokeAPIKey), ctx, id, at)
+}
+
+// RevokeRole mocks base method.
+func (m *MockPullRequestRepo) RevokeRole(ctx context.Context, userID string, g entity.Grant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRole", ctx, userID, g)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *MockPullRequestRepoMockRecorder) RevokeRole(ctx, userID, g interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*MockPullRequestRepo)(nil).RevokeRole), ctx, userID, g)
+}
+
+// RotateRoundRobin mocks base method.
+func (m *MockPullRequestRepo) RotateRoundRobin(ctx context.Context, teamID int, next func(string) (string, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRoundRobin", ctx, teamID, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateRoundRobin indicates an expected call of RotateRoundRobin.
+func (mr *MockPullRequestRepoMockRecorder) RotateRoundRobin(ctx, teamID, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRoundRobin", reflect.TypeOf((*MockPullRequestRepo)(nil).RotateRoundRobin), ctx, teamID, next)
+}
+
+// RunExclusive mocks base method.
+func (m *MockPullRequestRepo) RunExclusive(ctx context.Context, lockID int64, fn func(context.Context) error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunExclusive", ctx, lockID, fn)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunExclusive indicates an expected call of RunExclusive.
+func (mr *MockPullRequestRepoMockRecorder) RunExclusive(ctx, lockID, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunExclusive", reflect.TypeOf((*MockPullRequestRepo)(nil).RunExclusive), ctx, lockID, fn)
+}
+
+// SetCodeHostUser mocks base method.
+func (m_2 *MockPullRequestRepo) SetCodeHostUser(ctx context.Context, m entity0.UserMapping) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SetCodeHostUser", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCodeHostUser indicates an expected call of SetCodeHostUser.
+func (mr *MockPullRequestRepoMockRecorder) SetCodeHostUser(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCodeHostUser", reflect.TypeOf((*MockPullRequestRepo)(nil).SetCodeHostUser), ctx, m)
+}
+
+// SetMaxOpenReviews mocks base method.
+func (m *MockPullRequestRepo) SetMaxOpenReviews(ctx context.Context, userID string, maxOpen int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaxOpenReviews", ctx, userID, maxOpen)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMaxOpenReviews indicates an expected call of SetMaxOpenReviews.
+func (mr *MockPullRequestRepoMockRecorder) SetMaxOpenReviews(ctx, userID, maxOpen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaxOpenReviews", reflect.TypeOf((*MockPullRequestRepo)(nil).SetMaxOpenReviews), ctx, userID, maxOpen)
+}
+
+// SetUserEmail mocks base method.
+func (m *MockPullRequestRepo) SetUserEmail(ctx context.Context, userID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserEmail", ctx, userID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserEmail indicates an expected call of SetUserEmail.
+func (mr *MockPullRequestRepoMockRecorder) SetUserEmail(ctx, userID, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserEmail", reflect.TypeOf((*MockPullRequestRepo)(nil).SetUserEmail), ctx, userID, email)
+}
+
+// SetUserTags mocks base method.
+func (m *MockPullRequestRepo) SetUserTags(ctx context.Context, userID string, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserTags", ctx, userID, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserTags indicates an expected call of SetUserTags.
+func (mr *MockPullRequestRepoMockRecorder) SetUserTags(ctx, userID, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTags", reflect.TypeOf((*MockPullRequestRepo)(nil).SetUserTags), ctx, userID, tags)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockPullRequestRepo) TouchAPIKey(ctx context.Context, id int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockPullRequestRepoMockRecorder) TouchAPIKey(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockPullRequestRepo)(nil).TouchAPIKey), ctx, id, at)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockPullRequestRepo) UpdateDelivery(ctx context.Context, d entity6.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockPullRequestRepoMockRecorder) UpdateDelivery(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockPullRequestRepo)(nil).UpdateDelivery), ctx, d)
+}
+
 // UpdatePr mocks base method.
-func (m *MockPullRequestRepo) UpdatePr(ctx context.Context, prId string, newPr entity.PullRequest) error {
+func (m *MockPullRequestRepo) UpdatePr(ctx context.Context, prId string, newPr entity3.PullRequest) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdatePr", ctx, prId, newPr)
 	ret0, _ := ret[0].(error)
@@ -228,8 +1053,36 @@ func (mr *MockPullRequestRepoMockRecorder) UpdatePr(ctx, prId, newPr interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePr", reflect.TypeOf((*MockPullRequestRepo)(nil).UpdatePr), ctx, prId, newPr)
 }
 
+// UpdateReviewerSync mocks base method.
+func (m *MockPullRequestRepo) UpdateReviewerSync(ctx context.Context, l entity0.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReviewerSync", ctx, l)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReviewerSync indicates an expected call of UpdateReviewerSync.
+func (mr *MockPullRequestRepoMockRecorder) UpdateReviewerSync(ctx, l interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReviewerSync", reflect.TypeOf((*MockPullRequestRepo)(nil).UpdateReviewerSync), ctx, l)
+}
+
+// UpdateTeamSettings mocks base method.
+func (m *MockPullRequestRepo) UpdateTeamSettings(ctx context.Context, team entity4.Team) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTeamSettings", ctx, team)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTeamSettings indicates an expected call of UpdateTeamSettings.
+func (mr *MockPullRequestRepoMockRecorder) UpdateTeamSettings(ctx, team interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeamSettings", reflect.TypeOf((*MockPullRequestRepo)(nil).UpdateTeamSettings), ctx, team)
+}
+
 // UpdateUser mocks base method.
-func (m *MockPullRequestRepo) UpdateUser(ctx context.Context, u entity1.User) error {
+func (m *MockPullRequestRepo) UpdateUser(ctx context.Context, u entity5.User) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateUser", ctx, u)
 	ret0, _ := ret[0].(error)
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+)
+
+// Notifier доставляет уведомления о PR людям (чат, почта)
+type Notifier interface {
+	Notify(ctx context.Context, n entityNotify.Notification) error
+}
+
+// DigestSender отправляет ежедневную сводку открытых ревью пользователю
+type DigestSender interface {
+	SendDigest(ctx context.Context, d entityNotify.Digest) error
+}
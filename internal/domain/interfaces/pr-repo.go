@@ -2,14 +2,21 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityOwners "github.com/JanArsMAI/PullRequestService/internal/domain/codeowners"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
 	entityPr "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
 	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
 	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
 )
 
 type PullRequestRepo interface {
-	AddTeam(ctx context.Context, name string, users []entityUser.User) error
+	AddTeam(ctx context.Context, team entityTeam.Team) error
+	UpdateTeamSettings(ctx context.Context, team entityTeam.Team) error
 	GetTeam(ctx context.Context, id int) (*entityTeam.Team, error)
 	GetTeamByName(ctx context.Context, name string) (*entityTeam.Team, error)
 	AddPR(ctx context.Context, pr entityPr.PullRequest) error
@@ -23,4 +30,61 @@ type PullRequestRepo interface {
 	AddReviewerToPR(ctx context.Context, prId string, reviewerID string) error
 	GetTeamPr(ctx context.Context, teamID int) ([]entityPr.PullRequest, error)
 	GetAllPRs(ctx context.Context) ([]entityPr.PullRequest, error)
+	GetReviewLoad(ctx context.Context, userIDs []string) (map[string]entityUser.ReviewLoad, error)
+	ReplaceCodeOwners(ctx context.Context, teamID int, rules []entityOwners.Rule) error
+	GetCodeOwners(ctx context.Context, teamID int) ([]entityOwners.Rule, error)
+	SetUserTags(ctx context.Context, userID string, tags []string) error
+	GetUserTags(ctx context.Context, userIDs []string) (map[string][]string, error)
+	AddAvailability(ctx context.Context, w entityUser.Availability) (int, error)
+	GetAvailability(ctx context.Context, userID string) ([]entityUser.Availability, error)
+	DeleteAvailability(ctx context.Context, userID string, id int) error
+	GetStartingAvailability(ctx context.Context, now time.Time) ([]entityUser.Availability, error)
+	GetEndingAvailability(ctx context.Context, now time.Time) ([]entityUser.Availability, error)
+	MarkAvailabilityStarted(ctx context.Context, id int) error
+	MarkAvailabilityEnded(ctx context.Context, id int) error
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpen int) error
+	SetUserEmail(ctx context.Context, userID string, email string) error
+	GetUserEmails(ctx context.Context, userIDs []string) (map[string]string, error)
+	GetUsersWithEmail(ctx context.Context) ([]entityUser.User, error)
+	ClaimDigest(ctx context.Context, userID string, day time.Time) (bool, error)
+	ReleaseDigest(ctx context.Context, userID string, day time.Time) error
+	GetSLABreaches(ctx context.Context, now time.Time, teamIDs []int) ([]entityPr.SLABreach, error)
+	MarkReviewOverdue(ctx context.Context, b entityPr.SLABreach, stage string, leads []string, now time.Time) (bool, error)
+	GetTeamLeads(ctx context.Context, teamID int) ([]string, error)
+	GetStaleReviews(ctx context.Context, now time.Time) ([]entityPr.StaleReview, error)
+	GetAssignments(ctx context.Context, prID string) ([]entityPr.Assignment, error)
+	RunExclusive(ctx context.Context, lockID int64, fn func(context.Context) error) (bool, error)
+	RotateRoundRobin(ctx context.Context, teamID int, next func(last string) (string, error)) error
+	AddReview(ctx context.Context, r entityPr.Review) (int, error)
+	GetReviews(ctx context.Context, prID string) ([]entityPr.Review, error)
+	GetUserGrants(ctx context.Context, userID string) ([]entityAuth.Grant, error)
+	GrantRole(ctx context.Context, userID string, g entityAuth.Grant) error
+	RevokeRole(ctx context.Context, userID string, g entityAuth.Grant) error
+	AddAPIKey(ctx context.Context, k entityAuth.APIKey) (int, error)
+	GetAPIKeys(ctx context.Context) ([]entityAuth.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*entityAuth.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int, at time.Time) error
+	TouchAPIKey(ctx context.Context, id int, at time.Time) error
+	AddWebhook(ctx context.Context, w entityHook.Subscription) (int, error)
+	GetWebhooks(ctx context.Context) ([]entityHook.Subscription, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	AddDeliveries(ctx context.Context, deliveries []entityHook.Delivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entityHook.Delivery, error)
+	UpdateDelivery(ctx context.Context, d entityHook.Delivery) error
+	GetDeliveries(ctx context.Context, status entityHook.DeliveryStatus, limit int) ([]entityHook.Delivery, error)
+	GetDelivery(ctx context.Context, id int) (*entityHook.Delivery, error)
+	PublishOutbox(ctx context.Context, limit int, publish func(context.Context, entityEvent.Event) error) (int, error)
+	GetOutboxEvents(ctx context.Context, afterSeq int64, limit int) ([]entityEvent.Event, error)
+	GetLastOutboxSeq(ctx context.Context) (int64, error)
+	SetCodeHostUser(ctx context.Context, m entityHost.UserMapping) error
+	DeleteCodeHostUser(ctx context.Context, provider entityHost.Provider, login string) error
+	GetCodeHostUser(ctx context.Context, provider entityHost.Provider, login string) (*entityHost.UserMapping, error)
+	GetCodeHostUsers(ctx context.Context, provider entityHost.Provider) ([]entityHost.UserMapping, error)
+	ClaimCodeHostDelivery(ctx context.Context, provider entityHost.Provider, deliveryID string) (bool, error)
+	ReleaseCodeHostDelivery(ctx context.Context, provider entityHost.Provider, deliveryID string) error
+	AddCodeHostLink(ctx context.Context, l entityHost.Link) error
+	MarkReviewerSyncPending(ctx context.Context, prID string, now time.Time) error
+	ClaimDueReviewerSyncs(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entityHost.Link, error)
+	UpdateReviewerSync(ctx context.Context, l entityHost.Link) error
+	GetCodeHostLogins(ctx context.Context, provider entityHost.Provider, userIDs []string) (map[string]string, error)
 }
@@ -2,10 +2,16 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityOwners "github.com/JanArsMAI/PullRequestService/internal/domain/codeowners"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
 	entityPr "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
 	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
 	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
 	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
 )
 
@@ -13,12 +19,49 @@ type PrService interface {
 	AddTeam(ctx context.Context, teamDto *dto.AddTeamRequest) error
 	ReassignPullRequest(ctx context.Context, activePr entityPr.PullRequest, user entityUser.User) error
 	GetTeam(ctx context.Context, teamName string) (*entityTeam.Team, error)
+	UpdateTeamSettings(ctx context.Context, settings dto.TeamSettingsRequest) (*entityTeam.Team, error)
 	SetUserActive(ctx context.Context, userID string, isActive bool) error
 	GetUserWithTeam(ctx context.Context, userID string) (*entityUser.User, string, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpen int) (*entityUser.User, error)
+	SetUserEmail(ctx context.Context, userID string, email string) (*entityUser.User, error)
+	SetUserTags(ctx context.Context, req dto.UserTagsRequest) (*entityUser.User, error)
+	GetUserTags(ctx context.Context, userID string) (*entityUser.User, error)
+	AddAvailability(ctx context.Context, req dto.AvailabilityRequest) (*entityUser.Availability, error)
+	GetAvailability(ctx context.Context, userID string) ([]entityUser.Availability, error)
+	DeleteAvailability(ctx context.Context, userID string, id int) error
+	ApplyAvailability(ctx context.Context, now time.Time) error
 	CreatePR(ctx context.Context, prDto dto.CreatePR) (*entityPr.PullRequest, error)
 	GetUsersPr(ctx context.Context, userID string) ([]entityPr.PullRequest, error)
-	Merge(ctx context.Context, userID string, prId string) (*entityPr.PullRequest, error)
-	Reassign(ctx context.Context, prID, oldReviewerID string) (*entityPr.PullRequest, string, error)
+	GetPullRequest(ctx context.Context, prID string) (*entityPr.PullRequest, error)
+	GetPullRequestHistory(ctx context.Context, prID string) ([]entityPr.Assignment, error)
+	Merge(ctx context.Context, prId string) (*entityPr.PullRequest, error)
+	Ready(ctx context.Context, req dto.PullRequestLifecycleRequest) (*entityPr.PullRequest, error)
+	Close(ctx context.Context, prID string) (*entityPr.PullRequest, error)
+	Reopen(ctx context.Context, req dto.PullRequestLifecycleRequest) (*entityPr.PullRequest, error)
+	ToDraft(ctx context.Context, prID string) (*entityPr.PullRequest, error)
+	SubmitReview(ctx context.Context, req dto.ReviewRequest) (*entityPr.Review, error)
+	Reassign(ctx context.Context, prID, oldReviewerID, preferredID string) (*entityPr.PullRequest, string, error)
+	CheckReviewSLA(ctx context.Context, now time.Time) error
+	GetSLABreaches(ctx context.Context, teamName string) ([]entityPr.SLABreach, error)
+	ReassignStaleReviews(ctx context.Context, now time.Time) (int, error)
 	GetStatistics(ctx context.Context) (map[string]int, map[string]int, error)
 	Deactivate(ctx context.Context, teamName string, userIDs []string) error
+	GrantRole(ctx context.Context, req dto.RoleRequest) ([]entityAuth.Grant, error)
+	RevokeRole(ctx context.Context, req dto.RoleRequest) ([]entityAuth.Grant, error)
+	GetUserRoles(ctx context.Context, userID string) ([]entityAuth.Grant, error)
+	IssueAPIKey(ctx context.Context, req dto.IssueAPIKeyRequest) (*entityAuth.APIKey, string, error)
+	GetAPIKeys(ctx context.Context) ([]entityAuth.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+	AddWebhook(ctx context.Context, req dto.WebhookRequest) (*entityHook.Subscription, error)
+	GetWebhooks(ctx context.Context) ([]entityHook.Subscription, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, status string) ([]entityHook.Delivery, error)
+	Redeliver(ctx context.Context, id int) (*entityHook.Delivery, error)
+	MapCodeHostUser(ctx context.Context, req dto.CodeHostUserRequest) (*entityHost.UserMapping, error)
+	UnmapCodeHostUser(ctx context.Context, req dto.CodeHostUserRequest) error
+	GetCodeHostUsers(ctx context.Context, provider string) ([]entityHost.UserMapping, error)
+	HandleCodeHostEvent(ctx context.Context, e entityHost.PullRequestEvent) (*entityPr.PullRequest, error)
+	EventFilter(ctx context.Context, req dto.EventStreamRequest) (*entityEvent.Filter, error)
+	UploadCodeOwners(ctx context.Context, req dto.UploadCodeOwnersRequest) ([]entityOwners.Rule, error)
+	TestCodeOwners(ctx context.Context, req dto.TestCodeOwnersRequest) (map[string][]entityOwners.Owner, error)
 }
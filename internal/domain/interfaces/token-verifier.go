@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+)
+
+// TokenVerifier проверяет токен доступа и возвращает вызывающего,
+// при неверном токене возвращает ErrInvalidToken, при просроченном - ErrTokenExpired
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (entityAuth.Principal, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
+)
+
+// WebhookSender отправляет доставку подписчику и возвращает HTTP код ответа,
+// ответ не 2xx считается ошибкой
+type WebhookSender interface {
+	Send(ctx context.Context, d entityHook.Delivery) (int, error)
+}
@@ -0,0 +1,64 @@
+package entity
+
+import "time"
+
+// Kind - повод уведомления
+type Kind string
+
+const (
+	// KindAssigned - ревьюеров назначили на PR
+	KindAssigned Kind = "assigned"
+	// KindReassigned - ревьюера заменили другим через reassign
+	KindReassigned Kind = "reassigned"
+	// KindMerged - PR смержен
+	KindMerged Kind = "merged"
+	// KindNeedMoreReviewers - PR не хватает ревьюеров
+	KindNeedMoreReviewers Kind = "need_more_reviewers"
+	// KindReviewReminder - ревьюер не оставил ревью в срок SLA команды
+	KindReviewReminder Kind = "review_reminder"
+	// KindReviewEscalated - прошёл второй срок SLA, сообщение тимлидам
+	KindReviewEscalated Kind = "review_escalated"
+)
+
+var Kinds = []Kind{KindAssigned, KindReassigned, KindMerged, KindNeedMoreReviewers, KindReviewReminder, KindReviewEscalated}
+
+// Notification - сообщение о PR для людей. Recipients - кому оно адресовано: назначенные ревьюеры,
+// для мержа - автор и ревьюеры, для нехватки ревьюеров - автор, для эскалации просрочки ревью - тимлиды
+type Notification struct {
+	Kind     Kind
+	TeamID   int
+	TeamName string
+	PrID     string
+	PrName   string
+	AuthorID string
+	// Recipients - id пользователей сервиса, которых касается сообщение
+	Recipients []string
+	// Removed - снятые ревьюеры, для KindReassigned
+	Removed        []string
+	Reviewers      []string
+	MergedBy       string
+	NeedMoreReason string
+	Reason         string
+	// ReviewerID и AssignedAt - просрочивший ревьюер и когда он назначен, для напоминаний и эскалаций
+	ReviewerID string
+	AssignedAt time.Time
+	// Emails - адреса получателей, у которых он задан (user_id -> email)
+	Emails map[string]string
+}
+
+// Digest - ежедневная сводка открытых ревью пользователя
+type Digest struct {
+	UserID   string
+	UserName string
+	Email    string
+	Date     time.Time
+	// PullRequests - открытые PR, где пользователь ревьюер, от старых к новым
+	PullRequests []DigestItem
+}
+
+type DigestItem struct {
+	PrID      string
+	PrName    string
+	AuthorID  string
+	CreatedAt time.Time
+}
@@ -3,16 +3,64 @@ package entity
 import (
 	"time"
 
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
 	entity "github.com/JanArsMAI/PullRequestService/internal/domain/user"
 )
 
+// Причины, по которым PR не хватает ревьюеров
+const (
+	ReasonNotEnoughCandidates = "NOT_ENOUGH_CANDIDATES"
+	ReasonReviewersAtCapacity = "REVIEWERS_AT_CAPACITY"
+)
+
 type PullRequest struct {
 	Id                string
 	Name              string
 	Author            entity.User
 	Reviewers         []entity.User
-	Status            string
+	Status            Status
 	NeedMoreReviewers bool
-	CreatedAt         time.Time
-	MergedAt          *time.Time
+	// NeedMoreReason - причина need_more_reviewers, пустая, если ревьюеров хватает
+	NeedMoreReason string
+	CreatedAt      time.Time
+	MergedAt       *time.Time
+	// MergedBy - кто смержил PR
+	MergedBy string
+	// ReviewBypassed - PR смержен администратором без нужных одобрений
+	ReviewBypassed bool
+	// ReviewerPools - id команды, из пула которой назначен ревьюер (reviewer id -> team id)
+	ReviewerPools map[string]int
+	// RequiredTags - навыки, которые нужны для ревью, ревьюеры с ними выбираются в первую очередь
+	RequiredTags []string
+	// CodeHost - PR у провайдера и состояние переноса ревьюеров туда, nil - PR создан не из вебхука
+	CodeHost *entityHost.Link
+}
+
+// PoolOf возвращает команду, из которой был назначен ревьюер
+func (p *PullRequest) PoolOf(reviewerID string) (int, bool) {
+	teamID, ok := p.ReviewerPools[reviewerID]
+	if !ok || teamID == 0 {
+		return 0, false
+	}
+	return teamID, true
+}
+
+func (p *PullRequest) SetPool(reviewerID string, teamID int) {
+	if p.ReviewerPools == nil {
+		p.ReviewerPools = make(map[string]int)
+	}
+	p.ReviewerPools[reviewerID] = teamID
+}
+
+// UpdateNeedMoreReason выставляет причину по текущему флагу NeedMoreReviewers,
+// atCapacity - были ли кандидаты, пропущенные из-за лимита открытых ревью
+func (p *PullRequest) UpdateNeedMoreReason(atCapacity bool) {
+	switch {
+	case !p.NeedMoreReviewers:
+		p.NeedMoreReason = ""
+	case atCapacity:
+		p.NeedMoreReason = ReasonReviewersAtCapacity
+	default:
+		p.NeedMoreReason = ReasonNotEnoughCandidates
+	}
 }
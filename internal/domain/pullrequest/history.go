@@ -0,0 +1,73 @@
+package entity
+
+import "time"
+
+// AssignmentAction - что произошло с ревьюером PR, хранится в reviewer_assignments.action
+type AssignmentAction string
+
+const (
+	AssignmentAssigned AssignmentAction = "assigned"
+	AssignmentRemoved  AssignmentAction = "removed"
+	// AssignmentReplaced - ревьюера сняли и вместо него назначили ReplacedBy
+	AssignmentReplaced AssignmentAction = "replaced"
+)
+
+// SystemActor - автор изменения, сделанного фоновой задачей сервиса без вызывающего
+const SystemActor = "system"
+
+// Assignment - запись истории ревьюеров PR, записи только добавляются
+type Assignment struct {
+	Id         int64
+	PrID       string
+	ReviewerID string
+	Action     AssignmentAction
+	// ReplacedBy - новый ревьюер для AssignmentReplaced
+	ReplacedBy string
+	// Reason - причина изменения, как reason в pr.reviewers_changed, для назначения при создании PR - create
+	Reason string
+	// Actor - кто сделал изменение: subject токена, провайдер для вебхуков или SystemActor
+	Actor string
+	At    time.Time
+}
+
+// ReviewerDiff возвращает ревьюеров after, которых не было в before, и ревьюеров before, которых нет в after
+func ReviewerDiff(before, after PullRequest) (added, removed []string) {
+	was := make(map[string]struct{}, len(before.Reviewers))
+	for _, r := range before.Reviewers {
+		was[r.Id] = struct{}{}
+	}
+	is := make(map[string]struct{}, len(after.Reviewers))
+	for _, r := range after.Reviewers {
+		is[r.Id] = struct{}{}
+		if _, ok := was[r.Id]; !ok {
+			added = append(added, r.Id)
+		}
+	}
+	for _, r := range before.Reviewers {
+		if _, ok := is[r.Id]; !ok {
+			removed = append(removed, r.Id)
+		}
+	}
+	return added, removed
+}
+
+// AssignmentChanges превращает назначенных и снятых одним изменением ревьюеров в записи истории:
+// если одного ревьюера заменили другим, снятый записывается как replaced с ReplacedBy
+func AssignmentChanges(prID string, added, removed []string, reason, actor string) []Assignment {
+	res := make([]Assignment, 0, len(added)+len(removed))
+	replacedBy := ""
+	if len(added) == 1 && len(removed) == 1 {
+		replacedBy = added[0]
+	}
+	for _, id := range removed {
+		a := Assignment{PrID: prID, ReviewerID: id, Action: AssignmentRemoved, Reason: reason, Actor: actor}
+		if replacedBy != "" {
+			a.Action, a.ReplacedBy = AssignmentReplaced, replacedBy
+		}
+		res = append(res, a)
+	}
+	for _, id := range added {
+		res = append(res, Assignment{PrID: prID, ReviewerID: id, Action: AssignmentAssigned, Reason: reason, Actor: actor})
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// Verdict - итог ревью, оставленного ревьюером
+type Verdict string
+
+const (
+	VerdictApproved         Verdict = "APPROVED"
+	VerdictChangesRequested Verdict = "CHANGES_REQUESTED"
+	// VerdictCommented - комментарий без решения, не меняет предыдущий вердикт ревьюера
+	VerdictCommented Verdict = "COMMENTED"
+)
+
+func (v Verdict) IsValid() bool {
+	switch v {
+	case VerdictApproved, VerdictChangesRequested, VerdictCommented:
+		return true
+	}
+	return false
+}
+
+type Review struct {
+	Id         int
+	PrID       string
+	ReviewerID string
+	Verdict    Verdict
+	Body       string
+	CreatedAt  time.Time
+}
+
+// ReviewState - состояние ревью PR по последним вердиктам назначенных ревьюеров
+type ReviewState struct {
+	Approvals        int
+	ChangesRequested bool
+}
+
+// Summarize считает одобрения и запросы изменений назначенных ревьюеров,
+// reviews должны идти в порядке создания, учитывается последний вердикт каждого ревьюера
+func Summarize(reviews []Review, reviewerIDs []string) ReviewState {
+	assigned := make(map[string]bool, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		assigned[id] = true
+	}
+	latest := make(map[string]Verdict)
+	for _, r := range reviews {
+		if r.Verdict == VerdictCommented || !assigned[r.ReviewerID] {
+			continue
+		}
+		latest[r.ReviewerID] = r.Verdict
+	}
+	var state ReviewState
+	for _, v := range latest {
+		switch v {
+		case VerdictApproved:
+			state.Approvals++
+		case VerdictChangesRequested:
+			state.ChangesRequested = true
+		}
+	}
+	return state
+}
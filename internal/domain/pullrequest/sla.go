@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+)
+
+// SLABreach - назначенный ревьюер открытого PR, который не оставил ни одного ревью за срок команды автора
+type SLABreach struct {
+	PrID       string
+	PrName     string
+	AuthorID   string
+	TeamID     int
+	TeamName   string
+	ReviewerID string
+	AssignedAt time.Time
+	SLA        entityTeam.SLA
+	// RemindedAt и EscalatedAt - когда ревьюеру напомнили и когда сработал второй срок, nil - ещё нет
+	RemindedAt  *time.Time
+	EscalatedAt *time.Time
+}
+
+// Deadline - срок первого ревью
+func (b SLABreach) Deadline() time.Time {
+	return b.AssignedAt.Add(time.Duration(b.SLA.ReviewHours) * time.Hour)
+}
+
+// EscalationDeadline - второй срок, нулевое время - второго срока нет
+func (b SLABreach) EscalationDeadline() time.Time {
+	if b.SLA.EscalateHours == 0 {
+		return time.Time{}
+	}
+	return b.AssignedAt.Add(time.Duration(b.SLA.EscalateHours) * time.Hour)
+}
+
+// NeedsReminder - ревьюеру ещё не напоминали
+func (b SLABreach) NeedsReminder() bool {
+	return b.RemindedAt == nil
+}
+
+// NeedsEscalation - второй срок прошёл, а действие ещё не выполнялось
+func (b SLABreach) NeedsEscalation(now time.Time) bool {
+	deadline := b.EscalationDeadline()
+	return b.EscalatedAt == nil && !deadline.IsZero() && !now.Before(deadline)
+}
+
+// StaleReview - назначенный ревьюер открытого PR, который не оставил ни одного ревью за stale_review_hours
+// команды автора и должен быть заменён
+type StaleReview struct {
+	PrID       string
+	TeamID     int
+	ReviewerID string
+	AssignedAt time.Time
+}
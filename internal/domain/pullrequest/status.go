@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Status - состояние PR в жизненном цикле
+type Status string
+
+const (
+	// StatusDraft - черновик, ревьюеры не назначаются, пока PR не готов к ревью
+	StatusDraft Status = "DRAFT"
+	StatusOpen  Status = "OPEN"
+	// StatusMerged - конечное состояние
+	StatusMerged Status = "MERGED"
+	// StatusClosed - PR закрыт без мержа, ревьюеры освобождены, можно переоткрыть
+	StatusClosed Status = "CLOSED"
+)
+
+var ErrInvalidTransition = errors.New("invalid PR status transition")
+
+// transitions - разрешённые переходы между состояниями
+var transitions = map[Status][]Status{
+	StatusDraft:  {StatusOpen, StatusClosed},
+	StatusOpen:   {StatusMerged, StatusClosed, StatusDraft},
+	StatusClosed: {StatusOpen},
+	StatusMerged: {},
+}
+
+func (s Status) IsValid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo переводит PR в состояние next, если переход разрешён
+func (p *PullRequest) TransitionTo(next Status) error {
+	if !p.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, p.Status, next)
+	}
+	p.Status = next
+	return nil
+}
@@ -2,8 +2,85 @@ package entity
 
 import entity "github.com/JanArsMAI/PullRequestService/internal/domain/user"
 
+// стратегии выбора ревьюеров, хранятся в teams.reviewer_strategy
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+	StrategyWeighted    = "weighted"
+)
+
+// количество ревьюеров на PR, если для команды не задано иное
+const DefaultRequiredReviewers = 2
+
+// количество одобрений, нужных для мержа PR, если для команды не задано иное
+const DefaultRequiredApprovals = 1
+
+// действия после второго срока SLA, хранятся в teams.sla_action
+const (
+	// SLAActionEscalate - сообщить о просрочке тимлидам команды
+	SLAActionEscalate = "escalate"
+	// SLAActionReassign - заменить ревьюера так же, как при reassign, если замены нет - сообщить тимлидам
+	SLAActionReassign = "reassign"
+)
+
+// SLA - сроки ревью команды. ReviewHours - за сколько часов после назначения ревьюер должен оставить
+// первое ревью, 0 - SLA выключен, после него ревьюеру приходит напоминание. EscalateHours - второй срок
+// от назначения, после которого выполняется Action, 0 - только напоминание
+type SLA struct {
+	ReviewHours   int
+	EscalateHours int
+	Action        string
+}
+
+func (s SLA) Enabled() bool {
+	return s.ReviewHours > 0
+}
+
+// IsValid проверяет сроки: второй срок не раньше первого, действие известно
+func (s SLA) IsValid() bool {
+	if s.ReviewHours < 0 || s.EscalateHours < 0 {
+		return false
+	}
+	if s.EscalateHours > 0 && (s.ReviewHours == 0 || s.EscalateHours <= s.ReviewHours) {
+		return false
+	}
+	return s.Action == SLAActionEscalate || s.Action == SLAActionReassign
+}
+
 type Team struct {
-	Id    int
-	Name  string
-	Users []entity.User
+	Id                int
+	Name              string
+	ReviewerStrategy  string
+	RequiredReviewers int
+	RequiredApprovals int
+	// SLA - сроки ревью PR команды
+	SLA SLA
+	// StaleReviewHours - через сколько часов после назначения ревьюер без ревью заменяется автоматически, 0 - не заменяется
+	StaleReviewHours int
+	Users            []entity.User
+}
+
+// ReviewersNeeded возвращает требуемое число ревьюеров на PR команды
+func (t Team) ReviewersNeeded() int {
+	if t.RequiredReviewers <= 0 {
+		return DefaultRequiredReviewers
+	}
+	return t.RequiredReviewers
+}
+
+// ApprovalsNeeded возвращает число одобрений, без которых PR команды нельзя смержить
+func (t Team) ApprovalsNeeded() int {
+	if t.RequiredApprovals <= 0 {
+		return DefaultRequiredApprovals
+	}
+	return t.RequiredApprovals
+}
+
+func IsValidStrategy(strategy string) bool {
+	switch strategy {
+	case StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded, StrategyWeighted:
+		return true
+	}
+	return false
 }
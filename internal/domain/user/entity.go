@@ -1,8 +1,55 @@
 package entity
 
+import (
+	"net/mail"
+	"time"
+)
+
 type User struct {
 	Id       string
 	Name     string
 	IsActive bool
 	TeamID   int
+	// Tags - навыки пользователя (go, sql, frontend), по ним ревьюеры подбираются к PR
+	Tags []string
+	// MaxOpenReviews - сколько открытых ревью пользователь может вести одновременно, 0 - без ограничения
+	MaxOpenReviews int
+	// Email - адрес для писем о назначениях и ежедневной сводки ревью, пустой - письма не отправляются
+	Email string
+}
+
+// ReviewLoad - текущая нагрузка пользователя как ревьюера
+type ReviewLoad struct {
+	OpenReviews    int
+	LastAssignedAt *time.Time
+}
+
+// Availability - окно недоступности пользователя (отпуск, дежурство),
+// внутри окна пользователь считается неактивным и не назначается ревьюером
+type Availability struct {
+	Id     int
+	UserID string
+	From   time.Time
+	To     time.Time
+	Reason string
+}
+
+func (a Availability) Covers(t time.Time) bool {
+	return !t.Before(a.From) && t.Before(a.To)
+}
+
+// Unavailable проверяет, попадает ли момент t хотя бы в одно из окон
+func Unavailable(windows []Availability, t time.Time) bool {
+	for _, w := range windows {
+		if w.Covers(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidEmail проверяет, что строка - один адрес без имени, например alice@example.com
+func IsValidEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
 }
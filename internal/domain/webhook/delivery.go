@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryFailed    DeliveryStatus = "FAILED"
+)
+
+// Параметры повторных попыток: 30s, 1m, 2m, ... но не больше часа между попытками
+const (
+	MaxAttempts = 8
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Delivery - доставка одного события одному подписчику
+type Delivery struct {
+	Id             int
+	SubscriptionID int
+	// URL и Secret подписчика, заполняются при выборке доставок к отправке
+	URL           string
+	Secret        string
+	EventID       string
+	EventType     entityEvent.Type
+	Payload       []byte
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	ResponseCode  int
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+// Backoff возвращает паузу перед следующей попыткой после attempt неудачных
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+func (d *Delivery) Succeeded(now time.Time, code int) {
+	d.Attempts++
+	d.Status = DeliveryDelivered
+	d.ResponseCode = code
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// Failed записывает неудачную попытку, после MaxAttempts доставка считается проваленной
+func (d *Delivery) Failed(now time.Time, code int, reason string) {
+	d.Attempts++
+	d.ResponseCode = code
+	d.LastError = reason
+	if d.Attempts >= MaxAttempts {
+		d.Status = DeliveryFailed
+		return
+	}
+	d.Status = DeliveryPending
+	d.NextAttemptAt = now.Add(Backoff(d.Attempts))
+}
+
+// Redeliver ставит проваленную доставку в очередь заново с полным числом попыток
+func (d *Delivery) Redeliver(now time.Time) {
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.LastError = ""
+}
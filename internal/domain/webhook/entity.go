@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+)
+
+// Заголовки запроса доставки
+const (
+	HeaderSignature = "X-PRS-Signature"
+	HeaderEvent     = "X-PRS-Event"
+	HeaderDelivery  = "X-PRS-Delivery"
+)
+
+// Subscription - подписчик на события. TeamID = 0 - подписка на события всех команд,
+// пустой Events - на все типы событий
+type Subscription struct {
+	Id        int
+	TeamID    int
+	TeamName  string
+	URL       string
+	Secret    string
+	Events    []entityEvent.Type
+	CreatedAt time.Time
+}
+
+func (s Subscription) Matches(e entityEvent.Event) bool {
+	if s.TeamID != 0 && s.TeamID != e.TeamID {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, t := range s.Events {
+		if t == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign подписывает тело запроса секретом подписчика: HMAC-SHA256 от "<timestamp>.<body>".
+// Значение заголовка X-PRS-Signature - "t=<timestamp>,v1=<hex>", timestamp защищает от повторной отправки
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos"
+)
+
+// lastUsedPrecision - last_used_at обновляется не чаще раза в минуту, чтобы не писать в БД на каждый запрос
+const lastUsedPrecision = time.Minute
+
+// APIKeySource - откуда берутся выпущенные API ключи
+type APIKeySource interface {
+	GetAPIKeyByHash(ctx context.Context, hash string) (*entityAuth.APIKey, error)
+	TouchAPIKey(ctx context.Context, id int, at time.Time) error
+}
+
+type apiKeyVerifier struct {
+	next interfaces.TokenVerifier
+	keys APIKeySource
+	now  func() time.Time
+}
+
+// WithAPIKeys принимает API ключи (с префиксом prs_) наравне с токенами пользователей,
+// остальные токены передаются в next
+func WithAPIKeys(next interfaces.TokenVerifier, keys APIKeySource, now func() time.Time) interfaces.TokenVerifier {
+	return &apiKeyVerifier{next: next, keys: keys, now: now}
+}
+
+func (v *apiKeyVerifier) Verify(ctx context.Context, token string) (entityAuth.Principal, error) {
+	if !strings.HasPrefix(token, entityAuth.APIKeyPrefix) {
+		return v.next.Verify(ctx, token)
+	}
+	key, err := v.keys.GetAPIKeyByHash(ctx, entityAuth.HashAPIKey(token))
+	if err != nil {
+		if errors.Is(err, repos.ErrAPIKeyNotFound) {
+			return entityAuth.Principal{}, entityAuth.ErrInvalidToken
+		}
+		return entityAuth.Principal{}, fmt.Errorf("failed to load api key: %w", err)
+	}
+	now := v.now().UTC()
+	if key.Expired(now) {
+		return entityAuth.Principal{}, entityAuth.ErrTokenExpired
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedPrecision {
+		if err := v.keys.TouchAPIKey(ctx, key.Id, now); err != nil {
+			return entityAuth.Principal{}, fmt.Errorf("failed to record use of api key %d: %w", key.Id, err)
+		}
+	}
+	return key.Principal(), nil
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+)
+
+// GrantSource - откуда берутся роли пользователей, выданные в БД
+type GrantSource interface {
+	GetUserGrants(ctx context.Context, userID string) ([]entityAuth.Grant, error)
+}
+
+type storedGrantsVerifier struct {
+	next   interfaces.TokenVerifier
+	grants GrantSource
+}
+
+// WithStoredGrants дополняет роли из токена ролями, выданными пользователю в БД
+func WithStoredGrants(next interfaces.TokenVerifier, grants GrantSource) interfaces.TokenVerifier {
+	return &storedGrantsVerifier{next: next, grants: grants}
+}
+
+func (v *storedGrantsVerifier) Verify(ctx context.Context, token string) (entityAuth.Principal, error) {
+	p, err := v.next.Verify(ctx, token)
+	if err != nil || p.UserID == "" {
+		return p, err
+	}
+	grants, err := v.grants.GetUserGrants(ctx, p.UserID)
+	if err != nil {
+		return entityAuth.Principal{}, fmt.Errorf("failed to load roles of %s: %w", p.UserID, err)
+	}
+	return p.WithGrants(grants), nil
+}
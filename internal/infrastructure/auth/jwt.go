@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+)
+
+// поддерживаемые алгоритмы подписи
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// переменная окружения с секретом HS256, если он не задан в config.yaml
+const secretEnv = "JWT_SECRET"
+
+// minSecretLen - минимальная длина секрета HS256 в байтах, короткий секрет подбирается перебором
+const minSecretLen = 32
+
+// placeholderSecrets - секреты из примеров и документации, с ними токен может выпустить кто угодно
+var placeholderSecrets = map[string]struct{}{
+	"change-me":           {},
+	"changeme":            {},
+	"secret":              {},
+	"jwt-secret":          {},
+	"your-256-bit-secret": {},
+}
+
+var ErrInvalidConfig = errors.New("invalid auth config")
+
+// JWTVerifier проверяет подписанные JWT одним заранее выбранным алгоритмом,
+// алгоритм из заголовка токена должен с ним совпадать (токены с alg=none и подменой алгоритма отклоняются)
+type JWTVerifier struct {
+	alg      string
+	secret   []byte
+	key      *rsa.PublicKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+type Option func(*JWTVerifier)
+
+// WithIssuer требует совпадения claim iss
+func WithIssuer(issuer string) Option {
+	return func(v *JWTVerifier) { v.issuer = issuer }
+}
+
+// WithAudience требует, чтобы claim aud содержал audience
+func WithAudience(audience string) Option {
+	return func(v *JWTVerifier) { v.audience = audience }
+}
+
+// WithLeeway задаёт допустимое расхождение часов при проверке exp и nbf
+func WithLeeway(leeway time.Duration) Option {
+	return func(v *JWTVerifier) { v.leeway = leeway }
+}
+
+// WithClock подменяет текущее время (например, в тестах)
+func WithClock(now func() time.Time) Option {
+	return func(v *JWTVerifier) { v.now = now }
+}
+
+func NewHS256Verifier(secret []byte, opts ...Option) (*JWTVerifier, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("%w: empty HS256 secret", ErrInvalidConfig)
+	}
+	return newVerifier(&JWTVerifier{alg: AlgHS256, secret: secret}, opts), nil
+}
+
+func NewRS256Verifier(key *rsa.PublicKey, opts ...Option) (*JWTVerifier, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%w: empty RS256 public key", ErrInvalidConfig)
+	}
+	return newVerifier(&JWTVerifier{alg: AlgRS256, key: key}, opts), nil
+}
+
+func newVerifier(v *JWTVerifier, opts []Option) *JWTVerifier {
+	v.now = time.Now
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// NewVerifierFromConfig создаёт проверку токенов по секции auth из config.yaml
+func NewVerifierFromConfig(cfg config.AuthConfig) (*JWTVerifier, error) {
+	opts := []Option{WithIssuer(cfg.Issuer), WithAudience(cfg.Audience), WithLeeway(cfg.Leeway)}
+	switch strings.ToUpper(cfg.Algorithm) {
+	case AlgHS256, "":
+		secret := cfg.Secret
+		if secret == "" {
+			secret = os.Getenv(secretEnv)
+		}
+		if err := checkSecret(secret); err != nil {
+			return nil, err
+		}
+		return NewHS256Verifier([]byte(secret), opts...)
+	case AlgRS256:
+		key, err := loadPublicKey(cfg.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		return NewRS256Verifier(key, opts...)
+	default:
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidConfig, cfg.Algorithm)
+	}
+}
+
+// checkSecret отклоняет пустой, известный по примерам или слишком короткий секрет HS256
+func checkSecret(secret string) error {
+	if secret == "" {
+		return fmt.Errorf("%w: empty HS256 secret", ErrInvalidConfig)
+	}
+	if _, ok := placeholderSecrets[strings.ToLower(secret)]; ok {
+		return fmt.Errorf("%w: HS256 secret is a placeholder, set %s to a random value", ErrInvalidConfig, secretEnv)
+	}
+	if len(secret) < minSecretLen {
+		return fmt.Errorf("%w: HS256 secret must be at least %d bytes", ErrInvalidConfig, minSecretLen)
+	}
+	return nil
+}
+
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read public key: %v", ErrInvalidConfig, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: public key is not PEM encoded", ErrInvalidConfig)
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse public key: %v", ErrInvalidConfig, err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: public key is not RSA", ErrInvalidConfig)
+	}
+	return key, nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+func (v *JWTVerifier) Verify(_ context.Context, token string) (entityAuth.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return entityAuth.Principal{}, entityAuth.ErrInvalidToken
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != v.alg {
+		return entityAuth.Principal{}, entityAuth.ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return entityAuth.Principal{}, entityAuth.ErrInvalidToken
+	}
+	if !v.verifySignature(parts[0]+"."+parts[1], sig) {
+		return entityAuth.Principal{}, entityAuth.ErrInvalidToken
+	}
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return entityAuth.Principal{}, entityAuth.ErrInvalidToken
+	}
+	if err := v.validate(claims); err != nil {
+		return entityAuth.Principal{}, err
+	}
+	return principalFromClaims(claims), nil
+}
+
+func (v *JWTVerifier) verifySignature(signingInput string, sig []byte) bool {
+	switch v.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signingInput))
+		return hmac.Equal(mac.Sum(nil), sig)
+	case AlgRS256:
+		digest := sha256.Sum256([]byte(signingInput))
+		return rsa.VerifyPKCS1v15(v.key, crypto.SHA256, digest[:], sig) == nil
+	}
+	return false
+}
+
+func (v *JWTVerifier) validate(c Claims) error {
+	now := v.now()
+	if c.Subject == "" || c.ExpiresAt == 0 {
+		return entityAuth.ErrInvalidToken
+	}
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(v.leeway)) {
+		return entityAuth.ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return entityAuth.ErrInvalidToken
+	}
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return entityAuth.ErrInvalidToken
+	}
+	if v.audience != "" && !c.Audience.contains(v.audience) {
+		return entityAuth.ErrInvalidToken
+	}
+	return nil
+}
+
+// principalFromClaims переводит claims в вызывающего, неизвестные роли пропускаются,
+// без ролей вызывающий считается обычным пользователем
+func principalFromClaims(c Claims) entityAuth.Principal {
+	p := entityAuth.Principal{Subject: c.Subject, UserID: c.Subject}
+	for _, r := range c.Roles {
+		switch role := entityAuth.Role(strings.ToLower(r)); role {
+		case entityAuth.RoleAdmin, entityAuth.RoleUser:
+			p.Roles = append(p.Roles, role)
+		}
+	}
+	if len(p.Roles) == 0 {
+		p.Roles = []entityAuth.Role{entityAuth.RoleUser}
+	}
+	return p
+}
+
+func decodeSegment(seg string, dst any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
@@ -0,0 +1,69 @@
+package codehost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultTimeout - сколько ждать ответа API провайдера
+const defaultTimeout = 10 * time.Second
+
+// apiClient - общая часть клиентов REST API: JSON запросы с заголовками авторизации провайдера
+type apiClient struct {
+	client  *http.Client
+	baseURL string
+	header  http.Header
+}
+
+func newAPIClient(client *http.Client, baseURL string, header http.Header) apiClient {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return apiClient{client: client, baseURL: strings.TrimRight(baseURL, "/"), header: header}
+}
+
+// do отправляет запрос с JSON телом in (если не nil) и разбирает ответ в out (если не nil), ответ не 2xx - ошибка
+func (c apiClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", "PullRequestService")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("%s %s: provider responded with %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: failed to decode response: %w", method, path, err)
+	}
+	return nil
+}
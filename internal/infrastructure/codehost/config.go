@@ -0,0 +1,43 @@
+package codehost
+
+import (
+	"os"
+
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+)
+
+// Переменные окружения с секретами, если они не заданы в конфиге
+const (
+	githubSecretEnv   = "GITHUB_WEBHOOK_SECRET"
+	gitlabTokenEnv    = "GITLAB_WEBHOOK_TOKEN"
+	githubAPITokenEnv = "GITHUB_API_TOKEN"
+	gitlabAPITokenEnv = "GITLAB_API_TOKEN"
+)
+
+// NewReceiversFromConfig создаёт приёмники вебхуков. Без секрета приёмник отклоняет все запросы
+func NewReceiversFromConfig(cfg config.CodeHostConfig) (*GitHubReceiver, *GitLabReceiver) {
+	secret := orEnv(cfg.GitHubSecret, githubSecretEnv)
+	token := orEnv(cfg.GitLabToken, gitlabTokenEnv)
+	return NewGitHubReceiver(secret), NewGitLabReceiver(token)
+}
+
+// NewClientsFromConfig создаёт клиенты API провайдеров, у которых задан токен
+func NewClientsFromConfig(cfg config.CodeHostConfig) map[entityHost.Provider]interfaces.CodeHostClient {
+	clients := make(map[entityHost.Provider]interfaces.CodeHostClient)
+	if token := orEnv(cfg.GitHubAPIToken, githubAPITokenEnv); token != "" {
+		clients[entityHost.ProviderGitHub] = NewGitHubClient(nil, cfg.GitHubAPIURL, token)
+	}
+	if token := orEnv(cfg.GitLabAPIToken, gitlabAPITokenEnv); token != "" {
+		clients[entityHost.ProviderGitLab] = NewGitLabClient(nil, cfg.GitLabAPIURL, token)
+	}
+	return clients
+}
+
+func orEnv(value, env string) string {
+	if value == "" {
+		return os.Getenv(env)
+	}
+	return value
+}
@@ -0,0 +1,72 @@
+package codehost
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+)
+
+// FakeCall - один вызов FakeClient
+type FakeCall struct {
+	Method string
+	PrID   string
+	Logins []string
+}
+
+// FakeClient вместо провайдера хранит запрошенных ревьюеров в памяти, для тестов и локального запуска.
+// Если задан Err, все вызовы возвращают его и ничего не меняют
+type FakeClient struct {
+	mu        sync.Mutex
+	reviewers map[string][]string
+	calls     []FakeCall
+	Err       error
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{reviewers: make(map[string][]string)}
+}
+
+func (c *FakeClient) RequestReviewers(_ context.Context, link entityHost.Link, logins []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, FakeCall{Method: "request", PrID: link.PrID, Logins: slices.Clone(logins)})
+	if c.Err != nil {
+		return c.Err
+	}
+	for _, login := range logins {
+		if !slices.Contains(c.reviewers[link.PrID], login) {
+			c.reviewers[link.PrID] = append(c.reviewers[link.PrID], login)
+		}
+	}
+	return nil
+}
+
+func (c *FakeClient) RemoveReviewers(_ context.Context, link entityHost.Link, logins []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, FakeCall{Method: "remove", PrID: link.PrID, Logins: slices.Clone(logins)})
+	if c.Err != nil {
+		return c.Err
+	}
+	c.reviewers[link.PrID] = slices.DeleteFunc(c.reviewers[link.PrID], func(login string) bool {
+		return slices.Contains(logins, login)
+	})
+	return nil
+}
+
+// Reviewers - логины, у которых сейчас запрошено ревью PR, в отсортированном виде
+func (c *FakeClient) Reviewers(prID string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := slices.Clone(c.reviewers[prID])
+	slices.Sort(res)
+	return res
+}
+
+func (c *FakeClient) Calls() []FakeCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.calls)
+}
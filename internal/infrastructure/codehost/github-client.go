@@ -0,0 +1,54 @@
+package codehost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+var ErrUnknownRepo = errors.New("repository name of the pull request is unknown")
+
+// GitHubClient запрашивает ревью через REST API GitHub: /repos/{owner}/{repo}/pulls/{number}/requested_reviewers
+type GitHubClient struct {
+	api apiClient
+}
+
+// NewGitHubClient - baseURL пустой для github.com, для GitHub Enterprise - "https://<host>/api/v3"
+func NewGitHubClient(client *http.Client, baseURL, token string) *GitHubClient {
+	if baseURL == "" {
+		baseURL = githubAPIURL
+	}
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return &GitHubClient{api: newAPIClient(client, baseURL, header)}
+}
+
+type githubReviewersRequest struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+func (c *GitHubClient) RequestReviewers(ctx context.Context, link entityHost.Link, logins []string) error {
+	return c.reviewers(ctx, http.MethodPost, link, logins)
+}
+
+func (c *GitHubClient) RemoveReviewers(ctx context.Context, link entityHost.Link, logins []string) error {
+	return c.reviewers(ctx, http.MethodDelete, link, logins)
+}
+
+func (c *GitHubClient) reviewers(ctx context.Context, method string, link entityHost.Link, logins []string) error {
+	if len(logins) == 0 {
+		return nil
+	}
+	if link.Repo == "" {
+		return ErrUnknownRepo
+	}
+	path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", link.Repo, link.Number)
+	return c.api.do(ctx, method, path, githubReviewersRequest{Reviewers: logins}, nil)
+}
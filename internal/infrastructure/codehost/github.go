@@ -0,0 +1,110 @@
+package codehost
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+)
+
+// Заголовки вебхуков GitHub
+const (
+	githubHeaderEvent     = "X-GitHub-Event"
+	githubHeaderDelivery  = "X-GitHub-Delivery"
+	githubHeaderSignature = "X-Hub-Signature-256"
+)
+
+// GitHubReceiver проверяет X-Hub-Signature-256 (HMAC-SHA256 тела с секретом вебхука)
+// и разбирает события pull_request
+type GitHubReceiver struct {
+	secret []byte
+}
+
+func NewGitHubReceiver(secret string) *GitHubReceiver {
+	return &GitHubReceiver{secret: []byte(secret)}
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubPayload struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int        `json:"number"`
+		Title  string     `json:"title"`
+		Draft  bool       `json:"draft"`
+		Merged bool       `json:"merged"`
+		User   githubUser `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		Id       int64  `json:"id"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender githubUser `json:"sender"`
+}
+
+func (r *GitHubReceiver) Parse(header http.Header, body []byte) (*entityHost.PullRequestEvent, error) {
+	if !r.verify(header.Get(githubHeaderSignature), body) {
+		return nil, entityHost.ErrInvalidSignature
+	}
+	if header.Get(githubHeaderEvent) != "pull_request" {
+		return nil, entityHost.ErrIgnoredEvent
+	}
+	deliveryID := header.Get(githubHeaderDelivery)
+	var payload githubPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", entityHost.ErrMalformedPayload, err)
+	}
+	if deliveryID == "" || payload.Repository.Id == 0 || payload.PullRequest.Number == 0 {
+		return nil, entityHost.ErrMalformedPayload
+	}
+	var action entityHost.Action
+	switch payload.Action {
+	case "opened":
+		action = entityHost.ActionOpened
+	case "closed":
+		action = entityHost.ActionClosed
+		if payload.PullRequest.Merged {
+			action = entityHost.ActionMerged
+		}
+	case "reopened":
+		action = entityHost.ActionReopened
+	case "converted_to_draft":
+		action = entityHost.ActionDraft
+	case "ready_for_review":
+		action = entityHost.ActionReady
+	default:
+		return nil, entityHost.ErrIgnoredEvent
+	}
+	return &entityHost.PullRequestEvent{
+		Provider:    entityHost.ProviderGitHub,
+		DeliveryID:  deliveryID,
+		Action:      action,
+		RepoID:      payload.Repository.Id,
+		Repo:        payload.Repository.FullName,
+		Number:      payload.PullRequest.Number,
+		Title:       payload.PullRequest.Title,
+		AuthorLogin: payload.PullRequest.User.Login,
+		SenderLogin: payload.Sender.Login,
+		Draft:       payload.PullRequest.Draft,
+	}, nil
+}
+
+func (r *GitHubReceiver) verify(signature string, body []byte) bool {
+	if len(r.secret) == 0 {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
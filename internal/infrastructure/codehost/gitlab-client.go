@@ -0,0 +1,122 @@
+package codehost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+)
+
+const gitlabAPIURL = "https://gitlab.com/api/v4"
+
+// GitLabClient меняет ревьюеров merge request через REST API GitLab. API принимает только полный
+// список reviewer_ids, поэтому клиент читает текущих ревьюеров, а логины переводит в id через /users
+type GitLabClient struct {
+	api apiClient
+}
+
+// NewGitLabClient - baseURL пустой для gitlab.com, для своей инсталляции - "https://<host>/api/v4"
+func NewGitLabClient(client *http.Client, baseURL, token string) *GitLabClient {
+	if baseURL == "" {
+		baseURL = gitlabAPIURL
+	}
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", token)
+	return &GitLabClient{api: newAPIClient(client, baseURL, header)}
+}
+
+type gitlabUser struct {
+	Id       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type gitlabMergeRequest struct {
+	Reviewers []gitlabUser `json:"reviewers"`
+}
+
+type gitlabReviewersRequest struct {
+	ReviewerIDs []int64 `json:"reviewer_ids"`
+}
+
+func (c *GitLabClient) RequestReviewers(ctx context.Context, link entityHost.Link, logins []string) error {
+	if len(logins) == 0 {
+		return nil
+	}
+	current, err := c.mergeRequestReviewers(ctx, link)
+	if err != nil {
+		return err
+	}
+	ids := make([]int64, 0, len(current)+len(logins))
+	has := make(map[string]bool, len(current))
+	for _, u := range current {
+		ids = append(ids, u.Id)
+		has[u.Username] = true
+	}
+	for _, login := range logins {
+		if has[login] {
+			continue
+		}
+		id, err := c.userID(ctx, login)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == len(current) {
+		return nil
+	}
+	return c.setReviewers(ctx, link, ids)
+}
+
+func (c *GitLabClient) RemoveReviewers(ctx context.Context, link entityHost.Link, logins []string) error {
+	if len(logins) == 0 {
+		return nil
+	}
+	current, err := c.mergeRequestReviewers(ctx, link)
+	if err != nil {
+		return err
+	}
+	remove := make(map[string]bool, len(logins))
+	for _, login := range logins {
+		remove[login] = true
+	}
+	ids := make([]int64, 0, len(current))
+	for _, u := range current {
+		if !remove[u.Username] {
+			ids = append(ids, u.Id)
+		}
+	}
+	if len(ids) == len(current) {
+		return nil
+	}
+	return c.setReviewers(ctx, link, ids)
+}
+
+func (c *GitLabClient) mergeRequestPath(link entityHost.Link) string {
+	return fmt.Sprintf("/projects/%d/merge_requests/%d", link.RepoID, link.Number)
+}
+
+func (c *GitLabClient) mergeRequestReviewers(ctx context.Context, link entityHost.Link) ([]gitlabUser, error) {
+	var mr gitlabMergeRequest
+	if err := c.api.do(ctx, http.MethodGet, c.mergeRequestPath(link), nil, &mr); err != nil {
+		return nil, err
+	}
+	return mr.Reviewers, nil
+}
+
+func (c *GitLabClient) setReviewers(ctx context.Context, link entityHost.Link, ids []int64) error {
+	return c.api.do(ctx, http.MethodPut, c.mergeRequestPath(link), gitlabReviewersRequest{ReviewerIDs: ids}, nil)
+}
+
+func (c *GitLabClient) userID(ctx context.Context, login string) (int64, error) {
+	var users []gitlabUser
+	if err := c.api.do(ctx, http.MethodGet, "/users?username="+url.QueryEscape(login), nil, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("gitlab user %s not found", login)
+	}
+	return users[0].Id, nil
+}
@@ -0,0 +1,107 @@
+package codehost
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+)
+
+// Заголовки вебхуков GitLab
+const (
+	gitlabHeaderEvent    = "X-Gitlab-Event"
+	gitlabHeaderDelivery = "X-Gitlab-Event-UUID"
+	gitlabHeaderToken    = "X-Gitlab-Token"
+)
+
+// GitLabReceiver сверяет X-Gitlab-Token с секретным токеном вебхука и разбирает события merge request
+type GitLabReceiver struct {
+	token []byte
+}
+
+func NewGitLabReceiver(token string) *GitLabReceiver {
+	return &GitLabReceiver{token: []byte(token)}
+}
+
+type gitlabChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+type gitlabPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		Id                int64  `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		Iid    int    `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+		Draft  bool   `json:"draft"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *gitlabChange `json:"draft"`
+	} `json:"changes"`
+}
+
+func (r *GitLabReceiver) Parse(header http.Header, body []byte) (*entityHost.PullRequestEvent, error) {
+	token := []byte(header.Get(gitlabHeaderToken))
+	if len(r.token) == 0 || subtle.ConstantTimeCompare(token, r.token) != 1 {
+		return nil, entityHost.ErrInvalidSignature
+	}
+	if header.Get(gitlabHeaderEvent) != "Merge Request Hook" {
+		return nil, entityHost.ErrIgnoredEvent
+	}
+	deliveryID := header.Get(gitlabHeaderDelivery)
+	var payload gitlabPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", entityHost.ErrMalformedPayload, err)
+	}
+	attrs := payload.ObjectAttributes
+	if deliveryID == "" || payload.ObjectKind != "merge_request" || payload.Project.Id == 0 || attrs.Iid == 0 {
+		return nil, entityHost.ErrMalformedPayload
+	}
+	var action entityHost.Action
+	switch attrs.Action {
+	case "open":
+		action = entityHost.ActionOpened
+	case "merge":
+		action = entityHost.ActionMerged
+	case "close":
+		action = entityHost.ActionClosed
+	case "reopen":
+		action = entityHost.ActionReopened
+	case "update":
+		// в GitLab смена черновика приходит обычным update с changes.draft
+		change := payload.Changes.Draft
+		if change == nil || change.Previous == change.Current {
+			return nil, entityHost.ErrIgnoredEvent
+		}
+		action = entityHost.ActionReady
+		if change.Current {
+			action = entityHost.ActionDraft
+		}
+	default:
+		return nil, entityHost.ErrIgnoredEvent
+	}
+	// в payload GitLab у автора есть только числовой author_id, MR открывает сам автор,
+	// поэтому для open автором считается пользователь из поля user
+	return &entityHost.PullRequestEvent{
+		Provider:    entityHost.ProviderGitLab,
+		DeliveryID:  deliveryID,
+		Action:      action,
+		RepoID:      payload.Project.Id,
+		Repo:        payload.Project.PathWithNamespace,
+		Number:      attrs.Iid,
+		Title:       attrs.Title,
+		AuthorLogin: payload.User.Username,
+		SenderLogin: payload.User.Username,
+		Draft:       attrs.Draft,
+	}, nil
+}
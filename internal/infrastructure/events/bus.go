@@ -0,0 +1,45 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+)
+
+// Bus - публикация событий внутри процесса: событие получает каждый подписчик.
+// Если хотя бы один подписчик вернул ошибку, relay опубликует событие ещё раз всем,
+// поэтому подписчики должны спокойно переносить повторы (например, по Event.Id)
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []interfaces.EventPublisher
+}
+
+func NewBus(subscribers ...interfaces.EventPublisher) *Bus {
+	return &Bus{subscribers: subscribers}
+}
+
+func (b *Bus) Subscribe(subscriber interfaces.EventPublisher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber)
+}
+
+func (b *Bus) Publish(ctx context.Context, e entityEvent.Event) error {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+	var errs []error
+	for _, s := range subscribers {
+		if err := s.Publish(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to publish %s: %w", e.Type, errors.Join(errs...))
+	}
+	return nil
+}
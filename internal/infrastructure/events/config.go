@@ -0,0 +1,45 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+)
+
+const (
+	PublisherInProcess = "inprocess"
+	PublisherStdout    = "stdout"
+	PublisherFile      = "file"
+)
+
+var ErrInvalidConfig = errors.New("invalid events config")
+
+// NewPublisherFromConfig выбирает, куда relay публикует события из outbox.
+// События всегда идут в bus (вебхуки и другие подписчики), stdout и file дополнительно пишут их JSON строками,
+// чтобы отладочный вывод не отключал подписчиков. Вторым значением возвращается функция, освобождающая ресурсы публикатора
+func NewPublisherFromConfig(cfg config.EventsConfig, bus *Bus) (interfaces.EventPublisher, func() error, error) {
+	noop := func() error { return nil }
+	switch strings.ToLower(cfg.Publisher) {
+	case PublisherInProcess, "":
+		return bus, noop, nil
+	case PublisherStdout:
+		bus.Subscribe(NewWriterPublisher(os.Stdout))
+		return bus, noop, nil
+	case PublisherFile:
+		if cfg.Path == "" {
+			return nil, nil, fmt.Errorf("%w: path is required for file publisher", ErrInvalidConfig)
+		}
+		writer, closeFile, err := NewFilePublisher(cfg.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		bus.Subscribe(writer)
+		return bus, closeFile, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported publisher %q", ErrInvalidConfig, cfg.Publisher)
+	}
+}
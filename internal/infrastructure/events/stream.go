@@ -0,0 +1,98 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+)
+
+// subscriberBuffer - сколько событий ждёт медленного подписчика, прежде чем его канал закроется
+const subscriberBuffer = 64
+
+// Stream - журнал последних событий в памяти и рассылка новых подписчикам потока (SSE).
+// События приходят из outbox через application.StreamFeed с номером Seq, по нему клиент продолжает поток.
+// Журнал ограничен size событиями, повторно опубликованные события (тот же Seq) отбрасываются
+type Stream struct {
+	mu     sync.Mutex
+	size   int
+	log    []entityEvent.Event
+	seen   map[int64]struct{}
+	subs   map[chan entityEvent.Event]struct{}
+	closed bool
+}
+
+func NewStream(size int) *Stream {
+	return &Stream{
+		size: size,
+		log:  make([]entityEvent.Event, 0, size),
+		seen: make(map[int64]struct{}, size),
+		subs: make(map[chan entityEvent.Event]struct{}),
+	}
+}
+
+func (s *Stream) Publish(_ context.Context, e entityEvent.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[e.Seq]; ok && e.Seq != 0 {
+		return nil
+	}
+	if len(s.log) == s.size {
+		delete(s.seen, s.log[0].Seq)
+		s.log = append(s.log[:0], s.log[1:]...)
+	}
+	s.log = append(s.log, e)
+	s.seen[e.Seq] = struct{}{}
+	for ch := range s.subs {
+		select {
+		case ch <- e:
+		default:
+			// подписчик отстал: поток обрывается, клиент переподключится с Last-Event-ID
+			delete(s.subs, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+func (s *Stream) Subscribe(lastSeq int64) ([]entityEvent.Event, bool, <-chan entityEvent.Event, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	found := lastSeq == 0
+	var backlog []entityEvent.Event
+	if lastSeq != 0 {
+		for i, e := range s.log {
+			if e.Seq == lastSeq {
+				found = true
+				backlog = append(backlog, s.log[i+1:]...)
+				break
+			}
+		}
+	}
+	ch := make(chan entityEvent.Event, subscriberBuffer)
+	if s.closed {
+		close(ch)
+	} else {
+		s.subs[ch] = struct{}{}
+	}
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subs[ch]; ok {
+			delete(s.subs, ch)
+			close(ch)
+		}
+	}
+	return backlog, found, ch, cancel
+}
+
+// Close закрывает каналы всех подписчиков, чтобы открытые потоки завершились при остановке сервиса
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for ch := range s.subs {
+		delete(s.subs, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
+)
+
+// WriterPublisher пишет каждое событие отдельной JSON строкой, удобно для тестов и отладки
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// NewFilePublisher дописывает события в конец файла path
+func NewFilePublisher(path string) (*WriterPublisher, func() error, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+	return NewWriterPublisher(f), f.Close, nil
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, e entityEvent.Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", e.Id, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write event %s: %w", e.Id, err)
+	}
+	return nil
+}
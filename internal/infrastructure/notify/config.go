@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+)
+
+const (
+	NotifierNone  = "none"
+	NotifierSlack = "slack"
+	NotifierEmail = "email"
+)
+
+// slackWebhookEnv - переменная окружения с webhook по умолчанию, если он не задан в конфиге
+const slackWebhookEnv = "SLACK_WEBHOOK_URL"
+
+// smtpPasswordEnv - переменная окружения с паролем SMTP, если он не задан в конфиге
+const smtpPasswordEnv = "SMTP_PASSWORD"
+
+// defaultDigestAt - время ежедневной сводки по UTC, если оно не задано в конфиге
+const defaultDigestAt = "09:00"
+
+var ErrInvalidConfig = errors.New("invalid notifications config")
+
+// NewNotifierFromConfig выбирает, куда отправляются уведомления ревьюерам
+func NewNotifierFromConfig(cfg config.NotificationsConfig) (interfaces.Notifier, error) {
+	var notifiers Multi
+	for _, name := range notifierNames(cfg.Notifier) {
+		switch name {
+		case NotifierNone:
+		case NotifierSlack:
+			slack := cfg.Slack
+			if slack.WebhookURL == "" {
+				slack.WebhookURL = os.Getenv(slackWebhookEnv)
+			}
+			notifier, err := NewSlackNotifier(nil, slack)
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, notifier)
+		case NotifierEmail:
+			notifier, err := newEmailNotifierFromConfig(cfg.Email)
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, notifier)
+		default:
+			return nil, fmt.Errorf("%w: unknown notifier %q", ErrInvalidConfig, name)
+		}
+	}
+	switch len(notifiers) {
+	case 0:
+		return Noop{}, nil
+	case 1:
+		return notifiers[0], nil
+	default:
+		return notifiers, nil
+	}
+}
+
+// NewDigestFromConfig возвращает отправителя ежедневной сводки и её время от начала суток по UTC,
+// если почта среди notifier, иначе nil
+func NewDigestFromConfig(cfg config.NotificationsConfig) (interfaces.DigestSender, time.Duration, error) {
+	if !slices.Contains(notifierNames(cfg.Notifier), NotifierEmail) {
+		return nil, 0, nil
+	}
+	at, err := ParseDigestAt(cfg.Email.DigestAt)
+	if err != nil {
+		return nil, 0, err
+	}
+	sender, err := newEmailNotifierFromConfig(cfg.Email)
+	if err != nil {
+		return nil, 0, err
+	}
+	return sender, at, nil
+}
+
+// ParseDigestAt разбирает время сводки в формате 15:04, пустая строка - 09:00
+func ParseDigestAt(s string) (time.Duration, error) {
+	if s == "" {
+		s = defaultDigestAt
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: digest_at %q must be HH:MM", ErrInvalidConfig, s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func newEmailNotifierFromConfig(cfg config.EmailConfig) (*EmailNotifier, error) {
+	if cfg.Password == "" {
+		cfg.Password = os.Getenv(smtpPasswordEnv)
+	}
+	return NewEmailNotifier(cfg)
+}
+
+// notifierNames разбирает список notifier через запятую, пустой список - none
+func notifierNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
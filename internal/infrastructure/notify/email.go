@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+)
+
+// defaultSMTPPort - порт SMTP сервера, если он не задан в конфиге
+const defaultSMTPPort = 25
+
+// EmailNotifier отправляет письма через SMTP: сразу - о назначении ревьюером, раз в день - сводку открытых ревью.
+// Письмо уходит каждому получателю отдельно, получатели без адреса пропускаются
+type EmailNotifier struct {
+	host    string
+	addr    string
+	from    string
+	auth    smtp.Auth
+	timeout time.Duration
+}
+
+func NewEmailNotifier(cfg config.EmailConfig) (*EmailNotifier, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("%w: email host is required", ErrInvalidConfig)
+	}
+	if !entityUser.IsValidEmail(cfg.From) {
+		return nil, fmt.Errorf("%w: invalid email from %q", ErrInvalidConfig, cfg.From)
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		// PlainAuth отказывается отправлять пароль без TLS, кроме localhost
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return &EmailNotifier{
+		host:    cfg.Host,
+		addr:    net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		from:    cfg.From,
+		auth:    auth,
+		timeout: defaultTimeout,
+	}, nil
+}
+
+// Notify отправляет письма о назначении, переназначении и просрочке ревью, об остальном почта не пишет
+func (e *EmailNotifier) Notify(ctx context.Context, n entityNotify.Notification) error {
+	var subject, text string
+	switch n.Kind {
+	case entityNotify.KindAssigned:
+		subject = fmt.Sprintf("Review requested: %s %q", n.PrID, n.PrName)
+		text = fmt.Sprintf("You were assigned to review %s %q by %s.", n.PrID, n.PrName, n.AuthorID)
+	case entityNotify.KindReassigned:
+		subject = fmt.Sprintf("Review requested: %s %q", n.PrID, n.PrName)
+		text = fmt.Sprintf("You now review %s %q by %s instead of %s.", n.PrID, n.PrName, n.AuthorID, strings.Join(n.Removed, ", "))
+	case entityNotify.KindReviewReminder:
+		subject = fmt.Sprintf("Review overdue: %s %q", n.PrID, n.PrName)
+		text = fmt.Sprintf("%s %q by %s is waiting for your review since %s UTC.", n.PrID, n.PrName, n.AuthorID, n.AssignedAt.UTC().Format(time.DateTime))
+	case entityNotify.KindReviewEscalated:
+		subject = fmt.Sprintf("Review overdue: %s %q", n.PrID, n.PrName)
+		text = fmt.Sprintf("%s has not reviewed %s %q by %s since %s UTC.", n.ReviewerID, n.PrID, n.PrName, n.AuthorID, n.AssignedAt.UTC().Format(time.DateTime))
+	default:
+		return nil
+	}
+	var errs []error
+	for _, userID := range n.Recipients {
+		to, ok := n.Emails[userID]
+		if !ok {
+			continue
+		}
+		if err := e.send(ctx, to, subject, text); err != nil {
+			errs = append(errs, fmt.Errorf("mail to %s: %w", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SendDigest отправляет сводку открытых ревью пользователя
+func (e *EmailNotifier) SendDigest(ctx context.Context, d entityNotify.Digest) error {
+	day := d.Date.Format(time.DateOnly)
+	var text strings.Builder
+	fmt.Fprintf(&text, "Hi %s, you have %d open review(s) on %s, oldest first:\n\n", d.UserName, len(d.PullRequests), day)
+	for _, pr := range d.PullRequests {
+		fmt.Fprintf(&text, "- %s %q by %s, open since %s\n", pr.PrID, pr.PrName, pr.AuthorID, pr.CreatedAt.UTC().Format(time.DateOnly))
+	}
+	return e.send(ctx, d.Email, fmt.Sprintf("Open reviews for %s: %d", day, len(d.PullRequests)), text.String())
+}
+
+func (e *EmailNotifier) send(ctx context.Context, to, subject, text string) error {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", e.addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+	c, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: e.host}); err != nil {
+			return err
+		}
+	}
+	if e.auth != nil {
+		if err := c.Auth(e.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(e.message(to, subject, text)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message собирает письмо text/plain, тема кодируется, чтобы название PR не могло добавить заголовки
+func (e *EmailNotifier) message(to, subject, text string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(text, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// FakeMail - письмо, принятое FakeSMTPServer. Username - логин из AUTH PLAIN, пустой без авторизации
+type FakeMail struct {
+	From     string
+	To       []string
+	Data     string
+	Username string
+}
+
+// FakeSMTPServer - SMTP сервер на 127.0.0.1 для тестов: принимает любые письма и запоминает их
+type FakeSMTPServer struct {
+	ln    net.Listener
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	mails []FakeMail
+}
+
+func NewFakeSMTPServer() (*FakeSMTPServer, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &FakeSMTPServer{ln: ln}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+func (s *FakeSMTPServer) Host() string {
+	return s.ln.Addr().(*net.TCPAddr).IP.String()
+}
+
+func (s *FakeSMTPServer) Port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *FakeSMTPServer) Mails() []FakeMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]FakeMail(nil), s.mails...)
+}
+
+func (s *FakeSMTPServer) Close() error {
+	err := s.ln.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *FakeSMTPServer) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.serve(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (s *FakeSMTPServer) serve(c *textproto.Conn) {
+	_ = c.PrintfLine("220 localhost fake SMTP")
+	var mail FakeMail
+	var username string
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO":
+			_ = c.PrintfLine("250-localhost")
+			_ = c.PrintfLine("250 AUTH PLAIN")
+		case "HELO", "NOOP":
+			_ = c.PrintfLine("250 OK")
+		case "AUTH":
+			// AUTH PLAIN <base64(identity \0 username \0 password)>
+			_, resp, _ := strings.Cut(arg, " ")
+			creds, err := base64.StdEncoding.DecodeString(resp)
+			parts := strings.Split(string(creds), "\x00")
+			if err != nil || len(parts) != 3 {
+				_ = c.PrintfLine("501 malformed AUTH")
+				continue
+			}
+			username = parts[1]
+			_ = c.PrintfLine("235 authenticated")
+		case "MAIL":
+			mail = FakeMail{From: smtpPath(arg), Username: username}
+			_ = c.PrintfLine("250 OK")
+		case "RCPT":
+			mail.To = append(mail.To, smtpPath(arg))
+			_ = c.PrintfLine("250 OK")
+		case "DATA":
+			_ = c.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			data, err := c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mail.Data = string(data)
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			mail = FakeMail{}
+			_ = c.PrintfLine("250 OK")
+		case "RSET":
+			mail = FakeMail{}
+			_ = c.PrintfLine("250 OK")
+		case "QUIT":
+			_ = c.PrintfLine("221 bye")
+			return
+		default:
+			_ = c.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+// smtpPath достаёт адрес из "FROM:<a@b>" или "TO:<a@b>"
+func smtpPath(arg string) string {
+	_, path, _ := strings.Cut(arg, ":")
+	path, _, _ = strings.Cut(strings.TrimSpace(path), " ")
+	return strings.Trim(path, "<>")
+}
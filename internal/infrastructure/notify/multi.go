@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+)
+
+// Multi отправляет уведомление всем notifier по очереди, ошибка одного не мешает остальным
+type Multi []interfaces.Notifier
+
+func (m Multi) Notify(ctx context.Context, n entityNotify.Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
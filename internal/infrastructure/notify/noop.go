@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"sync"
+
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+)
+
+// Noop ничего не отправляет, используется, когда уведомления выключены
+type Noop struct{}
+
+func (Noop) Notify(context.Context, entityNotify.Notification) error {
+	return nil
+}
+
+// Recorder запоминает уведомления вместо отправки, для тестов
+type Recorder struct {
+	mu            sync.Mutex
+	notifications []entityNotify.Notification
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Notify(_ context.Context, n entityNotify.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+func (r *Recorder) Notifications() []entityNotify.Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]entityNotify.Notification(nil), r.notifications...)
+}
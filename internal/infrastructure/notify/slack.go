@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/JanArsMAI/PullRequestService/internal/config"
+	entityNotify "github.com/JanArsMAI/PullRequestService/internal/domain/notification"
+)
+
+// defaultTimeout - сколько ждать ответа webhook чата
+const defaultTimeout = 10 * time.Second
+
+var ErrNoWebhook = errors.New("no chat webhook configured for team")
+
+// defaultTemplates - сообщения по умолчанию, поля шаблона - поля entityNotify.Notification
+var defaultTemplates = map[entityNotify.Kind]string{
+	entityNotify.KindAssigned:          `{{join .Recipients}}: you were assigned to review {{.PrID}} "{{.PrName}}" by {{.AuthorID}}`,
+	entityNotify.KindReassigned:        `{{join .Recipients}}: you now review {{.PrID}} "{{.PrName}}" instead of {{join .Removed}}`,
+	entityNotify.KindMerged:            `{{.PrID}} "{{.PrName}}" was merged{{if .MergedBy}} by {{.MergedBy}}{{end}}`,
+	entityNotify.KindNeedMoreReviewers: `{{.AuthorID}}: {{.PrID}} "{{.PrName}}" needs more reviewers{{if .NeedMoreReason}} ({{.NeedMoreReason}}){{end}}`,
+	entityNotify.KindReviewReminder:    `{{.ReviewerID}}: {{.PrID}} "{{.PrName}}" by {{.AuthorID}} is waiting for your review since {{utc .AssignedAt}}`,
+	entityNotify.KindReviewEscalated:   `{{if .Recipients}}{{join .Recipients}}: {{end}}{{.ReviewerID}} has not reviewed {{.PrID}} "{{.PrName}}" since {{utc .AssignedAt}}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"join": func(ids []string) string { return strings.Join(ids, ", ") },
+	"utc":  func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04") + " UTC" },
+}
+
+// SlackNotifier отправляет уведомления в incoming webhook Slack. Формат {"text", "channel", "username"}
+// понимает и Mattermost. Канал и webhook выбираются по команде PR, для остальных команд - webhook по умолчанию
+type SlackNotifier struct {
+	client     *http.Client
+	webhookURL string
+	username   string
+	teams      map[string]config.SlackChannelConfig
+	templates  map[entityNotify.Kind]*template.Template
+}
+
+func NewSlackNotifier(client *http.Client, cfg config.SlackConfig) (*SlackNotifier, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	templates := make(map[entityNotify.Kind]*template.Template, len(entityNotify.Kinds))
+	for _, kind := range entityNotify.Kinds {
+		text, ok := cfg.Templates[string(kind)]
+		if !ok {
+			text = defaultTemplates[kind]
+		}
+		tmpl, err := template.New(string(kind)).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: template %s: %v", ErrInvalidConfig, kind, err)
+		}
+		templates[kind] = tmpl
+	}
+	for name := range cfg.Templates {
+		if _, ok := templates[entityNotify.Kind(name)]; !ok {
+			return nil, fmt.Errorf("%w: unknown template %s", ErrInvalidConfig, name)
+		}
+	}
+	return &SlackNotifier{
+		client:     client,
+		webhookURL: cfg.WebhookURL,
+		username:   cfg.Username,
+		teams:      cfg.Teams,
+		templates:  templates,
+	}, nil
+}
+
+type slackMessage struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+func (s *SlackNotifier) Notify(ctx context.Context, n entityNotify.Notification) error {
+	tmpl, ok := s.templates[n.Kind]
+	if !ok {
+		return nil
+	}
+	team := s.teams[n.TeamName]
+	url := team.WebhookURL
+	if url == "" {
+		url = s.webhookURL
+	}
+	if url == "" {
+		return fmt.Errorf("%w %s", ErrNoWebhook, n.TeamName)
+	}
+	var text strings.Builder
+	if err := tmpl.Execute(&text, n); err != nil {
+		return fmt.Errorf("failed to render %s message: %w", n.Kind, err)
+	}
+	body, err := json.Marshal(slackMessage{Text: text.String(), Channel: team.Channel, Username: s.username})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("chat webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type APIKeyDto struct {
+	Id         int            `db:"id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"key_prefix"`
+	Hash       string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	CreatedBy  string         `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type CodeHostUserDto struct {
+	Provider string `db:"provider"`
+	Login    string `db:"login"`
+	UserID   string `db:"user_id"`
+}
+
+type CodeHostLinkDto struct {
+	PrID            string         `db:"pull_request_id"`
+	Provider        string         `db:"provider"`
+	RepoID          int64          `db:"repo_id"`
+	Repo            string         `db:"repo"`
+	Number          int            `db:"number"`
+	Status          string         `db:"sync_status"`
+	Version         int            `db:"version"`
+	Attempts        int            `db:"attempts"`
+	NextAttemptAt   time.Time      `db:"next_attempt_at"`
+	LastError       string         `db:"last_error"`
+	SyncedReviewers pq.StringArray `db:"synced_reviewers"`
+	SyncedAt        *time.Time     `db:"synced_at"`
+}
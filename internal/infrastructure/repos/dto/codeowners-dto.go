@@ -0,0 +1,8 @@
+package dto
+
+type CodeOwnerRuleDto struct {
+	RuleID    int    `db:"id"`
+	Pattern   string `db:"pattern"`
+	OwnerKind string `db:"owner_kind"`
+	OwnerName string `db:"owner_name"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type OutboxDto struct {
+	Id            int64     `db:"id"`
+	EventID       string    `db:"event_id"`
+	EventType     string    `db:"event_type"`
+	AggregateType string    `db:"aggregate_type"`
+	AggregateID   string    `db:"aggregate_id"`
+	TeamID        int       `db:"team_id"`
+	Payload       []byte    `db:"payload"`
+	OccurredAt    time.Time `db:"occurred_at"`
+}
@@ -6,14 +6,61 @@ type PullRequestDto struct {
 	ID                string     `db:"pull_request_id"`
 	Name              string     `db:"pull_request_name"`
 	AuthorID          string     `db:"author_id"`
+	AuthorTeamID      int        `db:"author_team_id"`
 	Status            string     `db:"status"`
 	NeedMoreReviewers bool       `db:"need_more_reviewers"`
+	NeedMoreReason    string     `db:"need_more_reason"`
 	CreatedAt         time.Time  `db:"created_at"`
 	MergedAt          *time.Time `db:"merged_at"`
+	MergedBy          string     `db:"merged_by"`
+	ReviewBypassed    bool       `db:"review_bypassed"`
+}
+
+type ReviewDto struct {
+	Id            int       `db:"id"`
+	PullRequestID string    `db:"pull_request_id"`
+	ReviewerID    string    `db:"reviewer_id"`
+	Verdict       string    `db:"verdict"`
+	Body          string    `db:"body"`
+	CreatedAt     time.Time `db:"created_at"`
 }
 
 type PullRequestReviewerDto struct {
 	PullRequestID string    `db:"pull_request_id"`
 	ReviewerID    string    `db:"reviewer_id"`
 	AssignedAt    time.Time `db:"assigned_at"`
+	PoolTeamID    *int      `db:"pool_team_id"`
+}
+
+type SLABreachDto struct {
+	PrID             string     `db:"pull_request_id"`
+	PrName           string     `db:"pull_request_name"`
+	AuthorID         string     `db:"author_id"`
+	TeamID           int        `db:"team_id"`
+	TeamName         string     `db:"team_name"`
+	ReviewerID       string     `db:"reviewer_id"`
+	AssignedAt       time.Time  `db:"assigned_at"`
+	SLAReviewHours   int        `db:"sla_review_hours"`
+	SLAEscalateHours int        `db:"sla_escalate_hours"`
+	SLAAction        string     `db:"sla_action"`
+	RemindedAt       *time.Time `db:"reminded_at"`
+	EscalatedAt      *time.Time `db:"escalated_at"`
+}
+
+type StaleReviewDto struct {
+	PrID       string    `db:"pull_request_id"`
+	TeamID     int       `db:"team_id"`
+	ReviewerID string    `db:"reviewer_id"`
+	AssignedAt time.Time `db:"assigned_at"`
+}
+
+type AssignmentDto struct {
+	Id         int64     `db:"id"`
+	PrID       string    `db:"pull_request_id"`
+	ReviewerID string    `db:"reviewer_id"`
+	Action     string    `db:"action"`
+	ReplacedBy string    `db:"replaced_by"`
+	Reason     string    `db:"reason"`
+	Actor      string    `db:"actor"`
+	CreatedAt  time.Time `db:"created_at"`
 }
@@ -1,6 +1,13 @@
 package dto
 
 type TeamDto struct {
-	Id   int    `db:"id"`
-	Name string `db:"team_name"`
+	Id                int    `db:"id"`
+	Name              string `db:"team_name"`
+	ReviewerStrategy  string `db:"reviewer_strategy"`
+	RequiredReviewers int    `db:"required_reviewers"`
+	RequiredApprovals int    `db:"required_approvals"`
+	SLAReviewHours    int    `db:"sla_review_hours"`
+	SLAEscalateHours  int    `db:"sla_escalate_hours"`
+	SLAAction         string `db:"sla_action"`
+	StaleReviewHours  int    `db:"stale_review_hours"`
 }
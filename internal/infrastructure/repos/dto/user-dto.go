@@ -1,8 +1,37 @@
 package dto
 
+import "time"
+
 type UserDto struct {
-	Id       string `db:"user_id"`
-	Name     string `db:"username"`
-	IsActive bool   `db:"is_active"`
-	TeamID   int    `db:"team_id"`
+	Id             string `db:"user_id"`
+	Name           string `db:"username"`
+	IsActive       bool   `db:"is_active"`
+	TeamID         int    `db:"team_id"`
+	MaxOpenReviews int    `db:"max_open_reviews"`
+	Email          string `db:"email"`
+}
+
+type ReviewLoadDto struct {
+	ReviewerID     string     `db:"reviewer_id"`
+	OpenReviews    int        `db:"open_reviews"`
+	LastAssignedAt *time.Time `db:"last_assigned_at"`
+}
+
+type UserTagDto struct {
+	UserID string `db:"user_id"`
+	Tag    string `db:"tag"`
+}
+
+type AvailabilityDto struct {
+	Id       int       `db:"id"`
+	UserID   string    `db:"user_id"`
+	StartsAt time.Time `db:"starts_at"`
+	EndsAt   time.Time `db:"ends_at"`
+	Reason   string    `db:"reason"`
+}
+
+type GrantDto struct {
+	Role     string `db:"role"`
+	TeamID   *int   `db:"team_id"`
+	TeamName string `db:"team_name"`
 }
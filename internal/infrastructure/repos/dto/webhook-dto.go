@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type WebhookDto struct {
+	Id        int            `db:"id"`
+	TeamID    *int           `db:"team_id"`
+	TeamName  string         `db:"team_name"`
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret"`
+	Events    pq.StringArray `db:"events"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+type DeliveryDto struct {
+	Id            int        `db:"id"`
+	WebhookID     int        `db:"webhook_id"`
+	URL           string     `db:"url"`
+	Secret        string     `db:"secret"`
+	EventID       string     `db:"event_id"`
+	EventType     string     `db:"event_type"`
+	Payload       []byte     `db:"payload"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	LastError     string     `db:"last_error"`
+	ResponseCode  int        `db:"response_code"`
+	CreatedAt     time.Time  `db:"created_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+}
@@ -3,22 +3,38 @@ package repos
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityOwners "github.com/JanArsMAI/PullRequestService/internal/domain/codeowners"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
 	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
 	entityPr "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
 	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
 	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
 	"github.com/JanArsMAI/PullRequestService/internal/infrastructure/repos/dto"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 var (
 	ErrNoUserWithId = errors.New("No user with this ID")
 	ErrTeamNotFound = errors.New("team with this Name not found")
 	ErrPrNotFound   = errors.New("Pull request with this id is not found")
+
+	ErrAvailabilityNotFound = errors.New("availability window not found")
+	ErrGrantNotFound        = errors.New("role is not granted to user")
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrCodeHostUserNotFound = errors.New("code host user is not mapped")
 )
 
 type PostgresRepo struct {
@@ -31,34 +47,40 @@ func NewPostgresRepo(db *sqlx.DB) interfaces.PullRequestRepo {
 	}
 }
 
-func (p *PostgresRepo) AddTeam(ctx context.Context, name string, users []entityUser.User) error {
+func (p *PostgresRepo) AddTeam(ctx context.Context, team entityTeam.Team) error {
 	tx, err := p.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error beginning transaction: %w", err)
 	}
 	var teamID int
-	queryTeam := `INSERT INTO teams (team_name)
-		VALUES ($1)
+	eventType := entityEvent.TeamCreated
+	queryTeam := `INSERT INTO teams (team_name, reviewer_strategy, required_reviewers)
+		VALUES ($1, $2, $3)
 		ON CONFLICT (team_name) DO NOTHING
 		RETURNING id;`
-	if err := tx.QueryRowContext(ctx, queryTeam, name).Scan(&teamID); err != nil {
+	if err := tx.QueryRowContext(ctx, queryTeam, team.Name, team.ReviewerStrategy, team.ReviewersNeeded()).Scan(&teamID); err != nil {
 		if err == sql.ErrNoRows {
-			if err := tx.GetContext(ctx, &teamID, `SELECT id FROM teams WHERE team_name=$1`, name); err != nil {
+			if err := tx.GetContext(ctx, &teamID, `SELECT id FROM teams WHERE team_name=$1`, team.Name); err != nil {
 				_ = tx.Rollback()
 				return fmt.Errorf("error getting existing team id: %w", err)
 			}
+			eventType = entityEvent.TeamUpdated
 		} else {
 			_ = tx.Rollback()
 			return fmt.Errorf("error inserting team: %w", err)
 		}
 	}
-	for _, user := range users {
+	for _, user := range team.Users {
 
 		if err := addUserTx(ctx, tx, user, teamID); err != nil {
 			_ = tx.Rollback()
 			return fmt.Errorf("error inserting or updating user %s: %w", user.Id, err)
 		}
 	}
+	if err := teamEventTx(ctx, tx, teamID, eventType); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
@@ -67,41 +89,101 @@ func (p *PostgresRepo) AddTeam(ctx context.Context, name string, users []entityU
 	return nil
 }
 
+func (p *PostgresRepo) UpdateTeamSettings(ctx context.Context, team entityTeam.Team) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE teams
+		SET reviewer_strategy = $1,
+		    required_reviewers = $2,
+		    required_approvals = $3,
+		    sla_review_hours = $4,
+		    sla_escalate_hours = $5,
+		    sla_action = COALESCE(NULLIF($6, ''), 'escalate'),
+		    stale_review_hours = $7
+		WHERE id = $8`, team.ReviewerStrategy, team.ReviewersNeeded(), team.ApprovalsNeeded(),
+		team.SLA.ReviewHours, team.SLA.EscalateHours, team.SLA.Action, team.StaleReviewHours, team.Id)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error updating team settings: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		_ = tx.Rollback()
+		return ErrTeamNotFound
+	}
+	if err := teamEventTx(ctx, tx, team.Id, entityEvent.TeamUpdated); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// addUserTx создаёт пользователя или переводит существующего в команду teamID.
+// email задаётся только новому пользователю, у существующего он меняется через SetUserEmail
 func addUserTx(ctx context.Context, tx *sqlx.Tx, user entityUser.User, teamID int) error {
-	query := `INSERT INTO users (user_id, username, team_id, is_active)
-		VALUES ($1, $2, $3, $4)
+	query := `INSERT INTO users (user_id, username, team_id, is_active, email)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
 		ON CONFLICT (user_id) DO UPDATE
 		SET username = EXCLUDED.username,
 		    is_active = EXCLUDED.is_active,
 		    team_id = EXCLUDED.team_id;`
-	_, err := tx.ExecContext(ctx, query, user.Id, user.Name, teamID, user.IsActive)
+	_, err := tx.ExecContext(ctx, query, user.Id, user.Name, teamID, user.IsActive, user.Email)
 	if err != nil {
 		return fmt.Errorf("error adding/updating user: %w", err)
 	}
 	return nil
 }
 
+const teamColumns = `id, team_name, reviewer_strategy, required_reviewers, required_approvals,
+	sla_review_hours, sla_escalate_hours, sla_action, stale_review_hours`
+
+// teamUsersQuery - участники команды, внутри окна недоступности пользователь считается неактивным
+const teamUsersQuery = `
+	SELECT u.user_id, u.username, COALESCE(u.max_open_reviews, 0) AS max_open_reviews, COALESCE(u.email, '') AS email,
+		u.is_active AND NOT EXISTS (
+			SELECT 1 FROM user_availability w
+			WHERE w.user_id = u.user_id AND w.starts_at <= NOW() AND w.ends_at > NOW()
+		) AS is_active
+	FROM users u
+	WHERE u.team_id = $1`
+
 func (p *PostgresRepo) GetTeam(ctx context.Context, id int) (*entityTeam.Team, error) {
 	var team dto.TeamDto
-	if err := p.db.GetContext(ctx, &team, `SELECT id, team_name FROM teams WHERE id = $1`, id); err != nil {
+	if err := p.db.GetContext(ctx, &team, `SELECT `+teamColumns+` FROM teams WHERE id = $1`, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrTeamNotFound
 		}
 		return nil, err
 	}
 	var users []dto.UserDto
-	if err := p.db.SelectContext(ctx, &users, `SELECT user_id, username, is_active FROM users WHERE team_id = $1`, id); err != nil {
+	if err := p.db.SelectContext(ctx, &users, teamUsersQuery, id); err != nil {
 		return nil, err
 	}
 	entityTeam := &entityTeam.Team{
-		Id:   team.Id,
-		Name: team.Name,
+		Id:                team.Id,
+		Name:              team.Name,
+		ReviewerStrategy:  team.ReviewerStrategy,
+		RequiredReviewers: team.RequiredReviewers,
+		RequiredApprovals: team.RequiredApprovals,
+		SLA: entityTeam.SLA{
+			ReviewHours:   team.SLAReviewHours,
+			EscalateHours: team.SLAEscalateHours,
+			Action:        team.SLAAction,
+		},
+		StaleReviewHours: team.StaleReviewHours,
 	}
 	for _, u := range users {
 		entityTeam.Users = append(entityTeam.Users, entityUser.User{
-			Id:       u.Id,
-			Name:     u.Name,
-			IsActive: u.IsActive,
+			Id:             u.Id,
+			Name:           u.Name,
+			IsActive:       u.IsActive,
+			MaxOpenReviews: u.MaxOpenReviews,
+			Email:          u.Email,
 		})
 	}
 
@@ -110,7 +192,7 @@ func (p *PostgresRepo) GetTeam(ctx context.Context, id int) (*entityTeam.Team, e
 
 func (p *PostgresRepo) GetTeamByName(ctx context.Context, name string) (*entityTeam.Team, error) {
 	var team dto.TeamDto
-	if err := p.db.GetContext(ctx, &team, `SELECT id, team_name FROM teams WHERE team_name = $1`, name); err != nil {
+	if err := p.db.GetContext(ctx, &team, `SELECT `+teamColumns+` FROM teams WHERE team_name = $1`, name); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrTeamNotFound
 		}
@@ -118,20 +200,31 @@ func (p *PostgresRepo) GetTeamByName(ctx context.Context, name string) (*entityT
 	}
 
 	var users []dto.UserDto
-	if err := p.db.SelectContext(ctx, &users, `SELECT user_id, username, is_active FROM users WHERE team_id = $1`, team.Id); err != nil {
+	if err := p.db.SelectContext(ctx, &users, teamUsersQuery, team.Id); err != nil {
 		return nil, fmt.Errorf("failed to get users for team %s: %w", name, err)
 	}
 
 	entityTeam := &entityTeam.Team{
-		Id:   team.Id,
-		Name: team.Name,
+		Id:                team.Id,
+		Name:              team.Name,
+		ReviewerStrategy:  team.ReviewerStrategy,
+		RequiredReviewers: team.RequiredReviewers,
+		RequiredApprovals: team.RequiredApprovals,
+		SLA: entityTeam.SLA{
+			ReviewHours:   team.SLAReviewHours,
+			EscalateHours: team.SLAEscalateHours,
+			Action:        team.SLAAction,
+		},
+		StaleReviewHours: team.StaleReviewHours,
 	}
 
 	for _, u := range users {
 		entityTeam.Users = append(entityTeam.Users, entityUser.User{
-			Id:       u.Id,
-			Name:     u.Name,
-			IsActive: u.IsActive,
+			Id:             u.Id,
+			Name:           u.Name,
+			IsActive:       u.IsActive,
+			MaxOpenReviews: u.MaxOpenReviews,
+			Email:          u.Email,
 		})
 	}
 
@@ -151,14 +244,15 @@ func (p *PostgresRepo) AddPR(ctx context.Context, pr entityPr.PullRequest) error
 	}()
 	queryPR := `
 		INSERT INTO pull_requests (
-			pull_request_id, pull_request_name, author_id, status, need_more_reviewers, created_at, merged_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+			pull_request_id, pull_request_name, author_id, status, need_more_reviewers, need_more_reason, created_at, merged_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)`
 	_, err = tx.ExecContext(ctx, queryPR,
 		pr.Id,
 		pr.Name,
 		pr.Author.Id,
 		pr.Status,
 		pr.NeedMoreReviewers,
+		pr.NeedMoreReason,
 		pr.CreatedAt,
 		pr.MergedAt,
 	)
@@ -167,15 +261,35 @@ func (p *PostgresRepo) AddPR(ctx context.Context, pr entityPr.PullRequest) error
 		return fmt.Errorf("error inserting pull request: %w", err)
 	}
 	if len(pr.Reviewers) > 0 {
-		queryReviewer := `INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id)
-			VALUES ($1, $2)`
+		queryReviewer := `INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, pool_team_id)
+			VALUES ($1, $2, $3)`
+		added := make([]string, 0, len(pr.Reviewers))
 		for _, reviewer := range pr.Reviewers {
-			if _, err := tx.ExecContext(ctx, queryReviewer, pr.Id, reviewer.Id); err != nil {
+			if _, err := tx.ExecContext(ctx, queryReviewer, pr.Id, reviewer.Id, poolTeamID(pr, reviewer.Id)); err != nil {
 				_ = tx.Rollback()
 				return fmt.Errorf("error inserting reviewer %s: %w", reviewer.Id, err)
 			}
+			added = append(added, reviewer.Id)
+		}
+		reason := entityEvent.ReasonFrom(ctx)
+		if reason == "" {
+			reason = entityEvent.ReasonCreate
+		}
+		if err := addAssignmentsTx(ctx, tx, entityPr.AssignmentChanges(pr.Id, added, nil, reason, actorFrom(ctx))); err != nil {
+			_ = tx.Rollback()
+			return err
 		}
 	}
+	for _, tag := range pr.RequiredTags {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO pull_request_tags (pull_request_id, tag) VALUES ($1, $2)`, pr.Id, tag); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error inserting required tag %s: %w", tag, err)
+		}
+	}
+	if err := prEventTx(ctx, tx, pr.Id, entityEvent.PrCreated); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
@@ -184,38 +298,71 @@ func (p *PostgresRepo) AddPR(ctx context.Context, pr entityPr.PullRequest) error
 	return nil
 }
 
+// poolTeamID - значение для pull_request_reviewers.pool_team_id, NULL если пул неизвестен
+func poolTeamID(pr entityPr.PullRequest, reviewerID string) *int {
+	teamID, ok := pr.PoolOf(reviewerID)
+	if !ok {
+		return nil
+	}
+	return &teamID
+}
+
 func (p *PostgresRepo) GetPr(ctx context.Context, prID string) (*entityPr.PullRequest, error) {
+	return getPr(ctx, p.db, prID)
+}
+
+// getPr читает PR через q, внутри транзакции видит её незакоммиченные изменения
+func getPr(ctx context.Context, q sqlx.QueryerContext, prID string) (*entityPr.PullRequest, error) {
 	var prDto dto.PullRequestDto
-	queryPR := `SELECT pull_request_id, pull_request_name, author_id, status, need_more_reviewers, created_at, merged_at
-        FROM pull_requests
-        WHERE pull_request_id = $1`
-	if err := p.db.GetContext(ctx, &prDto, queryPR, prID); err != nil {
+	queryPR := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, u.team_id AS author_team_id,
+            pr.status, pr.need_more_reviewers, COALESCE(pr.need_more_reason, '') AS need_more_reason,
+            pr.created_at, pr.merged_at, COALESCE(pr.merged_by, '') AS merged_by, pr.review_bypassed
+        FROM pull_requests pr
+        JOIN users u ON u.user_id = pr.author_id
+        WHERE pr.pull_request_id = $1`
+	if err := sqlx.GetContext(ctx, q, &prDto, queryPR, prID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrPrNotFound
 		}
 		return nil, fmt.Errorf("error getting pull request: %w", err)
 	}
-	var reviewerIDs []string
+	var reviewers []dto.PullRequestReviewerDto
 	queryReviewers := `
-        SELECT reviewer_id
+        SELECT pull_request_id, reviewer_id, assigned_at, pool_team_id
         FROM pull_request_reviewers
         WHERE pull_request_id = $1
     `
-	if err := p.db.SelectContext(ctx, &reviewerIDs, queryReviewers, prID); err != nil {
+	if err := sqlx.SelectContext(ctx, q, &reviewers, queryReviewers, prID); err != nil {
 		return nil, fmt.Errorf("get reviewers: %w", err)
 	}
+	var tags []string
+	if err := sqlx.SelectContext(ctx, q, &tags, `SELECT tag FROM pull_request_tags WHERE pull_request_id = $1 ORDER BY tag`, prID); err != nil {
+		return nil, fmt.Errorf("get required tags: %w", err)
+	}
 	pr := &entityPr.PullRequest{
 		Id:                prDto.ID,
 		Name:              prDto.Name,
-		Author:            entityUser.User{Id: prDto.AuthorID},
-		Status:            prDto.Status,
+		Author:            entityUser.User{Id: prDto.AuthorID, TeamID: prDto.AuthorTeamID},
+		Status:            entityPr.Status(prDto.Status),
 		NeedMoreReviewers: prDto.NeedMoreReviewers,
+		NeedMoreReason:    prDto.NeedMoreReason,
 		CreatedAt:         prDto.CreatedAt,
 		MergedAt:          prDto.MergedAt,
+		MergedBy:          prDto.MergedBy,
+		ReviewBypassed:    prDto.ReviewBypassed,
+		RequiredTags:      tags,
+	}
+	for _, r := range reviewers {
+		pr.Reviewers = append(pr.Reviewers, entityUser.User{Id: r.ReviewerID})
+		if r.PoolTeamID != nil {
+			pr.SetPool(r.ReviewerID, *r.PoolTeamID)
+		}
 	}
-	for _, rid := range reviewerIDs {
-		pr.Reviewers = append(pr.Reviewers, entityUser.User{Id: rid})
+	link, err := getCodeHostLink(ctx, q, prID)
+	if err != nil {
+		return nil, err
 	}
+	pr.CodeHost = link
 	return pr, nil
 }
 
@@ -224,28 +371,69 @@ func (p *PostgresRepo) UpdatePr(ctx context.Context, prId string, newPr entityPr
 	if err != nil {
 		return fmt.Errorf("error beginning transaction: %w", err)
 	}
+	before, err := lockPrTx(ctx, tx, prId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 	queryUpdate := `UPDATE pull_requests
         SET status = $1,
             need_more_reviewers = $2,
-            merged_at = $3
-        WHERE pull_request_id = $4`
-	_, err = tx.ExecContext(ctx, queryUpdate, newPr.Status, newPr.NeedMoreReviewers, newPr.MergedAt, prId)
+            need_more_reason = NULLIF($3, ''),
+            merged_at = $4,
+            merged_by = NULLIF($5, ''),
+            review_bypassed = $6
+        WHERE pull_request_id = $7`
+	_, err = tx.ExecContext(ctx, queryUpdate, newPr.Status, newPr.NeedMoreReviewers, newPr.NeedMoreReason, newPr.MergedAt,
+		newPr.MergedBy, newPr.ReviewBypassed, prId)
 	if err != nil {
 		_ = tx.Rollback()
 		return fmt.Errorf("error updating pull_request: %w", err)
 	}
-	_, err = tx.ExecContext(ctx, `DELETE FROM pull_request_reviewers WHERE pull_request_id = $1`, prId)
-	if err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("delete old reviewers: %w", err)
+	// ревьюеры не перезаписываются целиком, чтобы у оставшихся сохранился assigned_at
+	added, removed := entityPr.ReviewerDiff(*before, newPr)
+	if len(removed) > 0 {
+		_, err = tx.ExecContext(ctx, `DELETE FROM pull_request_reviewers WHERE pull_request_id = $1 AND reviewer_id = ANY($2)`,
+			prId, pq.Array(removed))
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("delete old reviewers: %w", err)
+		}
+	}
+	isAdded := make(map[string]struct{}, len(added))
+	for _, id := range added {
+		isAdded[id] = struct{}{}
+		_, err := tx.ExecContext(ctx, `INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, pool_team_id) VALUES ($1, $2, $3)`, prId, id, poolTeamID(newPr, id))
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error inserting new reviewer %s: %w", id, err)
+		}
 	}
 	for _, r := range newPr.Reviewers {
-		_, err := tx.ExecContext(ctx, `INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`, prId, r.Id)
+		if _, ok := isAdded[r.Id]; ok {
+			continue
+		}
+		// пул оставшегося ревьюера меняется, только если он известен newPr
+		_, err := tx.ExecContext(ctx, `UPDATE pull_request_reviewers SET pool_team_id = COALESCE($3, pool_team_id)
+			WHERE pull_request_id = $1 AND reviewer_id = $2`, prId, r.Id, poolTeamID(newPr, r.Id))
 		if err != nil {
 			_ = tx.Rollback()
-			return fmt.Errorf("error inserting new reviewer %s: %w", r.Id, err)
+			return fmt.Errorf("error updating reviewer %s: %w", r.Id, err)
 		}
 	}
+	if err := addAssignmentsTx(ctx, tx, entityPr.AssignmentChanges(prId, added, removed, entityEvent.ReasonFrom(ctx), actorFrom(ctx))); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	after, err := getPr(ctx, tx, prId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := addOutboxTx(ctx, tx, entityEvent.NewPrChange(*before, *after, entityEvent.ReasonFrom(ctx))); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
@@ -261,12 +449,18 @@ func (p *PostgresRepo) GetUsersPr(ctx context.Context, userId string, onlyActive
 			pr.pull_request_id,
 			pr.pull_request_name,
 			pr.author_id,
+			a.team_id,
 			pr.status,
 			pr.need_more_reviewers,
 			pr.created_at,
 			pr.merged_at,
-			r.reviewer_id
+			r.reviewer_id,
+			r.pool_team_id,
+			ARRAY(SELECT t.tag FROM pull_request_tags t WHERE t.pull_request_id = pr.pull_request_id ORDER BY t.tag) AS required_tags,
+			COALESCE(pr.need_more_reason, '') AS need_more_reason
 		FROM pull_requests pr
+		JOIN users a
+			ON a.user_id = pr.author_id
 		LEFT JOIN pull_request_reviewers r 
 			ON pr.pull_request_id = r.pull_request_id
 		WHERE pr.pull_request_id IN (
@@ -292,10 +486,13 @@ func (p *PostgresRepo) GetUsersPr(ctx context.Context, userId string, onlyActive
 
 	for rows.Next() {
 		var prID, prName, authorID, status, reviewerID sql.NullString
+		var authorTeamID, poolTeamID sql.NullInt64
 		var needMoreReviewers sql.NullBool
 		var createdAt, mergedAt sql.NullTime
+		var requiredTags pq.StringArray
+		var needMoreReason string
 
-		if err := rows.Scan(&prID, &prName, &authorID, &status, &needMoreReviewers, &createdAt, &mergedAt, &reviewerID); err != nil {
+		if err := rows.Scan(&prID, &prName, &authorID, &authorTeamID, &status, &needMoreReviewers, &createdAt, &mergedAt, &reviewerID, &poolTeamID, &requiredTags, &needMoreReason); err != nil {
 			return nil, fmt.Errorf("error scanning PR row: %w", err)
 		}
 
@@ -303,10 +500,12 @@ func (p *PostgresRepo) GetUsersPr(ctx context.Context, userId string, onlyActive
 			prMap[prID.String] = &entityPr.PullRequest{
 				Id:                prID.String,
 				Name:              prName.String,
-				Author:            entityUser.User{Id: authorID.String},
-				Status:            status.String,
+				Author:            entityUser.User{Id: authorID.String, TeamID: int(authorTeamID.Int64)},
+				Status:            entityPr.Status(status.String),
 				NeedMoreReviewers: needMoreReviewers.Bool,
 				CreatedAt:         createdAt.Time,
+				RequiredTags:      requiredTags,
+				NeedMoreReason:    needMoreReason,
 			}
 			if mergedAt.Valid {
 				prMap[prID.String].MergedAt = &mergedAt.Time
@@ -317,6 +516,9 @@ func (p *PostgresRepo) GetUsersPr(ctx context.Context, userId string, onlyActive
 				prMap[prID.String].Reviewers,
 				entityUser.User{Id: reviewerID.String},
 			)
+			if poolTeamID.Valid {
+				prMap[prID.String].SetPool(reviewerID.String, int(poolTeamID.Int64))
+			}
 		}
 	}
 
@@ -331,7 +533,8 @@ func (p *PostgresRepo) GetUsersPr(ctx context.Context, userId string, onlyActive
 func (p *PostgresRepo) GetUserByID(ctx context.Context, userID string) (*entityUser.User, error) {
 	var u dto.UserDto
 	err := p.db.GetContext(ctx, &u, `
-		SELECT user_id, username, team_id, is_active
+		SELECT user_id, username, team_id, is_active, COALESCE(max_open_reviews, 0) AS max_open_reviews,
+			COALESCE(email, '') AS email
 		FROM users 
 		WHERE user_id = $1
 	`, userID)
@@ -342,49 +545,89 @@ func (p *PostgresRepo) GetUserByID(ctx context.Context, userID string) (*entityU
 		return nil, fmt.Errorf("error getting user: %w", err)
 	}
 	return &entityUser.User{
-		Id:       u.Id,
-		Name:     u.Name,
-		IsActive: u.IsActive,
-		TeamID:   u.TeamID,
+		Id:             u.Id,
+		Name:           u.Name,
+		IsActive:       u.IsActive,
+		TeamID:         u.TeamID,
+		MaxOpenReviews: u.MaxOpenReviews,
+		Email:          u.Email,
 	}, nil
 }
 
 func (p *PostgresRepo) RemoveReviewerFromAllPR(ctx context.Context, reviewerID string) error {
-	_, err := p.db.ExecContext(ctx, `
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	var prIDs []string
+	err = tx.SelectContext(ctx, &prIDs, `
         DELETE FROM pull_request_reviewers
         WHERE reviewer_id = $1
         AND pull_request_id IN (
             SELECT pull_request_id
             FROM pull_requests
-            WHERE status != 'MERGED'
+            WHERE status NOT IN ('MERGED', 'CLOSED')
         )
+        RETURNING pull_request_id
     `, reviewerID)
 	if err != nil {
+		_ = tx.Rollback()
 		return fmt.Errorf("failed to remove reviewer %s from pull_request_reviewers: %w", reviewerID, err)
 	}
+	for _, prID := range prIDs {
+		if err := reviewersEventTx(ctx, tx, prID, nil, []string{reviewerID}); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
 	return nil
 }
 
 func (p *PostgresRepo) UpdateUser(ctx context.Context, u entityUser.User) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	var wasActive bool
+	if err := tx.GetContext(ctx, &wasActive, `SELECT is_active FROM users WHERE user_id = $1 FOR UPDATE`, u.Id); err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoUserWithId
+		}
+		return fmt.Errorf("error locking user: %w", err)
+	}
 	query := `UPDATE users
         SET 
             username = $1,
             team_id = $2,
             is_active = $3
         WHERE user_id = $4`
-	res, err := p.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		u.Name,
 		u.TeamID,
 		u.IsActive,
 		u.Id,
 	)
 	if err != nil {
+		_ = tx.Rollback()
 		return fmt.Errorf("error updating user: %w", err)
 	}
-
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return ErrNoUserWithId
+	eventType := entityEvent.UserUpdated
+	switch {
+	case u.IsActive && !wasActive:
+		eventType = entityEvent.UserActivated
+	case !u.IsActive && wasActive:
+		eventType = entityEvent.UserDeactivated
+	}
+	if err := userEventTx(ctx, tx, u.Id, eventType); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
 	}
 
 	return nil
@@ -428,12 +671,30 @@ func (p *PostgresRepo) GetUserWithTeam(ctx context.Context, userID string) (*ent
 }
 
 func (p *PostgresRepo) AddReviewerToPR(ctx context.Context, prId string, reviewerID string) error {
-	_, err := p.db.ExecContext(ctx, `
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `
         INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id)
         VALUES ($1, $2)
         ON CONFLICT DO NOTHING
     `, prId, reviewerID)
-	return err
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	// ревьюер уже был назначен - состояние PR не изменилось, событие не нужно
+	if rows, _ := res.RowsAffected(); rows > 0 {
+		if err := reviewersEventTx(ctx, tx, prId, []string{reviewerID}, nil); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
 }
 
 func (p *PostgresRepo) GetTeamPr(ctx context.Context, teamID int) ([]entityPr.PullRequest, error) {
@@ -442,12 +703,18 @@ func (p *PostgresRepo) GetTeamPr(ctx context.Context, teamID int) ([]entityPr.Pu
 			pr.pull_request_id,
 			pr.pull_request_name,
 			pr.author_id,
+			a.team_id,
 			pr.status,
 			pr.need_more_reviewers,
 			pr.created_at,
 			pr.merged_at,
-			r.reviewer_id
+			r.reviewer_id,
+			r.pool_team_id,
+			ARRAY(SELECT t.tag FROM pull_request_tags t WHERE t.pull_request_id = pr.pull_request_id ORDER BY t.tag) AS required_tags,
+			COALESCE(pr.need_more_reason, '') AS need_more_reason
 		FROM pull_requests pr
+		JOIN users a
+			ON a.user_id = pr.author_id
 		LEFT JOIN pull_request_reviewers r
 			ON pr.pull_request_id = r.pull_request_id
 		WHERE 
@@ -475,14 +742,17 @@ func (p *PostgresRepo) GetTeamPr(ctx context.Context, teamID int) ([]entityPr.Pu
 		var (
 			prID, prName, authorID, status sql.NullString
 			reviewerID                     sql.NullString
+			authorTeamID, poolTeamID       sql.NullInt64
 			needMore                       sql.NullBool
 			createdAt, mergedAt            sql.NullTime
+			requiredTags                   pq.StringArray
+			needMoreReason                 string
 		)
 
 		if err := rows.Scan(
-			&prID, &prName, &authorID, &status,
+			&prID, &prName, &authorID, &authorTeamID, &status,
 			&needMore, &createdAt, &mergedAt,
-			&reviewerID,
+			&reviewerID, &poolTeamID, &requiredTags, &needMoreReason,
 		); err != nil {
 			return nil, fmt.Errorf("scan error: %w", err)
 		}
@@ -493,10 +763,12 @@ func (p *PostgresRepo) GetTeamPr(ctx context.Context, teamID int) ([]entityPr.Pu
 			pr = &entityPr.PullRequest{
 				Id:                prID.String,
 				Name:              prName.String,
-				Author:            entityUser.User{Id: authorID.String},
-				Status:            status.String,
+				Author:            entityUser.User{Id: authorID.String, TeamID: int(authorTeamID.Int64)},
+				Status:            entityPr.Status(status.String),
 				NeedMoreReviewers: needMore.Bool,
 				CreatedAt:         createdAt.Time,
+				RequiredTags:      requiredTags,
+				NeedMoreReason:    needMoreReason,
 			}
 			if mergedAt.Valid {
 				pr.MergedAt = &mergedAt.Time
@@ -508,6 +780,9 @@ func (p *PostgresRepo) GetTeamPr(ctx context.Context, teamID int) ([]entityPr.Pu
 			pr.Reviewers = append(pr.Reviewers,
 				entityUser.User{Id: reviewerID.String},
 			)
+			if poolTeamID.Valid {
+				pr.SetPool(reviewerID.String, int(poolTeamID.Int64))
+			}
 		}
 	}
 
@@ -525,12 +800,18 @@ func (p *PostgresRepo) GetAllPRs(ctx context.Context) ([]entityPr.PullRequest, e
 			pr.pull_request_id,
 			pr.pull_request_name,
 			pr.author_id,
+			a.team_id,
 			pr.status,
 			pr.need_more_reviewers,
 			pr.created_at,
 			pr.merged_at,
-			r.reviewer_id
+			r.reviewer_id,
+			r.pool_team_id,
+			ARRAY(SELECT t.tag FROM pull_request_tags t WHERE t.pull_request_id = pr.pull_request_id ORDER BY t.tag) AS required_tags,
+			COALESCE(pr.need_more_reason, '') AS need_more_reason
 		FROM pull_requests pr
+		JOIN users a
+			ON a.user_id = pr.author_id
 		LEFT JOIN pull_request_reviewers r
 			ON pr.pull_request_id = r.pull_request_id
 		ORDER BY pr.created_at DESC;
@@ -550,14 +831,17 @@ func (p *PostgresRepo) GetAllPRs(ctx context.Context) ([]entityPr.PullRequest, e
 		var (
 			prID, prName, authorID, status sql.NullString
 			reviewerID                     sql.NullString
+			authorTeamID, poolTeamID       sql.NullInt64
 			needMore                       sql.NullBool
 			createdAt, mergedAt            sql.NullTime
+			requiredTags                   pq.StringArray
+			needMoreReason                 string
 		)
 
 		if err := rows.Scan(
-			&prID, &prName, &authorID, &status,
+			&prID, &prName, &authorID, &authorTeamID, &status,
 			&needMore, &createdAt, &mergedAt,
-			&reviewerID,
+			&reviewerID, &poolTeamID, &requiredTags, &needMoreReason,
 		); err != nil {
 			return nil, fmt.Errorf("scan error: %w", err)
 		}
@@ -568,10 +852,12 @@ func (p *PostgresRepo) GetAllPRs(ctx context.Context) ([]entityPr.PullRequest, e
 			pr = &entityPr.PullRequest{
 				Id:                prID.String,
 				Name:              prName.String,
-				Author:            entityUser.User{Id: authorID.String},
-				Status:            status.String,
+				Author:            entityUser.User{Id: authorID.String, TeamID: int(authorTeamID.Int64)},
+				Status:            entityPr.Status(status.String),
 				NeedMoreReviewers: needMore.Bool,
 				CreatedAt:         createdAt.Time,
+				RequiredTags:      requiredTags,
+				NeedMoreReason:    needMoreReason,
 			}
 			if mergedAt.Valid {
 				pr.MergedAt = &mergedAt.Time
@@ -581,6 +867,9 @@ func (p *PostgresRepo) GetAllPRs(ctx context.Context) ([]entityPr.PullRequest, e
 
 		if reviewerID.Valid {
 			pr.Reviewers = append(pr.Reviewers, entityUser.User{Id: reviewerID.String})
+			if poolTeamID.Valid {
+				pr.SetPool(reviewerID.String, int(poolTeamID.Int64))
+			}
 		}
 	}
 
@@ -591,3 +880,1476 @@ func (p *PostgresRepo) GetAllPRs(ctx context.Context) ([]entityPr.PullRequest, e
 
 	return result, nil
 }
+
+func (p *PostgresRepo) GetReviewLoad(ctx context.Context, userIDs []string) (map[string]entityUser.ReviewLoad, error) {
+	query := `
+		SELECT
+			r.reviewer_id,
+			COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open_reviews,
+			MAX(r.assigned_at) AS last_assigned_at
+		FROM pull_request_reviewers r
+		JOIN pull_requests pr
+			ON pr.pull_request_id = r.pull_request_id
+		WHERE r.reviewer_id = ANY($1)
+		GROUP BY r.reviewer_id
+	`
+	var rows []dto.ReviewLoadDto
+	if err := p.db.SelectContext(ctx, &rows, query, pq.Array(userIDs)); err != nil {
+		return nil, fmt.Errorf("query review load: %w", err)
+	}
+	result := make(map[string]entityUser.ReviewLoad, len(rows))
+	for _, r := range rows {
+		result[r.ReviewerID] = entityUser.ReviewLoad{
+			OpenReviews:    r.OpenReviews,
+			LastAssignedAt: r.LastAssignedAt,
+		}
+	}
+	return result, nil
+}
+
+func (p *PostgresRepo) ReplaceCodeOwners(ctx context.Context, teamID int, rules []entityOwners.Rule) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM code_owner_rules WHERE team_id = $1`, teamID); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("delete old code owner rules: %w", err)
+	}
+	for i, rule := range rules {
+		var ruleID int
+		if err := tx.QueryRowContext(ctx, `INSERT INTO code_owner_rules (team_id, position, pattern)
+			VALUES ($1, $2, $3)
+			RETURNING id`, teamID, i, rule.Pattern).Scan(&ruleID); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error inserting code owner rule %q: %w", rule.Pattern, err)
+		}
+		for j, owner := range rule.Owners {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO code_owner_rule_owners (rule_id, position, owner_kind, owner_name)
+				VALUES ($1, $2, $3, $4)`, ruleID, j, owner.Kind, owner.Name); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("error inserting owner %s for rule %q: %w", owner.Name, rule.Pattern, err)
+			}
+		}
+	}
+	if err := teamEventTx(ctx, tx, teamID, entityEvent.TeamUpdated); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresRepo) GetCodeOwners(ctx context.Context, teamID int) ([]entityOwners.Rule, error) {
+	query := `
+		SELECT r.id, r.pattern, o.owner_kind, o.owner_name
+		FROM code_owner_rules r
+		JOIN code_owner_rule_owners o ON o.rule_id = r.id
+		WHERE r.team_id = $1
+		ORDER BY r.position, o.position
+	`
+	var rows []dto.CodeOwnerRuleDto
+	if err := p.db.SelectContext(ctx, &rows, query, teamID); err != nil {
+		return nil, fmt.Errorf("query code owner rules: %w", err)
+	}
+	rules := make([]entityOwners.Rule, 0)
+	for i := 0; i < len(rows); {
+		owners := make([]entityOwners.Owner, 0)
+		j := i
+		for ; j < len(rows) && rows[j].RuleID == rows[i].RuleID; j++ {
+			owners = append(owners, entityOwners.Owner{Kind: rows[j].OwnerKind, Name: rows[j].OwnerName})
+		}
+		rule, err := entityOwners.NewRule(rows[i].Pattern, owners)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored pattern %q: %w", rows[i].Pattern, err)
+		}
+		rules = append(rules, rule)
+		i = j
+	}
+	return rules, nil
+}
+
+func (p *PostgresRepo) SetUserTags(ctx context.Context, userID string, tags []string) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_tags WHERE user_id = $1`, userID); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("delete old user tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_tags (user_id, tag) VALUES ($1, $2)`, userID, tag); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error inserting tag %s for user %s: %w", tag, userID, err)
+		}
+	}
+	if err := userEventTx(ctx, tx, userID, entityEvent.UserUpdated); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresRepo) GetUserTags(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	var rows []dto.UserTagDto
+	query := `
+		SELECT user_id, tag
+		FROM user_tags
+		WHERE user_id = ANY($1)
+		ORDER BY user_id, tag
+	`
+	if err := p.db.SelectContext(ctx, &rows, query, pq.Array(userIDs)); err != nil {
+		return nil, fmt.Errorf("query user tags: %w", err)
+	}
+	res := make(map[string][]string, len(userIDs))
+	for _, row := range rows {
+		res[row.UserID] = append(res[row.UserID], row.Tag)
+	}
+	return res, nil
+}
+
+func (p *PostgresRepo) AddAvailability(ctx context.Context, w entityUser.Availability) (int, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var id int
+	query := `INSERT INTO user_availability (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+	if err := tx.QueryRowContext(ctx, query, w.UserID, w.From, w.To, w.Reason).Scan(&id); err != nil {
+		return 0, fmt.Errorf("error inserting availability window: %w", err)
+	}
+	if err := userEventTx(ctx, tx, w.UserID, entityEvent.UserUpdated); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (p *PostgresRepo) GetAvailability(ctx context.Context, userID string) ([]entityUser.Availability, error) {
+	var rows []dto.AvailabilityDto
+	query := `
+		SELECT id, user_id, starts_at, ends_at, reason
+		FROM user_availability
+		WHERE user_id = $1 AND ends_at > NOW()
+		ORDER BY starts_at
+	`
+	if err := p.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("query availability of user %s: %w", userID, err)
+	}
+	return availabilityFromDto(rows), nil
+}
+
+func (p *PostgresRepo) DeleteAvailability(ctx context.Context, userID string, id int) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	res, err := tx.ExecContext(ctx, `DELETE FROM user_availability WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("error deleting availability window: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrAvailabilityNotFound
+	}
+	if err := userEventTx(ctx, tx, userID, entityEvent.UserUpdated); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// GetStartingAvailability - окна, которые уже начались, но ещё не были обработаны
+func (p *PostgresRepo) GetStartingAvailability(ctx context.Context, now time.Time) ([]entityUser.Availability, error) {
+	var rows []dto.AvailabilityDto
+	query := `
+		SELECT id, user_id, starts_at, ends_at, reason
+		FROM user_availability
+		WHERE NOT started AND NOT ended AND starts_at <= $1 AND ends_at > $1
+		ORDER BY starts_at
+	`
+	if err := p.db.SelectContext(ctx, &rows, query, now); err != nil {
+		return nil, fmt.Errorf("query starting availability windows: %w", err)
+	}
+	return availabilityFromDto(rows), nil
+}
+
+// GetEndingAvailability - окна, которые уже закончились, но ещё не были обработаны
+func (p *PostgresRepo) GetEndingAvailability(ctx context.Context, now time.Time) ([]entityUser.Availability, error) {
+	var rows []dto.AvailabilityDto
+	query := `
+		SELECT id, user_id, starts_at, ends_at, reason
+		FROM user_availability
+		WHERE NOT ended AND ends_at <= $1
+		ORDER BY ends_at
+	`
+	if err := p.db.SelectContext(ctx, &rows, query, now); err != nil {
+		return nil, fmt.Errorf("query ending availability windows: %w", err)
+	}
+	return availabilityFromDto(rows), nil
+}
+
+func (p *PostgresRepo) MarkAvailabilityStarted(ctx context.Context, id int) error {
+	if _, err := p.db.ExecContext(ctx, `UPDATE user_availability SET started = TRUE WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("error marking availability window %d as started: %w", id, err)
+	}
+	return nil
+}
+
+func (p *PostgresRepo) MarkAvailabilityEnded(ctx context.Context, id int) error {
+	if _, err := p.db.ExecContext(ctx, `UPDATE user_availability SET started = TRUE, ended = TRUE WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("error marking availability window %d as ended: %w", id, err)
+	}
+	return nil
+}
+
+func availabilityFromDto(rows []dto.AvailabilityDto) []entityUser.Availability {
+	res := make([]entityUser.Availability, 0, len(rows))
+	for _, r := range rows {
+		res = append(res, entityUser.Availability{
+			Id:     r.Id,
+			UserID: r.UserID,
+			From:   r.StartsAt,
+			To:     r.EndsAt,
+			Reason: r.Reason,
+		})
+	}
+	return res
+}
+
+func (p *PostgresRepo) SetMaxOpenReviews(ctx context.Context, userID string, maxOpen int) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE users SET max_open_reviews = NULLIF($1, 0) WHERE user_id = $2`, maxOpen, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error updating max open reviews: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		_ = tx.Rollback()
+		return ErrNoUserWithId
+	}
+	if err := userEventTx(ctx, tx, userID, entityEvent.UserUpdated); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// SetUserEmail задаёт адрес для писем пользователю, пустая строка удаляет адрес
+func (p *PostgresRepo) SetUserEmail(ctx context.Context, userID string, email string) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE users SET email = NULLIF($1, '') WHERE user_id = $2`, email, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("error updating email: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		_ = tx.Rollback()
+		return ErrNoUserWithId
+	}
+	if err := userEventTx(ctx, tx, userID, entityEvent.UserUpdated); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// GetUserEmails возвращает адреса пользователей, у кого адрес задан (user_id -> email)
+func (p *PostgresRepo) GetUserEmails(ctx context.Context, userIDs []string) (map[string]string, error) {
+	res := make(map[string]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+	var rows []dto.UserDto
+	if err := p.db.SelectContext(ctx, &rows, `SELECT user_id, email FROM users
+		WHERE user_id = ANY($1) AND email IS NOT NULL`, pq.Array(userIDs)); err != nil {
+		return nil, fmt.Errorf("error getting user emails: %w", err)
+	}
+	for _, r := range rows {
+		res[r.Id] = r.Email
+	}
+	return res, nil
+}
+
+// GetUsersWithEmail возвращает активных пользователей с адресом, им отправляется ежедневная сводка
+func (p *PostgresRepo) GetUsersWithEmail(ctx context.Context) ([]entityUser.User, error) {
+	var rows []dto.UserDto
+	if err := p.db.SelectContext(ctx, &rows, `SELECT user_id, username, team_id, is_active, email FROM users
+		WHERE email IS NOT NULL AND is_active
+		ORDER BY user_id`); err != nil {
+		return nil, fmt.Errorf("error getting users with email: %w", err)
+	}
+	res := make([]entityUser.User, 0, len(rows))
+	for _, r := range rows {
+		res = append(res, entityUser.User{Id: r.Id, Name: r.Name, TeamID: r.TeamID, IsActive: r.IsActive, Email: r.Email})
+	}
+	return res, nil
+}
+
+// ClaimDigest отмечает сводку пользователя за день, false - сводка за этот день уже отправлена
+func (p *PostgresRepo) ClaimDigest(ctx context.Context, userID string, day time.Time) (bool, error) {
+	res, err := p.db.ExecContext(ctx, `INSERT INTO email_digests (user_id, digest_date)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, userID, day.Format(time.DateOnly))
+	if err != nil {
+		return false, fmt.Errorf("error claiming digest for %s: %w", userID, err)
+	}
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+// ReleaseDigest забывает сводку, чтобы её отправили повторно после ошибки
+func (p *PostgresRepo) ReleaseDigest(ctx context.Context, userID string, day time.Time) error {
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM email_digests WHERE user_id = $1 AND digest_date = $2`,
+		userID, day.Format(time.DateOnly)); err != nil {
+		return fmt.Errorf("error releasing digest for %s: %w", userID, err)
+	}
+	return nil
+}
+
+func (p *PostgresRepo) AddReview(ctx context.Context, r entityPr.Review) (int, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	var id int
+	err = tx.QueryRowContext(ctx, `INSERT INTO pull_request_reviews (pull_request_id, reviewer_id, verdict, body, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`, r.PrID, r.ReviewerID, r.Verdict, r.Body, r.CreatedAt).Scan(&id)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("error inserting review: %w", err)
+	}
+	pr, err := lockPrTx(ctx, tx, r.PrID)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := addOutboxTx(ctx, tx, entityEvent.NewPrReviewed(*pr, r)); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return id, nil
+}
+
+// GetReviews возвращает ревью PR в порядке их создания
+func (p *PostgresRepo) GetReviews(ctx context.Context, prID string) ([]entityPr.Review, error) {
+	var rows []dto.ReviewDto
+	err := p.db.SelectContext(ctx, &rows, `SELECT id, pull_request_id, reviewer_id, verdict, body, created_at
+		FROM pull_request_reviews
+		WHERE pull_request_id = $1
+		ORDER BY created_at, id`, prID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reviews of PR %s: %w", prID, err)
+	}
+	res := make([]entityPr.Review, 0, len(rows))
+	for _, r := range rows {
+		res = append(res, entityPr.Review{
+			Id:         r.Id,
+			PrID:       r.PullRequestID,
+			ReviewerID: r.ReviewerID,
+			Verdict:    entityPr.Verdict(r.Verdict),
+			Body:       r.Body,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (p *PostgresRepo) GetUserGrants(ctx context.Context, userID string) ([]entityAuth.Grant, error) {
+	var rows []dto.GrantDto
+	if err := p.db.SelectContext(ctx, &rows, `SELECT r.role, r.team_id, COALESCE(t.team_name, '') AS team_name
+		FROM user_roles r
+		LEFT JOIN teams t ON t.id = r.team_id
+		WHERE r.user_id = $1
+		ORDER BY r.role, t.team_name`, userID); err != nil {
+		return nil, fmt.Errorf("error getting roles of user %s: %w", userID, err)
+	}
+	res := make([]entityAuth.Grant, 0, len(rows))
+	for _, r := range rows {
+		g := entityAuth.Grant{Role: entityAuth.Role(r.Role), TeamName: r.TeamName}
+		if r.TeamID != nil {
+			g.TeamID = *r.TeamID
+		}
+		res = append(res, g)
+	}
+	return res, nil
+}
+
+// GrantRole выдаёт роль, повторная выдача ничего не меняет и события не пишет
+func (p *PostgresRepo) GrantRole(ctx context.Context, userID string, g entityAuth.Grant) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	res, err := tx.ExecContext(ctx, `INSERT INTO user_roles (user_id, role, team_id)
+		VALUES ($1, $2, NULLIF($3, 0))
+		ON CONFLICT (user_id, role, (COALESCE(team_id, 0))) DO NOTHING`, userID, g.Role, g.TeamID)
+	if err != nil {
+		return fmt.Errorf("error granting role %s to user %s: %w", g.Role, userID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows > 0 {
+		if err := userEventTx(ctx, tx, userID, entityEvent.UserUpdated); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresRepo) RevokeRole(ctx context.Context, userID string, g entityAuth.Grant) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	res, err := tx.ExecContext(ctx, `DELETE FROM user_roles
+		WHERE user_id = $1 AND role = $2 AND COALESCE(team_id, 0) = $3`, userID, g.Role, g.TeamID)
+	if err != nil {
+		return fmt.Errorf("error revoking role %s from user %s: %w", g.Role, userID, err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrGrantNotFound
+	}
+	if err := userEventTx(ctx, tx, userID, entityEvent.UserUpdated); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+const apiKeyColumns = `id, name, key_prefix, key_hash, scopes, created_by, created_at, expires_at, last_used_at, revoked_at`
+
+func (p *PostgresRepo) AddAPIKey(ctx context.Context, k entityAuth.APIKey) (int, error) {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var id int
+	query := `INSERT INTO api_keys (name, key_prefix, key_hash, scopes, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+	if err := tx.QueryRowContext(ctx, query, k.Name, k.Prefix, k.Hash, pq.Array(scopes), k.CreatedBy, k.ExpiresAt).Scan(&id); err != nil {
+		return 0, fmt.Errorf("error inserting api key: %w", err)
+	}
+	k.Id = id
+	if err := addOutboxTx(ctx, tx, entityEvent.NewAPIKeyEvent(entityEvent.APIKeyCreated, k)); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (p *PostgresRepo) GetAPIKeys(ctx context.Context) ([]entityAuth.APIKey, error) {
+	var rows []dto.APIKeyDto
+	if err := p.db.SelectContext(ctx, &rows, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY id`); err != nil {
+		return nil, fmt.Errorf("error getting api keys: %w", err)
+	}
+	res := make([]entityAuth.APIKey, 0, len(rows))
+	for _, r := range rows {
+		res = append(res, apiKeyFromDto(r))
+	}
+	return res, nil
+}
+
+// GetAPIKeyByHash возвращает неотозванный ключ по хешу
+func (p *PostgresRepo) GetAPIKeyByHash(ctx context.Context, hash string) (*entityAuth.APIKey, error) {
+	var row dto.APIKeyDto
+	err := p.db.GetContext(ctx, &row, `SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL`, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("error getting api key: %w", err)
+	}
+	k := apiKeyFromDto(row)
+	return &k, nil
+}
+
+func (p *PostgresRepo) RevokeAPIKey(ctx context.Context, id int, at time.Time) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var row dto.APIKeyDto
+	err = tx.GetContext(ctx, &row, `UPDATE api_keys SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING `+apiKeyColumns, id, at)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("error revoking api key %d: %w", id, err)
+	}
+	if err := addOutboxTx(ctx, tx, entityEvent.NewAPIKeyEvent(entityEvent.APIKeyRevoked, apiKeyFromDto(row))); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresRepo) TouchAPIKey(ctx context.Context, id int, at time.Time) error {
+	if _, err := p.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("error updating last use of api key %d: %w", id, err)
+	}
+	return nil
+}
+
+func apiKeyFromDto(r dto.APIKeyDto) entityAuth.APIKey {
+	scopes := make([]entityAuth.Permission, 0, len(r.Scopes))
+	for _, s := range r.Scopes {
+		scopes = append(scopes, entityAuth.Permission(s))
+	}
+	return entityAuth.APIKey{
+		Id:         r.Id,
+		Name:       r.Name,
+		Prefix:     r.Prefix,
+		Hash:       r.Hash,
+		Scopes:     scopes,
+		CreatedBy:  r.CreatedBy,
+		CreatedAt:  r.CreatedAt,
+		ExpiresAt:  r.ExpiresAt,
+		LastUsedAt: r.LastUsedAt,
+		RevokedAt:  r.RevokedAt,
+	}
+}
+
+func (p *PostgresRepo) AddWebhook(ctx context.Context, w entityHook.Subscription) (int, error) {
+	events := make([]string, 0, len(w.Events))
+	for _, e := range w.Events {
+		events = append(events, string(e))
+	}
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var id int
+	query := `INSERT INTO webhooks (team_id, url, secret, events)
+		VALUES (NULLIF($1, 0), $2, $3, $4)
+		RETURNING id`
+	if err := tx.QueryRowContext(ctx, query, w.TeamID, w.URL, w.Secret, pq.Array(events)).Scan(&id); err != nil {
+		return 0, fmt.Errorf("error inserting webhook: %w", err)
+	}
+	if err := addOutboxTx(ctx, tx, entityEvent.NewWebhookEvent(entityEvent.WebhookCreated, id, w.TeamID, w.Events)); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (p *PostgresRepo) GetWebhooks(ctx context.Context) ([]entityHook.Subscription, error) {
+	var rows []dto.WebhookDto
+	query := `SELECT w.id, w.team_id, COALESCE(t.team_name, '') AS team_name, w.url, w.secret, w.events, w.created_at
+		FROM webhooks w
+		LEFT JOIN teams t ON t.id = w.team_id
+		ORDER BY w.id`
+	if err := p.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("error getting webhooks: %w", err)
+	}
+	res := make([]entityHook.Subscription, 0, len(rows))
+	for _, r := range rows {
+		sub := entityHook.Subscription{
+			Id:        r.Id,
+			TeamName:  r.TeamName,
+			URL:       r.URL,
+			Secret:    r.Secret,
+			CreatedAt: r.CreatedAt,
+		}
+		if r.TeamID != nil {
+			sub.TeamID = *r.TeamID
+		}
+		for _, e := range r.Events {
+			sub.Events = append(sub.Events, entityEvent.Type(e))
+		}
+		res = append(res, sub)
+	}
+	return res, nil
+}
+
+func (p *PostgresRepo) DeleteWebhook(ctx context.Context, id int) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var row struct {
+		TeamID int            `db:"team_id"`
+		Events pq.StringArray `db:"events"`
+	}
+	err = tx.GetContext(ctx, &row, `DELETE FROM webhooks WHERE id = $1 RETURNING COALESCE(team_id, 0) AS team_id, events`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrWebhookNotFound
+		}
+		return fmt.Errorf("error deleting webhook %d: %w", id, err)
+	}
+	events := make([]entityEvent.Type, 0, len(row.Events))
+	for _, e := range row.Events {
+		events = append(events, entityEvent.Type(e))
+	}
+	if err := addOutboxTx(ctx, tx, entityEvent.NewWebhookEvent(entityEvent.WebhookDeleted, id, row.TeamID, events)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// AddDeliveries ставит доставки в очередь, повторная доставка того же события тому же подписчику игнорируется
+func (p *PostgresRepo) AddDeliveries(ctx context.Context, deliveries []entityHook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`
+	for _, d := range deliveries {
+		if _, err := tx.ExecContext(ctx, query, d.SubscriptionID, d.EventID, d.EventType, d.Payload, d.NextAttemptAt); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error inserting delivery of %s to webhook %d: %w", d.EventID, d.SubscriptionID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+const deliveryColumns = `d.id, d.webhook_id, w.url, w.secret, d.event_id, d.event_type, d.payload, d.status,
+	d.attempts, d.next_attempt_at, d.last_error, d.response_code, d.created_at, d.delivered_at`
+
+// ClaimDueDeliveries забирает доставки, время попытки которых наступило, и откладывает их на lease,
+// чтобы другие реплики не отправили их одновременно. Если отправка не завершится, доставка вернётся после lease
+func (p *PostgresRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entityHook.Delivery, error) {
+	var rows []dto.DeliveryDto
+	query := `UPDATE webhook_deliveries d SET next_attempt_at = $2
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+	if err := p.db.SelectContext(ctx, &rows, query, now, now.Add(lease), limit); err != nil {
+		return nil, fmt.Errorf("error claiming webhook deliveries: %w", err)
+	}
+	return deliveriesFromDto(rows), nil
+}
+
+func (p *PostgresRepo) UpdateDelivery(ctx context.Context, d entityHook.Delivery) error {
+	res, err := p.db.ExecContext(ctx, `UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, response_code = $6, delivered_at = $7
+		WHERE id = $1`, d.Id, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.ResponseCode, d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("error updating webhook delivery %d: %w", d.Id, err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func (p *PostgresRepo) GetDeliveries(ctx context.Context, status entityHook.DeliveryStatus, limit int) ([]entityHook.Delivery, error) {
+	var rows []dto.DeliveryDto
+	query := `SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = $1
+		ORDER BY d.created_at DESC
+		LIMIT $2`
+	if err := p.db.SelectContext(ctx, &rows, query, status, limit); err != nil {
+		return nil, fmt.Errorf("error getting webhook deliveries: %w", err)
+	}
+	return deliveriesFromDto(rows), nil
+}
+
+func (p *PostgresRepo) GetDelivery(ctx context.Context, id int) (*entityHook.Delivery, error) {
+	var rows []dto.DeliveryDto
+	query := `SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.id = $1`
+	if err := p.db.SelectContext(ctx, &rows, query, id); err != nil {
+		return nil, fmt.Errorf("error getting webhook delivery %d: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrDeliveryNotFound
+	}
+	d := deliveriesFromDto(rows)[0]
+	return &d, nil
+}
+
+func deliveriesFromDto(rows []dto.DeliveryDto) []entityHook.Delivery {
+	res := make([]entityHook.Delivery, 0, len(rows))
+	for _, r := range rows {
+		res = append(res, entityHook.Delivery{
+			Id:             r.Id,
+			SubscriptionID: r.WebhookID,
+			URL:            r.URL,
+			Secret:         r.Secret,
+			EventID:        r.EventID,
+			EventType:      entityEvent.Type(r.EventType),
+			Payload:        r.Payload,
+			Status:         entityHook.DeliveryStatus(r.Status),
+			Attempts:       r.Attempts,
+			NextAttemptAt:  r.NextAttemptAt,
+			LastError:      r.LastError,
+			ResponseCode:   r.ResponseCode,
+			CreatedAt:      r.CreatedAt,
+			DeliveredAt:    r.DeliveredAt,
+		})
+	}
+	return res
+}
+
+// lockPrTx блокирует строку PR до конца транзакции и читает его состояние.
+// Пока блокировка держится, другие транзакции не запишут в outbox событие того же PR,
+// поэтому номера записей outbox одного агрегата идут в порядке коммитов
+func lockPrTx(ctx context.Context, tx *sqlx.Tx, prID string) (*entityPr.PullRequest, error) {
+	var locked string
+	if err := tx.GetContext(ctx, &locked, `SELECT pull_request_id FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE`, prID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPrNotFound
+		}
+		return nil, fmt.Errorf("error locking pull request %s: %w", prID, err)
+	}
+	return getPr(ctx, tx, prID)
+}
+
+// reviewersEventTx пишет в историю ревьюеров и в outbox pr.reviewers_changed с тем, кого назначили и кого сняли
+func reviewersEventTx(ctx context.Context, tx *sqlx.Tx, prID string, added, removed []string) error {
+	pr, err := lockPrTx(ctx, tx, prID)
+	if err != nil {
+		return err
+	}
+	reason := entityEvent.ReasonFrom(ctx)
+	if err := addAssignmentsTx(ctx, tx, entityPr.AssignmentChanges(prID, added, removed, reason, actorFrom(ctx))); err != nil {
+		return err
+	}
+	return addOutboxTx(ctx, tx, entityEvent.NewPrReviewersChanged(*pr, added, removed, reason))
+}
+
+// addAssignmentsTx дописывает историю ревьюеров в той же транзакции, что и изменение ревьюеров
+func addAssignmentsTx(ctx context.Context, tx *sqlx.Tx, changes []entityPr.Assignment) error {
+	for _, a := range changes {
+		_, err := tx.ExecContext(ctx, `INSERT INTO reviewer_assignments (pull_request_id, reviewer_id, action, replaced_by, reason, actor)
+			VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)`, a.PrID, a.ReviewerID, a.Action, a.ReplacedBy, a.Reason, a.Actor)
+		if err != nil {
+			return fmt.Errorf("error recording %s of reviewer %s on PR %s: %w", a.Action, a.ReviewerID, a.PrID, err)
+		}
+	}
+	return nil
+}
+
+// actorFrom - автор изменения для истории: subject вызывающего из контекста, у фоновых задач - system
+func actorFrom(ctx context.Context) string {
+	if p, ok := entityAuth.FromContext(ctx); ok && p.Subject != "" {
+		return p.Subject
+	}
+	return entityPr.SystemActor
+}
+
+// prEventTx пишет в outbox событие PR с его состоянием внутри транзакции
+func prEventTx(ctx context.Context, tx *sqlx.Tx, prID string, t entityEvent.Type) error {
+	pr, err := lockPrTx(ctx, tx, prID)
+	if err != nil {
+		return err
+	}
+	return addOutboxTx(ctx, tx, entityEvent.NewPrEvent(t, *pr, entityEvent.ReasonFrom(ctx)))
+}
+
+// userEventTx пишет в outbox событие пользователя, блокируя его строку, как lockPrTx
+func userEventTx(ctx context.Context, tx *sqlx.Tx, userID string, t entityEvent.Type) error {
+	var u dto.UserDto
+	err := tx.GetContext(ctx, &u, `SELECT user_id, username, team_id, is_active FROM users WHERE user_id = $1 FOR UPDATE`, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoUserWithId
+		}
+		return fmt.Errorf("error locking user %s: %w", userID, err)
+	}
+	return addOutboxTx(ctx, tx, entityEvent.NewUserEvent(t, entityUser.User{
+		Id:       u.Id,
+		Name:     u.Name,
+		TeamID:   u.TeamID,
+		IsActive: u.IsActive,
+	}))
+}
+
+// teamEventTx пишет в outbox событие команды со списком её участников, блокируя строку команды
+func teamEventTx(ctx context.Context, tx *sqlx.Tx, teamID int, t entityEvent.Type) error {
+	var name string
+	if err := tx.GetContext(ctx, &name, `SELECT team_name FROM teams WHERE id = $1 FOR UPDATE`, teamID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTeamNotFound
+		}
+		return fmt.Errorf("error locking team %d: %w", teamID, err)
+	}
+	var members []string
+	if err := tx.SelectContext(ctx, &members, `SELECT user_id FROM users WHERE team_id = $1 ORDER BY user_id`, teamID); err != nil {
+		return fmt.Errorf("error getting members of team %d: %w", teamID, err)
+	}
+	team := entityTeam.Team{Id: teamID, Name: name}
+	for _, id := range members {
+		team.Users = append(team.Users, entityUser.User{Id: id})
+	}
+	return addOutboxTx(ctx, tx, entityEvent.NewTeamEvent(t, team))
+}
+
+// addOutboxTx сохраняет событие в outbox в той же транзакции, что и изменение,
+// поэтому событие публикуется тогда и только тогда, когда изменение закоммичено
+func addOutboxTx(ctx context.Context, tx *sqlx.Tx, e entityEvent.Event) error {
+	payload, err := json.Marshal(e.Data)
+	if err != nil {
+		return fmt.Errorf("error encoding event %s: %w", e.Type, err)
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO outbox (event_id, event_type, aggregate_type, aggregate_id, team_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`, e.Id, e.Type, e.Aggregate, e.AggregateID, e.TeamID, payload, e.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("error inserting %s into outbox: %w", e.Type, err)
+	}
+	return nil
+}
+
+// outboxLockID - ключ advisory lock, под которым relay забирает пачку событий, чтобы реплики
+// не забрали события одного агрегата не по порядку. Публикация идёт уже без блокировки
+const outboxLockID = 20251130
+
+// outboxLease - на сколько relay забирает пачку: пока срок не истёк, события пачки не забирают другие реплики,
+// после него события реплики, упавшей до отметки, публикуются снова
+const outboxLease = time.Minute
+
+// outboxMaxAttempts - после стольких неудачных публикаций событие получает failed_at и больше не публикуется,
+// чтобы оно не держало остальные события своего агрегата
+const outboxMaxAttempts = 10
+
+// PublishOutbox забирает до limit неотправленных событий на outboxLease короткой транзакцией, затем отдаёт их
+// publish в порядке записи вне транзакции и отмечает каждое отдельным запросом, поэтому медленный подписчик
+// не держит транзакцию и блокировку. Пока у агрегата есть событие с неудачными попытками или забранное
+// другой репликой, следующие события агрегата не забираются, чтобы не нарушить их порядок и не занимать limit.
+// После outboxMaxAttempts попыток событие отмечается failed_at и агрегат освобождается
+func (p *PostgresRepo) PublishOutbox(ctx context.Context, limit int, publish func(context.Context, entityEvent.Event) error) (int, error) {
+	rows, err := p.claimOutbox(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	blocked := make(map[string]struct{})
+	var skipped []int64
+	for _, row := range rows {
+		aggregate := row.AggregateType + ":" + row.AggregateID
+		if _, ok := blocked[aggregate]; ok {
+			skipped = append(skipped, row.Id)
+			continue
+		}
+		if err := publish(ctx, outboxEventFromDto(row)); err != nil {
+			blocked[aggregate] = struct{}{}
+			if _, err := p.db.ExecContext(ctx, `UPDATE outbox
+				SET attempts = attempts + 1, last_error = $2, locked_until = NULL,
+					failed_at = CASE WHEN attempts + 1 >= $3 THEN NOW() END
+				WHERE id = $1`, row.Id, err.Error(), outboxMaxAttempts); err != nil {
+				return sent, fmt.Errorf("error recording outbox failure %d: %w", row.Id, err)
+			}
+			continue
+		}
+		if _, err := p.db.ExecContext(ctx, `UPDATE outbox
+			SET attempts = attempts + 1, last_error = '', sent_at = NOW(), locked_until = NULL
+			WHERE id = $1`, row.Id); err != nil {
+			return sent, fmt.Errorf("error marking outbox event %d sent: %w", row.Id, err)
+		}
+		sent++
+	}
+	if len(skipped) > 0 {
+		if _, err := p.db.ExecContext(ctx, `UPDATE outbox SET locked_until = NULL WHERE id = ANY($1)`, pq.Array(skipped)); err != nil {
+			return sent, fmt.Errorf("error releasing outbox events: %w", err)
+		}
+	}
+	return sent, nil
+}
+
+// claimOutbox забирает пачку событий на outboxLease. Пока другая реплика забирает свою пачку, возвращает пустую
+func (p *PostgresRepo) claimOutbox(ctx context.Context, limit int) ([]dto.OutboxDto, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var locked bool
+	if err := tx.GetContext(ctx, &locked, `SELECT pg_try_advisory_xact_lock($1)`, outboxLockID); err != nil {
+		return nil, fmt.Errorf("error locking outbox: %w", err)
+	}
+	if !locked {
+		return nil, nil
+	}
+	var rows []dto.OutboxDto
+	if err := tx.SelectContext(ctx, &rows, `UPDATE outbox
+		SET locked_until = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT o.id
+			FROM outbox o
+			WHERE o.sent_at IS NULL AND o.failed_at IS NULL
+				AND (o.locked_until IS NULL OR o.locked_until < NOW())
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox b
+					WHERE b.aggregate_type = o.aggregate_type AND b.aggregate_id = o.aggregate_id AND b.id < o.id
+						AND b.sent_at IS NULL AND b.failed_at IS NULL
+						AND (b.attempts > 0 OR b.locked_until >= NOW())
+				)
+			ORDER BY o.id
+			LIMIT $1
+			FOR UPDATE OF o SKIP LOCKED
+		)
+		RETURNING id, event_id, event_type, aggregate_type, aggregate_id, team_id, payload, occurred_at`,
+		limit, outboxLease.Seconds()); err != nil {
+		return nil, fmt.Errorf("error claiming pending outbox events: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Id < rows[j].Id })
+	return rows, nil
+}
+
+// GetOutboxEvents возвращает события outbox с номером больше afterSeq в порядке номеров, отправленные и нет.
+// Записи outbox не удаляются, поэтому по ним любая реплика может продолжить поток событий
+func (p *PostgresRepo) GetOutboxEvents(ctx context.Context, afterSeq int64, limit int) ([]entityEvent.Event, error) {
+	var rows []dto.OutboxDto
+	if err := p.db.SelectContext(ctx, &rows, `SELECT id, event_id, event_type, aggregate_type, aggregate_id, team_id, payload, occurred_at
+		FROM outbox
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2`, afterSeq, limit); err != nil {
+		return nil, fmt.Errorf("error getting outbox events after %d: %w", afterSeq, err)
+	}
+	res := make([]entityEvent.Event, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, outboxEventFromDto(row))
+	}
+	return res, nil
+}
+
+// GetLastOutboxSeq возвращает номер последнего события outbox, 0 - событий нет
+func (p *PostgresRepo) GetLastOutboxSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	if err := p.db.GetContext(ctx, &seq, `SELECT COALESCE(MAX(id), 0) FROM outbox`); err != nil {
+		return 0, fmt.Errorf("error getting last outbox event: %w", err)
+	}
+	return seq, nil
+}
+
+func outboxEventFromDto(row dto.OutboxDto) entityEvent.Event {
+	return entityEvent.Event{
+		Id:          row.EventID,
+		Seq:         row.Id,
+		Type:        entityEvent.Type(row.EventType),
+		Aggregate:   row.AggregateType,
+		AggregateID: row.AggregateID,
+		TeamID:      row.TeamID,
+		OccurredAt:  row.OccurredAt,
+		Data:        json.RawMessage(row.Payload),
+	}
+}
+
+// SetCodeHostUser связывает логин с пользователем. Если логин был связан с другим пользователем,
+// событие пишется и для него
+func (p *PostgresRepo) SetCodeHostUser(ctx context.Context, m entityHost.UserMapping) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var previous string
+	err = tx.GetContext(ctx, &previous, `SELECT user_id FROM code_host_users WHERE provider = $1 AND login = $2 FOR UPDATE`, m.Provider, m.Login)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error getting %s user %s: %w", m.Provider, m.Login, err)
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO code_host_users (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login) DO UPDATE SET user_id = EXCLUDED.user_id`, m.Provider, m.Login, m.UserID)
+	if err != nil {
+		return fmt.Errorf("error mapping %s user %s: %w", m.Provider, m.Login, err)
+	}
+	if previous != "" && previous != m.UserID {
+		if err := userEventTx(ctx, tx, previous, entityEvent.UserUpdated); err != nil {
+			return err
+		}
+	}
+	if previous != m.UserID {
+		if err := userEventTx(ctx, tx, m.UserID, entityEvent.UserUpdated); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresRepo) DeleteCodeHostUser(ctx context.Context, provider entityHost.Provider, login string) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var userID string
+	err = tx.GetContext(ctx, &userID, `DELETE FROM code_host_users WHERE provider = $1 AND login = $2 RETURNING user_id`, provider, login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCodeHostUserNotFound
+		}
+		return fmt.Errorf("error unmapping %s user %s: %w", provider, login, err)
+	}
+	if err := userEventTx(ctx, tx, userID, entityEvent.UserUpdated); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresRepo) GetCodeHostUser(ctx context.Context, provider entityHost.Provider, login string) (*entityHost.UserMapping, error) {
+	var row dto.CodeHostUserDto
+	err := p.db.GetContext(ctx, &row, `SELECT provider, login, user_id FROM code_host_users WHERE provider = $1 AND login = $2`, provider, login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCodeHostUserNotFound
+		}
+		return nil, fmt.Errorf("error getting %s user %s: %w", provider, login, err)
+	}
+	m := codeHostUserFromDto(row)
+	return &m, nil
+}
+
+func (p *PostgresRepo) GetCodeHostUsers(ctx context.Context, provider entityHost.Provider) ([]entityHost.UserMapping, error) {
+	var rows []dto.CodeHostUserDto
+	if err := p.db.SelectContext(ctx, &rows, `SELECT provider, login, user_id FROM code_host_users
+		WHERE provider = $1
+		ORDER BY login`, provider); err != nil {
+		return nil, fmt.Errorf("error getting %s users: %w", provider, err)
+	}
+	res := make([]entityHost.UserMapping, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, codeHostUserFromDto(row))
+	}
+	return res, nil
+}
+
+func codeHostUserFromDto(row dto.CodeHostUserDto) entityHost.UserMapping {
+	return entityHost.UserMapping{
+		Provider: entityHost.Provider(row.Provider),
+		Login:    row.Login,
+		UserID:   row.UserID,
+	}
+}
+
+// ClaimCodeHostDelivery запоминает id доставки вебхука, false - доставка уже обрабатывалась
+func (p *PostgresRepo) ClaimCodeHostDelivery(ctx context.Context, provider entityHost.Provider, deliveryID string) (bool, error) {
+	res, err := p.db.ExecContext(ctx, `INSERT INTO code_host_deliveries (provider, delivery_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, provider, deliveryID)
+	if err != nil {
+		return false, fmt.Errorf("error claiming %s delivery %s: %w", provider, deliveryID, err)
+	}
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+// ReleaseCodeHostDelivery забывает доставку, чтобы провайдер мог повторить её после ошибки
+func (p *PostgresRepo) ReleaseCodeHostDelivery(ctx context.Context, provider entityHost.Provider, deliveryID string) error {
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM code_host_deliveries WHERE provider = $1 AND delivery_id = $2`, provider, deliveryID); err != nil {
+		return fmt.Errorf("error releasing %s delivery %s: %w", provider, deliveryID, err)
+	}
+	return nil
+}
+
+const codeHostLinkColumns = `pull_request_id, provider, repo_id, repo, number, sync_status, version, attempts,
+	next_attempt_at, last_error, synced_reviewers, synced_at`
+
+// AddCodeHostLink связывает PR с PR у провайдера, повторная связь того же PR игнорируется
+func (p *PostgresRepo) AddCodeHostLink(ctx context.Context, l entityHost.Link) error {
+	_, err := p.db.ExecContext(ctx, `INSERT INTO code_host_links (pull_request_id, provider, repo_id, repo, number, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (pull_request_id) DO NOTHING`, l.PrID, l.Provider, l.RepoID, l.Repo, l.Number, l.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("error linking %s to %s: %w", l.PrID, l.Provider, err)
+	}
+	return nil
+}
+
+func getCodeHostLink(ctx context.Context, q sqlx.QueryerContext, prID string) (*entityHost.Link, error) {
+	var rows []dto.CodeHostLinkDto
+	if err := sqlx.SelectContext(ctx, q, &rows, `SELECT `+codeHostLinkColumns+` FROM code_host_links WHERE pull_request_id = $1`, prID); err != nil {
+		return nil, fmt.Errorf("get code host link: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	l := codeHostLinkFromDto(rows[0])
+	return &l, nil
+}
+
+// MarkReviewerSyncPending ставит перенос ревьюеров PR в очередь, PR без связи с провайдером пропускаются
+func (p *PostgresRepo) MarkReviewerSyncPending(ctx context.Context, prID string, now time.Time) error {
+	_, err := p.db.ExecContext(ctx, `UPDATE code_host_links
+		SET sync_status = 'PENDING', version = version + 1, attempts = 0, next_attempt_at = $2
+		WHERE pull_request_id = $1`, prID, now)
+	if err != nil {
+		return fmt.Errorf("error marking reviewer sync of %s: %w", prID, err)
+	}
+	return nil
+}
+
+// ClaimDueReviewerSyncs забирает PR, ревьюеров которых пора перенести, и откладывает их на lease,
+// чтобы другие реплики не взяли их одновременно
+func (p *PostgresRepo) ClaimDueReviewerSyncs(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entityHost.Link, error) {
+	var rows []dto.CodeHostLinkDto
+	query := `UPDATE code_host_links SET next_attempt_at = $2
+		WHERE pull_request_id IN (
+			SELECT pull_request_id FROM code_host_links
+			WHERE sync_status = 'PENDING' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + codeHostLinkColumns
+	if err := p.db.SelectContext(ctx, &rows, query, now, now.Add(lease), limit); err != nil {
+		return nil, fmt.Errorf("error claiming reviewer syncs: %w", err)
+	}
+	res := make([]entityHost.Link, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, codeHostLinkFromDto(row))
+	}
+	return res, nil
+}
+
+// UpdateReviewerSync сохраняет результат попытки. Перенесённые ревьюеры записываются всегда,
+// а статус - только если ревьюеры PR не менялись с момента, когда попытка началась (версия совпадает)
+func (p *PostgresRepo) UpdateReviewerSync(ctx context.Context, l entityHost.Link) error {
+	res, err := p.db.ExecContext(ctx, `UPDATE code_host_links
+		SET synced_reviewers = $3,
+			synced_at = COALESCE($4, synced_at),
+			sync_status = CASE WHEN version = $2 THEN $5 ELSE sync_status END,
+			attempts = CASE WHEN version = $2 THEN $6 ELSE attempts END,
+			next_attempt_at = CASE WHEN version = $2 THEN $7 ELSE next_attempt_at END,
+			last_error = CASE WHEN version = $2 THEN $8 ELSE last_error END
+		WHERE pull_request_id = $1`, l.PrID, l.Version, pq.Array(l.SyncedReviewers), l.SyncedAt,
+		l.Status, l.Attempts, l.NextAttemptAt, l.LastError)
+	if err != nil {
+		return fmt.Errorf("error updating reviewer sync of %s: %w", l.PrID, err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrPrNotFound
+	}
+	return nil
+}
+
+// GetCodeHostLogins возвращает логины пользователей у провайдера (user_id -> login),
+// пользователи без связи в ответ не попадают
+func (p *PostgresRepo) GetCodeHostLogins(ctx context.Context, provider entityHost.Provider, userIDs []string) (map[string]string, error) {
+	res := make(map[string]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+	var rows []dto.CodeHostUserDto
+	if err := p.db.SelectContext(ctx, &rows, `SELECT DISTINCT ON (user_id) provider, login, user_id FROM code_host_users
+		WHERE provider = $1 AND user_id = ANY($2)
+		ORDER BY user_id, created_at DESC`, provider, pq.Array(userIDs)); err != nil {
+		return nil, fmt.Errorf("error getting %s logins: %w", provider, err)
+	}
+	for _, row := range rows {
+		res[row.UserID] = row.Login
+	}
+	return res, nil
+}
+
+func codeHostLinkFromDto(row dto.CodeHostLinkDto) entityHost.Link {
+	return entityHost.Link{
+		PrID:            row.PrID,
+		Provider:        entityHost.Provider(row.Provider),
+		RepoID:          row.RepoID,
+		Repo:            row.Repo,
+		Number:          row.Number,
+		Status:          entityHost.SyncStatus(row.Status),
+		Version:         row.Version,
+		Attempts:        row.Attempts,
+		NextAttemptAt:   row.NextAttemptAt,
+		LastError:       row.LastError,
+		SyncedReviewers: []string(row.SyncedReviewers),
+		SyncedAt:        row.SyncedAt,
+	}
+}
+
+// GetSLABreaches возвращает назначенных ревьюеров открытых PR, которые не оставили ни одного ревью
+// за срок команды автора, от давних назначений к новым. teamIDs == nil - все команды с SLA
+func (p *PostgresRepo) GetSLABreaches(ctx context.Context, now time.Time, teamIDs []int) ([]entityPr.SLABreach, error) {
+	var sb strings.Builder
+	sb.WriteString(`
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, t.id AS team_id, t.team_name,
+			r.reviewer_id, r.assigned_at, t.sla_review_hours, t.sla_escalate_hours, t.sla_action,
+			n.reminded_at, n.escalated_at
+		FROM pull_requests pr
+		JOIN users a ON a.user_id = pr.author_id
+		JOIN teams t ON t.id = a.team_id
+		JOIN pull_request_reviewers r ON r.pull_request_id = pr.pull_request_id
+		LEFT JOIN review_sla_notices n
+			ON n.pull_request_id = r.pull_request_id AND n.reviewer_id = r.reviewer_id AND n.assigned_at = r.assigned_at
+		WHERE pr.status = 'OPEN'
+			AND t.sla_review_hours > 0
+			AND r.assigned_at + make_interval(hours => t.sla_review_hours) <= $1
+			AND NOT EXISTS (
+				SELECT 1 FROM pull_request_reviews v
+				WHERE v.pull_request_id = r.pull_request_id AND v.reviewer_id = r.reviewer_id AND v.created_at >= r.assigned_at
+			)`)
+	args := []any{now}
+	if teamIDs != nil {
+		ids := make([]int64, 0, len(teamIDs))
+		for _, id := range teamIDs {
+			ids = append(ids, int64(id))
+		}
+		sb.WriteString(" AND t.id = ANY($2)")
+		args = append(args, pq.Array(ids))
+	}
+	sb.WriteString(" ORDER BY r.assigned_at, pr.pull_request_id, r.reviewer_id")
+	var rows []dto.SLABreachDto
+	if err := p.db.SelectContext(ctx, &rows, sb.String(), args...); err != nil {
+		return nil, fmt.Errorf("error getting SLA breaches: %w", err)
+	}
+	res := make([]entityPr.SLABreach, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, entityPr.SLABreach{
+			PrID:       row.PrID,
+			PrName:     row.PrName,
+			AuthorID:   row.AuthorID,
+			TeamID:     row.TeamID,
+			TeamName:   row.TeamName,
+			ReviewerID: row.ReviewerID,
+			AssignedAt: row.AssignedAt,
+			SLA: entityTeam.SLA{
+				ReviewHours:   row.SLAReviewHours,
+				EscalateHours: row.SLAEscalateHours,
+				Action:        row.SLAAction,
+			},
+			RemindedAt:  row.RemindedAt,
+			EscalatedAt: row.EscalatedAt,
+		})
+	}
+	return res, nil
+}
+
+// MarkReviewOverdue отмечает стадию просрочки ревью и пишет pr.review_overdue в outbox.
+// false - стадия уже отмечена (например, другой репликой) или ревьюера с тем же assigned_at на PR больше нет
+func (p *PostgresRepo) MarkReviewOverdue(ctx context.Context, b entityPr.SLABreach, stage string, leads []string, now time.Time) (bool, error) {
+	column := "reminded_at"
+	if stage == entityEvent.StageEscalation {
+		column = "escalated_at"
+	}
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `INSERT INTO review_sla_notices (pull_request_id, reviewer_id, assigned_at, `+column+`)
+		SELECT r.pull_request_id, r.reviewer_id, r.assigned_at, $4
+		FROM pull_request_reviewers r
+		WHERE r.pull_request_id = $1 AND r.reviewer_id = $2 AND r.assigned_at = $3
+		ON CONFLICT (pull_request_id, reviewer_id, assigned_at) DO UPDATE
+		SET `+column+` = EXCLUDED.`+column+`
+		WHERE review_sla_notices.`+column+` IS NULL`, b.PrID, b.ReviewerID, b.AssignedAt, now)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, fmt.Errorf("error marking review overdue: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		_ = tx.Rollback()
+		return false, nil
+	}
+	pr, err := lockPrTx(ctx, tx, b.PrID)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+	if err := addOutboxTx(ctx, tx, entityEvent.NewReviewOverdue(*pr, b, stage, leads)); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetTeamLeads возвращает тимлидов команды
+func (p *PostgresRepo) GetTeamLeads(ctx context.Context, teamID int) ([]string, error) {
+	var leads []string
+	if err := p.db.SelectContext(ctx, &leads, `SELECT user_id FROM user_roles
+		WHERE role = 'team_lead' AND team_id = $1
+		ORDER BY user_id`, teamID); err != nil {
+		return nil, fmt.Errorf("error getting leads of team %d: %w", teamID, err)
+	}
+	return leads, nil
+}
+
+// GetStaleReviews возвращает назначенных ревьюеров открытых PR, которые не оставили ни одного ревью
+// за stale_review_hours команды автора, от давних назначений к новым
+func (p *PostgresRepo) GetStaleReviews(ctx context.Context, now time.Time) ([]entityPr.StaleReview, error) {
+	var rows []dto.StaleReviewDto
+	if err := p.db.SelectContext(ctx, &rows, `
+		SELECT pr.pull_request_id, t.id AS team_id, r.reviewer_id, r.assigned_at
+		FROM pull_requests pr
+		JOIN users a ON a.user_id = pr.author_id
+		JOIN teams t ON t.id = a.team_id
+		JOIN pull_request_reviewers r ON r.pull_request_id = pr.pull_request_id
+		WHERE pr.status = 'OPEN'
+			AND t.stale_review_hours > 0
+			AND r.assigned_at + make_interval(hours => t.stale_review_hours) <= $1
+			AND NOT EXISTS (
+				SELECT 1 FROM pull_request_reviews v
+				WHERE v.pull_request_id = r.pull_request_id AND v.reviewer_id = r.reviewer_id AND v.created_at >= r.assigned_at
+			)
+		ORDER BY r.assigned_at, pr.pull_request_id, r.reviewer_id`, now); err != nil {
+		return nil, fmt.Errorf("error getting stale reviews: %w", err)
+	}
+	res := make([]entityPr.StaleReview, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, entityPr.StaleReview{
+			PrID:       row.PrID,
+			TeamID:     row.TeamID,
+			ReviewerID: row.ReviewerID,
+			AssignedAt: row.AssignedAt,
+		})
+	}
+	return res, nil
+}
+
+// RunExclusive выполняет fn, только если advisory lock lockID не занят другой репликой, и держит его до конца fn.
+// false - lock занят, fn не выполнялась
+func (p *PostgresRepo) RunExclusive(ctx context.Context, lockID int64, fn func(context.Context) error) (bool, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var locked bool
+	if err := tx.GetContext(ctx, &locked, `SELECT pg_try_advisory_xact_lock($1)`, lockID); err != nil {
+		return false, fmt.Errorf("error taking advisory lock %d: %w", lockID, err)
+	}
+	if !locked {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+// RotateRoundRobin передаёт next последнего участника команды, выбранного по кругу, и сохраняет того,
+// кого вернул next. Строка команды блокируется на время вызова, поэтому очередь общая для всех реплик
+// и не сбрасывается при перезапуске
+func (p *PostgresRepo) RotateRoundRobin(ctx context.Context, teamID int, next func(last string) (string, error)) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var last string
+	if err := tx.GetContext(ctx, &last, `SELECT round_robin_last FROM teams WHERE id = $1 FOR UPDATE`, teamID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTeamNotFound
+		}
+		return fmt.Errorf("error getting round robin position of team %d: %w", teamID, err)
+	}
+	selected, err := next(last)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE teams SET round_robin_last = $2 WHERE id = $1`, teamID, selected); err != nil {
+		return fmt.Errorf("error saving round robin position of team %d: %w", teamID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// GetAssignments возвращает историю ревьюеров PR в порядке изменений
+func (p *PostgresRepo) GetAssignments(ctx context.Context, prID string) ([]entityPr.Assignment, error) {
+	var rows []dto.AssignmentDto
+	if err := p.db.SelectContext(ctx, &rows, `SELECT id, pull_request_id, reviewer_id, action, COALESCE(replaced_by, '') AS replaced_by,
+			reason, actor, created_at
+		FROM reviewer_assignments
+		WHERE pull_request_id = $1
+		ORDER BY id`, prID); err != nil {
+		return nil, fmt.Errorf("error getting reviewer history of PR %s: %w", prID, err)
+	}
+	res := make([]entityPr.Assignment, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, entityPr.Assignment{
+			Id:         row.Id,
+			PrID:       row.PrID,
+			ReviewerID: row.ReviewerID,
+			Action:     entityPr.AssignmentAction(row.Action),
+			ReplacedBy: row.ReplacedBy,
+			Reason:     row.Reason,
+			Actor:      row.Actor,
+			At:         row.CreatedAt,
+		})
+	}
+	return res, nil
+}
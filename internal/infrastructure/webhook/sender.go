@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
+)
+
+// defaultTimeout - сколько ждать ответа подписчика
+const defaultTimeout = 10 * time.Second
+
+// HTTPSender отправляет доставки POST запросом с JSON телом и HMAC подписью
+type HTTPSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewHTTPSender(client *http.Client) *HTTPSender {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &HTTPSender{client: client, now: time.Now}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, d entityHook.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PullRequestService-Webhooks")
+	req.Header.Set(entityHook.HeaderEvent, string(d.EventType))
+	req.Header.Set(entityHook.HeaderDelivery, d.EventID)
+	req.Header.Set(entityHook.HeaderSignature, entityHook.Sign(d.Secret, s.now(), d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
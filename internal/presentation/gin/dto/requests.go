@@ -1,20 +1,53 @@
 package dto
 
+import "time"
+
 type AddTeamRequest struct {
-	TeamName string      `json:"team_name"`
-	Members  []MemberDto `json:"members"`
+	TeamName          string      `json:"team_name"`
+	ReviewerStrategy  string      `json:"reviewer_strategy,omitempty"`
+	RequiredReviewers int         `json:"required_reviewers,omitempty"`
+	Members           []MemberDto `json:"members"`
+}
+
+type TeamSettingsRequest struct {
+	TeamName          string   `json:"team_name"`
+	ReviewerStrategy  string   `json:"reviewer_strategy,omitempty"`
+	RequiredReviewers int      `json:"required_reviewers,omitempty"`
+	RequiredApprovals int      `json:"required_approvals,omitempty"`
+	SLA               *TeamSLA `json:"sla,omitempty"`
+	// StaleReviewHours - через сколько часов ревьюер без ревью заменяется автоматически, 0 выключает замену
+	StaleReviewHours *int `json:"stale_review_hours,omitempty"`
+}
+
+// TeamSLA - сроки ревью команды в часах от назначения ревьюера, review_hours = 0 выключает SLA.
+// action - что делать после escalate_hours: escalate (по умолчанию) или reassign
+type TeamSLA struct {
+	ReviewHours   int    `json:"review_hours"`
+	EscalateHours int    `json:"escalate_hours,omitempty"`
+	Action        string `json:"action,omitempty"`
 }
 
 type MemberDto struct {
 	Id       string `json:"user_id"`
 	Name     string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	Email    string `json:"email,omitempty"`
 }
 
 type CreatePR struct {
-	PrID     string `json:"pull_request_id"`
-	PrName   string `json:"pull_request_name"`
-	PrAuthor string `json:"author_id"`
+	PrID         string      `json:"pull_request_id"`
+	PrName       string      `json:"pull_request_name"`
+	PrAuthor     string      `json:"author_id"`
+	ExtraTeams   []TeamQuota `json:"extra_teams,omitempty"`
+	ChangedFiles []string    `json:"changed_files,omitempty"`
+	RequiredTags []string    `json:"required_tags,omitempty"`
+	Draft        bool        `json:"draft,omitempty"`
+}
+
+// TeamQuota - сколько ревьюеров взять из дополнительной команды (по умолчанию 1)
+type TeamQuota struct {
+	TeamName  string `json:"team_name"`
+	Reviewers int    `json:"reviewers,omitempty"`
 }
 
 type SetUserActive struct {
@@ -22,6 +55,37 @@ type SetUserActive struct {
 	IsActive bool   `json:"is_active"`
 }
 
+// MaxOpenReviewsRequest - лимит одновременных открытых ревью пользователя, 0 снимает лимит
+type MaxOpenReviewsRequest struct {
+	UserId         string `json:"user_id"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+}
+
+// UserEmailRequest - адрес для писем пользователю, пустой адрес отключает письма
+type UserEmailRequest struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+// UserTagsRequest - полный список навыков пользователя, старые навыки заменяются
+type UserTagsRequest struct {
+	UserID string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+// AvailabilityRequest - окно недоступности пользователя (отпуск, дежурство)
+type AvailabilityRequest struct {
+	UserID string    `json:"user_id"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+type DeleteAvailabilityRequest struct {
+	UserID string `json:"user_id"`
+	Id     int    `json:"availability_id"`
+}
+
 type CreatePr struct {
 	Id     string `json:"pull_request_id"`
 	Name   string `json:"pull_request_name"`
@@ -32,12 +96,89 @@ type MergeRequest struct {
 	Id string `json:"pull_request_id"`
 }
 
+type ClosePullRequest struct {
+	Id string `json:"pull_request_id"`
+}
+
+type PullRequestLifecycleRequest struct {
+	PrID         string      `json:"pull_request_id"`
+	ExtraTeams   []TeamQuota `json:"extra_teams,omitempty"`
+	ChangedFiles []string    `json:"changed_files,omitempty"`
+}
+
+// RoleRequest - выдача или отзыв роли: admin - на всю организацию, team_lead - в команде team_name
+type RoleRequest struct {
+	UserID   string `json:"user_id"`
+	Role     string `json:"role"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+// IssueAPIKeyRequest - выпуск API ключа для бота или CI, expires_at необязателен
+type IssueAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type RevokeAPIKeyRequest struct {
+	Id int `json:"api_key_id"`
+}
+
+// WebhookRequest - подписка на события команды team_name (или всех команд, если не указана),
+// пустой events - все события
+type WebhookRequest struct {
+	URL      string   `json:"url"`
+	Secret   string   `json:"secret"`
+	TeamName string   `json:"team_name,omitempty"`
+	Events   []string `json:"events,omitempty"`
+}
+
+type DeleteWebhookRequest struct {
+	Id int `json:"webhook_id"`
+}
+
+type RedeliverRequest struct {
+	Id int `json:"delivery_id"`
+}
+
+type ReviewRequest struct {
+	PrID    string `json:"pull_request_id"`
+	Verdict string `json:"verdict"`
+	Body    string `json:"body,omitempty"`
+}
+
 type ReassignPullRequest struct {
-	PrID        string `json:"pull_request_id"`
-	OldReviewer string `json:"old_reviewer_id"`
+	PrID               string `json:"pull_request_id"`
+	OldReviewer        string `json:"old_reviewer_id"`
+	PreferredCandidate string `json:"preferred_candidate_id,omitempty"`
 }
 
 type DeactivationRequest struct {
 	TeamName string   `json:"team_name"`
 	UserIDs  []string `json:"user_ids"`
 }
+
+// UploadCodeOwnersRequest - содержимое файла в формате CODEOWNERS
+type UploadCodeOwnersRequest struct {
+	TeamName string `json:"team_name"`
+	Content  string `json:"content"`
+}
+
+type TestCodeOwnersRequest struct {
+	TeamName string   `json:"team_name"`
+	Paths    []string `json:"paths"`
+}
+
+// CodeHostUserRequest - связь логина на GitHub/GitLab с пользователем сервиса, при удалении user_id не нужен
+type CodeHostUserRequest struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id,omitempty"`
+}
+
+// EventStreamRequest - фильтры потока событий: команда, пользователь и типы событий
+type EventStreamRequest struct {
+	TeamName string
+	UserID   string
+	Types    []string
+}
@@ -7,8 +7,13 @@ type TeamResponse struct {
 }
 
 type TeamDtoResponse struct {
-	TeamName string              `json:"team_name"`
-	Members  []MemberDtoResponse `json:"members"`
+	TeamName          string              `json:"team_name"`
+	ReviewerStrategy  string              `json:"reviewer_strategy,omitempty"`
+	RequiredReviewers int                 `json:"required_reviewers,omitempty"`
+	RequiredApprovals int                 `json:"required_approvals,omitempty"`
+	SLA               *TeamSLA            `json:"sla,omitempty"`
+	StaleReviewHours  int                 `json:"stale_review_hours,omitempty"`
+	Members           []MemberDtoResponse `json:"members"`
 }
 
 type ErrorResponse struct {
@@ -24,6 +29,17 @@ type MemberDtoResponse struct {
 	Id       string `json:"user_id"`
 	Name     string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	Email    string `json:"email,omitempty"`
+}
+
+type MaxOpenReviewsResponse struct {
+	UserId         string `json:"user_id"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+}
+
+type UserEmailResponse struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
 }
 
 type UserResponse struct {
@@ -37,16 +53,63 @@ type UserWithTeam struct {
 	Team     string `json:"team_name"`
 }
 
+type UserTagsResponse struct {
+	UserId string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+type AvailabilityResponse struct {
+	UserId  string               `json:"user_id"`
+	Windows []AvailabilityWindow `json:"windows"`
+}
+
+type AvailabilityWindow struct {
+	Id     int       `json:"availability_id"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Reason string    `json:"reason,omitempty"`
+}
+
 type PullRequestResponse struct {
 	Pr PullRequest `json:"pr"`
 }
 
 type PullRequest struct {
-	Id        string   `json:"pull_request_id"`
-	Name      string   `json:"pull_request_name"`
-	AuthorId  string   `json:"author_id"`
-	Status    string   `json:"status"`
-	Reviewers []string `json:"assigned_reviewers"`
+	Id                string   `json:"pull_request_id"`
+	Name              string   `json:"pull_request_name"`
+	AuthorId          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	Reviewers         []string `json:"assigned_reviewers"`
+	NeedMoreReviewers bool     `json:"need_more_reviewers"`
+	NeedMoreReason    string   `json:"need_more_reason,omitempty"`
+	// CodeHost - PR у провайдера и перенос ревьюеров туда, только для PR из вебхуков GitHub/GitLab
+	CodeHost *CodeHostSync `json:"code_host,omitempty"`
+}
+
+// PullRequestHistoryResponse - история ревьюеров PR от первых изменений к последним
+type PullRequestHistoryResponse struct {
+	PrId    string               `json:"pull_request_id"`
+	History []ReviewerAssignment `json:"history"`
+}
+
+// ReviewerAssignment - изменение ревьюера PR. action: assigned, removed или replaced (вместо ревьюера назначен replaced_by)
+type ReviewerAssignment struct {
+	ReviewerId string    `json:"reviewer_id"`
+	Action     string    `json:"action"`
+	ReplacedBy string    `json:"replaced_by,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Actor      string    `json:"actor"`
+	At         time.Time `json:"at"`
+}
+
+type CodeHostSync struct {
+	Provider   string     `json:"provider"`
+	Repo       string     `json:"repo,omitempty"`
+	Number     int        `json:"number"`
+	SyncStatus string     `json:"sync_status"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error,omitempty"`
+	SyncedAt   *time.Time `json:"synced_at,omitempty"`
 }
 
 type UsersPrResponse struct {
@@ -72,6 +135,22 @@ type MergedPullRequestOfUser struct {
 	Status    string     `json:"status"`
 	Reviewers []string   `json:"assigned_reviewers"`
 	MergeAt   *time.Time `json:"merged_at,omitempty"`
+	MergedBy  string     `json:"merged_by,omitempty"`
+	// ReviewBypassed - администратор смержил PR без нужных одобрений
+	ReviewBypassed bool `json:"review_bypassed"`
+}
+
+type ReviewResponse struct {
+	Review PullRequestReview `json:"review"`
+}
+
+type PullRequestReview struct {
+	Id         int       `json:"review_id"`
+	PrID       string    `json:"pull_request_id"`
+	ReviewerID string    `json:"reviewer_id"`
+	Verdict    string    `json:"verdict"`
+	Body       string    `json:"body,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type ReassignResponse struct {
@@ -85,9 +164,124 @@ type ReassignPR struct {
 	AuthorID          string   `json:"author_id"`
 	Status            string   `json:"status"`
 	AssignedReviewers []string `json:"assigned_reviewers"`
+	NeedMoreReviewers bool     `json:"need_more_reviewers"`
+	NeedMoreReason    string   `json:"need_more_reason,omitempty"`
 }
 
 type StatsResponse struct {
 	ByUser map[string]int `json:"by_user"`
 	ByPR   map[string]int `json:"by_pr"`
 }
+
+type CodeOwnerRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type CodeOwnersResponse struct {
+	TeamName string          `json:"team_name"`
+	Rules    []CodeOwnerRule `json:"rules"`
+}
+
+type CodeOwnersTestResponse struct {
+	TeamName string              `json:"team_name"`
+	Owners   map[string][]string `json:"owners"`
+}
+
+type RoleDto struct {
+	Role     string `json:"role"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+type UserRolesResponse struct {
+	UserId string    `json:"user_id"`
+	Roles  []RoleDto `json:"roles"`
+}
+
+type APIKeyDto struct {
+	Id         int        `json:"api_key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IssueAPIKeyResponse - ключ возвращается только при выпуске, сервис хранит лишь его хеш
+type IssueAPIKeyResponse struct {
+	Key    string    `json:"key"`
+	APIKey APIKeyDto `json:"api_key"`
+}
+
+type APIKeysResponse struct {
+	APIKeys []APIKeyDto `json:"api_keys"`
+}
+
+// WebhookDto - подписка без секрета
+type WebhookDto struct {
+	Id        int       `json:"webhook_id"`
+	URL       string    `json:"url"`
+	TeamName  string    `json:"team_name,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhooksResponse struct {
+	Webhooks []WebhookDto `json:"webhooks"`
+}
+
+type DeliveryDto struct {
+	Id            int        `json:"delivery_id"`
+	WebhookID     int        `json:"webhook_id"`
+	EventID       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	ResponseCode  int        `json:"response_code,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+type DeliveriesResponse struct {
+	Deliveries []DeliveryDto `json:"deliveries"`
+}
+
+type CodeHostUserDto struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id"`
+}
+
+type CodeHostUsersResponse struct {
+	Users []CodeHostUserDto `json:"users"`
+}
+
+// CodeHostWebhookResponse - результат обработки вебхука: processed, duplicate или ignored
+type CodeHostWebhookResponse struct {
+	Status string       `json:"status"`
+	Pr     *PullRequest `json:"pr,omitempty"`
+}
+
+// SLABreachDto - ревьюер, не оставивший ревью в срок. overdue_hours - сколько часов прошло после срока,
+// reminded_at и escalated_at - когда ревьюеру напомнили и когда сообщили тимлидам
+type SLABreachDto struct {
+	PrID         string     `json:"pull_request_id"`
+	PrName       string     `json:"pull_request_name"`
+	AuthorID     string     `json:"author_id"`
+	TeamName     string     `json:"team_name"`
+	ReviewerID   string     `json:"reviewer_id"`
+	AssignedAt   time.Time  `json:"assigned_at"`
+	Deadline     time.Time  `json:"deadline"`
+	OverdueHours int        `json:"overdue_hours"`
+	RemindedAt   *time.Time `json:"reminded_at,omitempty"`
+	EscalatedAt  *time.Time `json:"escalated_at,omitempty"`
+}
+
+type SLABreachesResponse struct {
+	Breaches []SLABreachDto `json:"breaches"`
+}
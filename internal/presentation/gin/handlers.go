@@ -2,11 +2,24 @@ package rest
 
 import (
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
+	entityOwners "github.com/JanArsMAI/PullRequestService/internal/domain/codeowners"
+	entityEvent "github.com/JanArsMAI/PullRequestService/internal/domain/event"
 	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
+	entityPr "github.com/JanArsMAI/PullRequestService/internal/domain/pullrequest"
+	entityTeam "github.com/JanArsMAI/PullRequestService/internal/domain/team"
+	entityUser "github.com/JanArsMAI/PullRequestService/internal/domain/user"
+	entityHook "github.com/JanArsMAI/PullRequestService/internal/domain/webhook"
 	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -19,36 +32,53 @@ import (
 // @schemes http https
 
 type Handlers struct {
-	svc    interfaces.PrService
-	logger *zap.Logger
+	svc       interfaces.PrService
+	verifier  interfaces.TokenVerifier
+	receivers map[entityHost.Provider]interfaces.CodeHostReceiver
+	stream    interfaces.EventStream
+	logger    *zap.Logger
 }
 
-func NewHandlers(service interfaces.PrService, logger *zap.Logger) *Handlers {
+func NewHandlers(service interfaces.PrService, verifier interfaces.TokenVerifier, receivers map[entityHost.Provider]interfaces.CodeHostReceiver, stream interfaces.EventStream, logger *zap.Logger) *Handlers {
 	return &Handlers{
-		svc:    service,
-		logger: logger,
+		svc:       service,
+		verifier:  verifier,
+		receivers: receivers,
+		stream:    stream,
+		logger:    logger,
 	}
 }
 
 const (
-	CodeBadRequest   = "BAD_REQUEST"
-	CodeNotFound     = "NOT_FOUND"
-	CodeUnauthorized = "UNAUTHORIZED"
-	CodeForbidden    = "FORBIDDEN"
-	CodePrExists     = "PR_EXISTS"
-	CodeNoCandidate  = "NO_CANDIDATE"
-	CodeNotAssigned  = "NOT_ASSIGNED"
+	CodeBadRequest        = "BAD_REQUEST"
+	CodeNotFound          = "NOT_FOUND"
+	CodeUnauthorized      = "UNAUTHORIZED"
+	CodeTokenExpired      = "TOKEN_EXPIRED"
+	CodeForbidden         = "FORBIDDEN"
+	CodePrExists          = "PR_EXISTS"
+	CodeNoCandidate       = "NO_CANDIDATE"
+	CodeNotAssigned       = "NOT_ASSIGNED"
+	CodeInvalidCandidate  = "INVALID_CANDIDATE"
+	CodeInvalidStatus     = "INVALID_TRANSITION"
+	CodePrNotOpen         = "PR_NOT_OPEN"
+	CodeNoApprovals       = "NOT_ENOUGH_APPROVALS"
+	CodeChangesRequested  = "CHANGES_REQUESTED"
+	CodeDeliveryNotFailed = "DELIVERY_NOT_FAILED"
+	CodeNotMapped         = "USER_NOT_MAPPED"
 )
 
 // AddTeam godoc
 // @Summary Создание новой команды
-// @Description Создаёт новую команду. Если пользователь уже в другой команде, PR пользователя переназначается на участников старой команды.
+// @Description Создаёт новую команду. Если пользователь уже в другой команде, PR пользователя переназначается на участников старой команды. Доступно только администраторам.
 // @Tags team
 // @Accept json
 // @Produce json
+// @Param Authorization header string true "Bearer JWT администратора"
 // @Param team body dto.AddTeamRequest true "Данные команды"
 // @Success 201 {object} dto.TeamResponse "Команда создана"
 // @Failure 400 {object} dto.ErrorResponse "Команда уже существует или некоректный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Вызывающий не администратор"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /team/add [post]
 func (h *Handlers) AddTeam(ctx *gin.Context) {
@@ -86,6 +116,9 @@ func (h *Handlers) AddTeam(ctx *gin.Context) {
 	}
 	err = h.svc.AddTeam(ctx, &body)
 	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
 		if errors.Is(err, application.ErrTeamWithNameAlreadyCreated) {
 			h.logger.Warn("invalid name to Add Team, error parsing JSON", zap.Error(err), zap.String("name", body.TeamName))
 			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -96,6 +129,36 @@ func (h *Handlers) AddTeam(ctx *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, application.ErrInvalidReviewerStrategy) {
+			h.logger.Warn("invalid reviewer strategy to Add Team", zap.String("reviewer_strategy", body.ReviewerStrategy))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "unknown reviewer_strategy",
+				},
+			})
+			return
+		}
+		if errors.Is(err, application.ErrInvalidRequiredReviewers) {
+			h.logger.Warn("invalid required reviewers to Add Team", zap.Int("required_reviewers", body.RequiredReviewers))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "required_reviewers must be positive",
+				},
+			})
+			return
+		}
+		if errors.Is(err, application.ErrInvalidEmail) {
+			h.logger.Warn("invalid member email to Add Team", zap.String("team_name", body.TeamName))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "invalid member email",
+				},
+			})
+			return
+		}
 		h.logger.Error("error to Add team", zap.Error(err))
 		ctx.AbortWithStatus(http.StatusInternalServerError)
 		return
@@ -104,10 +167,20 @@ func (h *Handlers) AddTeam(ctx *gin.Context) {
 	for _, user := range body.Members {
 		members = append(members, dto.MemberDtoResponse(user))
 	}
+	strategy := body.ReviewerStrategy
+	if strategy == "" {
+		strategy = entityTeam.StrategyRandom
+	}
+	required := body.RequiredReviewers
+	if required == 0 {
+		required = entityTeam.DefaultRequiredReviewers
+	}
 	resp := dto.TeamResponse{
 		Team: dto.TeamDtoResponse{
-			TeamName: body.TeamName,
-			Members:  members,
+			TeamName:          body.TeamName,
+			ReviewerStrategy:  strategy,
+			RequiredReviewers: required,
+			Members:           members,
 		},
 	}
 	h.logger.Info("successfully added team", zap.String("team_name", body.TeamName))
@@ -120,7 +193,7 @@ func (h *Handlers) AddTeam(ctx *gin.Context) {
 // @Tags team
 // @Accept json
 // @Produce json
-// @Param Authorization header string true "токен пользователя(Вводить без Bearer)"
+// @Param Authorization header string true "Bearer JWT пользователя"
 // @Param team_name query string true "Уникальное имя команды"
 // @Success 200 {object} dto.TeamResponse "Обьект команды"
 // @Failure 400 {object} dto.ErrorResponse "Пустое имя команды"
@@ -130,10 +203,7 @@ func (h *Handlers) AddTeam(ctx *gin.Context) {
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /team/get [get]
 func (h *Handlers) GetTeam(ctx *gin.Context) {
-	//сымитируем проверку токена пользователя: токен - это idпользователя, для упрощения
-	//реализации было сделано так
-	userId, ok := ctx.Get("User_Id")
-	if !ok {
+	if _, ok := principal(ctx); !ok {
 		h.logger.Warn("unauthorized user to get Team")
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
@@ -168,54 +238,178 @@ func (h *Handlers) GetTeam(ctx *gin.Context) {
 			h.logger.Warn("Not found team", zap.String("target_name", par))
 			return
 		}
+		if errors.Is(err, application.ErrForbidden) {
+			h.logger.Warn("Forbidden access for user to get team", zap.String("team", par))
+			ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeForbidden,
+					Message: "User is not in found team",
+				},
+			})
+			return
+		}
 		ctx.AbortWithStatus(http.StatusInternalServerError)
 		h.logger.Error("error wthile getting team", zap.Error(err), zap.String("target_name", par))
 		return
 	}
-	var isInTeam = false
 	members := make([]dto.MemberDtoResponse, 0, len(team.Users))
 	for _, user := range team.Users {
 		members = append(members, dto.MemberDtoResponse{
 			Id:       user.Id,
 			Name:     user.Name,
 			IsActive: user.IsActive,
+			Email:    user.Email,
 		})
-		if user.Id == userId.(string) || userId.(string) == "admin" {
-			isInTeam = true
-		}
 	}
-	if !isInTeam {
-		h.logger.Warn("Forbidden access for user to get team", zap.String("team", team.Name), zap.String("user_id", userId.(string)))
-		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+
+	resp := dto.TeamResponse{
+		Team: dto.TeamDtoResponse{
+			TeamName:          team.Name,
+			ReviewerStrategy:  team.ReviewerStrategy,
+			RequiredReviewers: team.ReviewersNeeded(),
+			RequiredApprovals: team.ApprovalsNeeded(),
+			SLA:               teamSLA(team),
+			StaleReviewHours:  team.StaleReviewHours,
+			Members:           members,
+		},
+	}
+	ctx.JSON(http.StatusOK, resp)
+	h.logger.Info("successfully got team", zap.String("team_name", team.Name))
+}
+
+// UpdateTeamSettings godoc
+// @Summary Изменение настроек команды
+// @Description Изменяет стратегию выбора ревьюеров команды (random, round_robin, least_loaded, weighted), требуемое число ревьюеров на PR, число одобрений, нужных для мержа, сроки ревью (sla) и автоматическую замену ревьюеров без ревью (stale_review_hours). Доступно администраторам и тимлидам команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.TeamSettingsRequest true "Новые настройки команды"
+// @Success 200 {object} dto.TeamResponse "Обновлённая команда"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный запрос или неизвестная стратегия"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /team/settings [post]
+func (h *Handlers) UpdateTeamSettings(ctx *gin.Context) {
+	var body dto.TeamSettingsRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Warn("invalid format of request to update team settings", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to update team settings",
+			},
+		})
+		return
+	}
+	if body.TeamName == "" {
+		h.logger.Warn("no team_name provided in UpdateTeamSettings")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
-				Code:    CodeForbidden,
-				Message: "User is not in found team",
+				Code:    CodeBadRequest,
+				Message: "No chosen team for updating settings",
 			},
 		})
 		return
 	}
-
+	team, err := h.svc.UpdateTeamSettings(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrTeamNotFound):
+			h.logger.Warn("Not found team to update settings", zap.String("team_name", body.TeamName))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidReviewerStrategy):
+			h.logger.Warn("invalid reviewer strategy", zap.String("reviewer_strategy", body.ReviewerStrategy))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "unknown reviewer_strategy",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidRequiredReviewers):
+			h.logger.Warn("invalid required reviewers", zap.Int("required_reviewers", body.RequiredReviewers))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "required_reviewers must be positive",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidRequiredApprovals):
+			h.logger.Warn("invalid required approvals", zap.Int("required_approvals", body.RequiredApprovals))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "required_approvals must be positive",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidSLA):
+			h.logger.Warn("invalid review SLA", zap.String("team_name", body.TeamName))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "sla hours must not be negative, escalate_hours must be greater than review_hours, action must be escalate or reassign",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidStaleReviewHours):
+			h.logger.Warn("invalid stale review hours", zap.String("team_name", body.TeamName))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "stale_review_hours must not be negative",
+				},
+			})
+		default:
+			h.logger.Error("failed to update team settings", zap.Error(err), zap.String("team_name", body.TeamName))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+	members := make([]dto.MemberDtoResponse, 0, len(team.Users))
+	for _, user := range team.Users {
+		members = append(members, dto.MemberDtoResponse{
+			Id:       user.Id,
+			Name:     user.Name,
+			IsActive: user.IsActive,
+			Email:    user.Email,
+		})
+	}
 	resp := dto.TeamResponse{
 		Team: dto.TeamDtoResponse{
-			TeamName: team.Name,
-			Members:  members,
+			TeamName:          team.Name,
+			ReviewerStrategy:  team.ReviewerStrategy,
+			RequiredReviewers: team.ReviewersNeeded(),
+			RequiredApprovals: team.ApprovalsNeeded(),
+			SLA:               teamSLA(team),
+			StaleReviewHours:  team.StaleReviewHours,
+			Members:           members,
 		},
 	}
 	ctx.JSON(http.StatusOK, resp)
-	h.logger.Info("successfully got team", zap.String("team_name", team.Name))
+	h.logger.Info("successfully updated team settings", zap.String("team_name", team.Name))
 }
 
 // SetIsActive godoc
 // @Summary Установить флаг активности пользователя
-// @Description Позволяет изменить статус активности пользователя (активен/неактивен). Доступно только администраторам.
+// @Description Позволяет изменить статус активности пользователя (активен/неактивен). Доступно администраторам и тимлидам команды.
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param Authorization header string true "токен администратора(Вводить без Bearer)"
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
 // @Param user body dto.SetUserActive true "Данные пользователя для изменения статуса активности"
 // @Success 200 {object} dto.UserResponse "Обновлённый пользователь"
 // @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
-// @Failure 401 {object} dto.ErrorResponse "Нет/неверный админский токен"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
 // @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /users/setIsActive [post]
@@ -244,6 +438,9 @@ func (h *Handlers) SetIsActive(ctx *gin.Context) {
 	}
 	err = h.svc.SetUserActive(ctx, body.UserId, body.IsActive)
 	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
 		if errors.Is(err, application.ErrUserNotFound) {
 			h.logger.Warn("user not found while SetUserActive", zap.String("user_id", body.UserId))
 			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
@@ -261,6 +458,9 @@ func (h *Handlers) SetIsActive(ctx *gin.Context) {
 	}
 	user, team, err := h.svc.GetUserWithTeam(ctx, body.UserId)
 	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
 		h.logger.Error("failed to SetUserActive", zap.Error(err), zap.String("user_id", body.UserId))
 		ctx.AbortWithStatus(http.StatusInternalServerError)
 		return
@@ -277,469 +477,2495 @@ func (h *Handlers) SetIsActive(ctx *gin.Context) {
 	h.logger.Info("Successfully updated user", zap.String("user_id", user.Id))
 }
 
-// CreatePR godoc
-// @Summary      Создать Pull Request
-// @Description  Создаёт новый Pull Request от указанного автора.
-// @Description  Ревьюеры выбираются автоматически на основе команды автора.
-// @Tags         PullRequests
-// @Accept       json
-// @Produce      json
-// @Param Authorization header string true "токен администратора(Вводить без Bearer)"
-// @Param        body   body      dto.CreatePR  true  "Данные для создания Pull Request"
-// @Success      201    {object}  dto.PullRequestResponse "PR успешно создан"
-// @Failure      400    {object}  dto.ErrorResponse "Некорректный формат запроса"
-// @Failure      404    {object}  dto.ErrorResponse "Автор или команда не найдены"
-// @Failure      409    {object}  dto.ErrorResponse "PR с таким ID уже существует"
-// @Failure      500    "Внутренняя ошибка сервера"
-// @Router       /pullRequest/create [post]
-func (h *Handlers) CreatePR(ctx *gin.Context) {
-	var body dto.CreatePR
-	err := ctx.ShouldBindJSON(&body)
-	if err != nil {
-		h.logger.Warn("invalid format of request to Create PR, error parsing JSON", zap.Error(err))
+// SetMaxOpenReviews godoc
+// @Summary Установить лимит открытых ревью пользователя
+// @Description Задаёт, сколько открытых PR пользователь может ревьюить одновременно. Кандидаты, достигшие лимита, пропускаются при создании PR, переназначении и активации, 0 снимает лимит. Доступно администраторам и тимлидам команды.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.MaxOpenReviewsRequest true "Пользователь и лимит"
+// @Success 200 {object} dto.MaxOpenReviewsResponse "Новый лимит"
+// @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/setMaxOpenReviews [post]
+func (h *Handlers) SetMaxOpenReviews(ctx *gin.Context) {
+	var body dto.MaxOpenReviewsRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.UserId == "" {
+		h.logger.Warn("invalid format of request to set max open reviews", zap.Error(err))
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "invalid format of request to Create PR, error parsing JSON",
+				Message: "invalid format of request to set max open reviews",
 			},
 		})
 		return
 	}
-	if body.PrAuthor == "" {
-		h.logger.Warn("no author_id provided in CreatePR")
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error: dto.ErrorMessage{
-				Code:    CodeBadRequest,
-				Message: "No chosen author for creating PR",
-			},
-		})
+	user, err := h.svc.SetMaxOpenReviews(ctx, body.UserId, body.MaxOpenReviews)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrUserNotFound):
+			h.logger.Warn("user not found while SetMaxOpenReviews", zap.String("user_id", body.UserId))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "user not found",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidMaxOpenReviews):
+			h.logger.Warn("invalid max open reviews", zap.Int("max_open_reviews", body.MaxOpenReviews))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "max_open_reviews must not be negative",
+				},
+			})
+		default:
+			h.logger.Error("failed to SetMaxOpenReviews", zap.Error(err), zap.String("user_id", body.UserId))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
 		return
 	}
-	if body.PrID == "" {
-		h.logger.Warn("no pr_id provided in CreatePR")
+	ctx.JSON(http.StatusOK, dto.MaxOpenReviewsResponse{UserId: user.Id, MaxOpenReviews: user.MaxOpenReviews})
+	h.logger.Info("Successfully updated max open reviews", zap.String("user_id", user.Id))
+}
+
+// SetUserEmail godoc
+// @Summary Установить email пользователя
+// @Description Задаёт адрес, на который приходят письма о назначении ревьюером и ежедневная сводка открытых ревью. Пустой email удаляет адрес. Доступно администраторам и тимлидам команды.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.UserEmailRequest true "Пользователь и адрес"
+// @Success 200 {object} dto.UserEmailResponse "Новый адрес"
+// @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/setEmail [post]
+func (h *Handlers) SetUserEmail(ctx *gin.Context) {
+	var body dto.UserEmailRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.UserID == "" {
+		h.logger.Warn("invalid format of request to set email", zap.Error(err))
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "No chosen PR id for creating PR",
+				Message: "invalid format of request to set email",
 			},
 		})
 		return
 	}
-	if body.PrName == "" {
-		h.logger.Warn("no pr_name provided in CreatePR")
+	user, err := h.svc.SetUserEmail(ctx, body.UserID, body.Email)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrUserNotFound):
+			h.logger.Warn("user not found while SetUserEmail", zap.String("user_id", body.UserID))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "user not found",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidEmail):
+			h.logger.Warn("invalid email", zap.String("user_id", body.UserID))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "invalid email",
+				},
+			})
+		default:
+			h.logger.Error("failed to SetUserEmail", zap.Error(err), zap.String("user_id", body.UserID))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.UserEmailResponse{UserID: user.Id, Email: user.Email})
+	h.logger.Info("Successfully updated email", zap.String("user_id", user.Id))
+}
+
+// SetUserTags godoc
+// @Summary Установить навыки пользователя
+// @Description Заменяет список навыков пользователя (go, sql, frontend и т.д.). По навыкам ревьюеры подбираются к PR с required_tags. Доступно администраторам и тимлидам команды.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.UserTagsRequest true "Пользователь и его навыки"
+// @Success 200 {object} dto.UserTagsResponse "Навыки пользователя"
+// @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/setTags [post]
+func (h *Handlers) SetUserTags(ctx *gin.Context) {
+	var body dto.UserTagsRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.UserID == "" {
+		h.logger.Warn("invalid format of request to set user tags", zap.Error(err))
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "No chosen PR name for creating PR",
+				Message: "invalid format of request to set user tags",
 			},
 		})
 		return
 	}
-	pr, err := h.svc.CreatePR(ctx, body)
+	user, err := h.svc.SetUserTags(ctx, body)
 	if err != nil {
-		if errors.Is(err, application.ErrPrIsAlreadyCreated) {
-			h.logger.Warn("Pr with this ID is already created", zap.String("Pr_id", body.PrID))
-			ctx.JSON(http.StatusConflict, dto.ErrorResponse{
-				Error: dto.ErrorMessage{
-					Code:    CodePrExists,
-					Message: "PR id already exists",
-				},
-			})
+		if h.denied(ctx, err) {
 			return
 		}
-		if errors.Is(err, application.ErrAuthorOrTeamAreNotFound) {
-			h.logger.Warn("Author or team of this PR not exist", zap.String("Pr_id", body.PrID),
-				zap.String("author_id", body.PrAuthor))
+		switch {
+		case errors.Is(err, application.ErrUserNotFound):
+			h.logger.Warn("user not found while SetUserTags", zap.String("user_id", body.UserID))
 			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
 					Code:    CodeNotFound,
-					Message: "resource not found",
+					Message: "user not found",
 				},
 			})
-			return
+		case errors.Is(err, application.ErrInvalidTags):
+			h.logger.Warn("invalid user tags", zap.Strings("tags", body.Tags))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "tags must be non-empty words",
+				},
+			})
+		default:
+			h.logger.Error("failed to SetUserTags", zap.Error(err), zap.String("user_id", body.UserID))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
 		}
-		h.logger.Error("failed to create PR", zap.Error(err))
-		ctx.AbortWithStatus(http.StatusInternalServerError)
 		return
 	}
-	reviewers := make([]string, 0, len(pr.Reviewers))
-	for _, reviewer := range pr.Reviewers {
-		reviewers = append(reviewers, reviewer.Id)
-	}
-	resp := dto.PullRequestResponse{
-		Pr: dto.PullRequest{
-			Id:        pr.Id,
-			Name:      pr.Name,
-			AuthorId:  pr.Author.Id,
-			Status:    pr.Status,
-			Reviewers: reviewers,
-		},
-	}
-	h.logger.Info("successfully created Pull Request", zap.String("author_id", pr.Author.Id), zap.String("Pr_id", pr.Id))
-	ctx.JSON(http.StatusCreated, resp)
+	ctx.JSON(http.StatusOK, dto.UserTagsResponse{UserId: user.Id, Tags: user.Tags})
+	h.logger.Info("Successfully updated user tags", zap.String("user_id", user.Id))
 }
 
-// GetUsersPr godoc
-// @Summary Получить PR'ы, где пользователь назначен ревьювером
-// @Description Получить PR'ы, где пользователь назначен ревьювером
+// GetUserTags godoc
+// @Summary Получить навыки пользователя
+// @Description Возвращает список навыков пользователя
 // @Tags Users
-// @Param Authorization header string true "токен пользователя (вводить без Bearer)"
+// @Param Authorization header string true "Bearer JWT пользователя"
 // @Param user_id query string true "Id пользователя"
-// @Success 200 {object} dto.UsersPrResponse "Список PR'ов пользователя"
+// @Success 200 {object} dto.UserTagsResponse "Навыки пользователя"
 // @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
 // @Failure 401 {object} dto.ErrorResponse "Пользователь не авторизован"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
 // @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /users/getReview [get]
-func (h *Handlers) GetUsersPr(ctx *gin.Context) {
-	_, ok := ctx.Get("User_Id")
-	if !ok {
-		h.logger.Warn("unauthorized user to get PR")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error: dto.ErrorMessage{
-				Code:    CodeUnauthorized,
-				Message: "No token to get info of PR",
-			},
-		})
-		return
-	}
+// @Router /users/getTags [get]
+func (h *Handlers) GetUserTags(ctx *gin.Context) {
 	par := ctx.Query("user_id")
 	if par == "" {
-		h.logger.Warn("GetUsersPr: empty user_id parameter")
+		h.logger.Warn("GetUserTags: empty user_id parameter")
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "No chosen user for getting PR",
+				Message: "No chosen user for getting tags",
 			},
 		})
 		return
 	}
-	prs, err := h.svc.GetUsersPr(ctx, par)
+	user, err := h.svc.GetUserTags(ctx, par)
 	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
 		if errors.Is(err, application.ErrUserNotFound) {
+			h.logger.Warn("Not found user", zap.String("target_name", par))
 			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
 					Code:    CodeNotFound,
 					Message: "resource not found",
 				},
 			})
-			h.logger.Warn("Not found user", zap.String("target_name", par))
 			return
 		}
-		h.logger.Error("failed to get user PRs", zap.Error(err))
+		h.logger.Error("failed to get user tags", zap.Error(err))
 		ctx.AbortWithStatus(http.StatusInternalServerError)
 		return
 	}
-	prResp := make([]dto.PullRequestOfUser, 0, len(prs))
-	for _, pr := range prs {
-		prResp = append(prResp, dto.PullRequestOfUser{
-			Id:       pr.Id,
-			Name:     pr.Name,
-			AuthorId: pr.Author.Id,
-			Status:   pr.Status,
-		})
-	}
-	resp := dto.UsersPrResponse{
-		UserId: par,
-		Prs:    prResp,
-	}
-	ctx.JSON(http.StatusOK, resp)
-	h.logger.Info("successfully got users pr", zap.String("user_id", par))
+	ctx.JSON(http.StatusOK, dto.UserTagsResponse{UserId: user.Id, Tags: user.Tags})
 }
 
-// Merge godoc
-// @Summary Пометить PR как MERGED
-// @Description Пометить PR как MERGED
-// @Tags PullRequests
-// @Param body body dto.MergeRequest true "Id PR"
-//
-//	@Param Authorization header string true "токен пользователя(Вводить без Bearer)"
-//	@Success 200 {object} dto.MergeResponse "PR в состоянии MERGED"
-//	@Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
-//
-// @Failure 401 {object} dto.ErrorResponse "Некорректный формат запроса"
-// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// AddAvailability godoc
+// @Summary Добавить окно недоступности пользователя
+// @Description Сохраняет период, в который пользователь не назначается ревьюером (отпуск, дежурство). Когда окно начинается, открытые PR пользователя переназначаются, а после окончания ему предлагаются PR с need_more_reviewers. Доступно администраторам и тимлидам команды.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.AvailabilityRequest true "Пользователь и период недоступности (RFC 3339)"
+// @Success 201 {object} dto.AvailabilityResponse "Созданное окно"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный период"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /pullRequest/merge [post]
-func (h *Handlers) Merge(ctx *gin.Context) {
-	var body dto.MergeRequest
-	if err := ctx.ShouldBindJSON(&body); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+// @Router /users/addAvailability [post]
+func (h *Handlers) AddAvailability(ctx *gin.Context) {
+	var body dto.AvailabilityRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.UserID == "" {
+		h.logger.Warn("invalid format of request to add availability window", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "Invalid merge request body",
+				Message: "invalid format of request to add availability window",
 			},
 		})
-		h.logger.Warn("invalid merge body", zap.Error(err))
 		return
 	}
+	w, err := h.svc.AddAvailability(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrUserNotFound):
+			h.logger.Warn("user not found while AddAvailability", zap.String("user_id", body.UserID))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "user not found",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidAvailability):
+			h.logger.Warn("invalid availability window", zap.String("user_id", body.UserID),
+				zap.Time("from", body.From), zap.Time("to", body.To))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "to must be after from and in the future",
+				},
+			})
+		default:
+			h.logger.Error("failed to AddAvailability", zap.Error(err), zap.String("user_id", body.UserID))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, dto.AvailabilityResponse{
+		UserId:  w.UserID,
+		Windows: []dto.AvailabilityWindow{availabilityWindow(*w)},
+	})
+	h.logger.Info("Successfully added availability window", zap.String("user_id", w.UserID), zap.Int("availability_id", w.Id))
+}
 
-	userId, ok := ctx.Get("User_Id")
-	if !ok {
-		h.logger.Warn("unauthorized user to merge PR")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+// GetAvailability godoc
+// @Summary Получить окна недоступности пользователя
+// @Description Возвращает текущие и будущие окна недоступности пользователя
+// @Tags Users
+// @Param Authorization header string true "Bearer JWT пользователя"
+// @Param user_id query string true "Id пользователя"
+// @Success 200 {object} dto.AvailabilityResponse "Окна недоступности"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 401 {object} dto.ErrorResponse "Пользователь не авторизован"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/getAvailability [get]
+func (h *Handlers) GetAvailability(ctx *gin.Context) {
+	par := ctx.Query("user_id")
+	if par == "" {
+		h.logger.Warn("GetAvailability: empty user_id parameter")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
-				Code:    CodeUnauthorized,
-				Message: "No token to merge PR",
+				Code:    CodeBadRequest,
+				Message: "No chosen user for getting availability",
 			},
 		})
 		return
 	}
-
-	pr, err := h.svc.Merge(ctx, userId.(string), body.Id)
+	windows, err := h.svc.GetAvailability(ctx, par)
 	if err != nil {
-		switch err {
-		case application.ErrPrNotFound:
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrUserNotFound) {
+			h.logger.Warn("Not found user", zap.String("target_name", par))
 			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
 					Code:    CodeNotFound,
 					Message: "resource not found",
 				},
 			})
-			h.logger.Warn("PR not found", zap.String("pr_id", body.Id))
 			return
-		case application.ErrUnableToMerge:
-			ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+		}
+		h.logger.Error("failed to get availability", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	resp := dto.AvailabilityResponse{
+		UserId:  par,
+		Windows: make([]dto.AvailabilityWindow, 0, len(windows)),
+	}
+	for _, w := range windows {
+		resp.Windows = append(resp.Windows, availabilityWindow(w))
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// DeleteAvailability godoc
+// @Summary Удалить окно недоступности
+// @Description Удаляет окно недоступности пользователя. Если пользователь находился внутри окна, ему сразу предлагаются PR с need_more_reviewers. Доступно администраторам и тимлидам команды.
+// @Tags Users
+// @Accept json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.DeleteAvailabilityRequest true "Пользователь и id окна"
+// @Success 204 "Окно удалено"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь или окно не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/deleteAvailability [post]
+func (h *Handlers) DeleteAvailability(ctx *gin.Context) {
+	var body dto.DeleteAvailabilityRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.UserID == "" || body.Id == 0 {
+		h.logger.Warn("invalid format of request to delete availability window", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to delete availability window",
+			},
+		})
+		return
+	}
+	if err := h.svc.DeleteAvailability(ctx, body.UserID, body.Id); err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrUserNotFound) || errors.Is(err, application.ErrAvailabilityNotFound) {
+			h.logger.Warn("availability window not found", zap.String("user_id", body.UserID), zap.Int("availability_id", body.Id))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
-					Code:    CodeForbidden,
-					Message: "User is not a reviewer, unable to merge",
+					Code:    CodeNotFound,
+					Message: "resource not found",
 				},
 			})
-			h.logger.Warn("unable to merge, user is not a reviewer",
-				zap.String("pr_id", body.Id),
-				zap.String("user_id", userId.(string)),
-			)
-			return
-		default:
-			ctx.AbortWithStatus(http.StatusInternalServerError)
-			h.logger.Error("error while merging PR",
-				zap.Error(err),
-				zap.String("pr_id", body.Id),
-				zap.String("user_id", userId.(string)),
-			)
 			return
 		}
+		h.logger.Error("failed to DeleteAvailability", zap.Error(err), zap.String("user_id", body.UserID))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
 	}
+	ctx.Status(http.StatusNoContent)
+	h.logger.Info("Successfully deleted availability window", zap.String("user_id", body.UserID), zap.Int("availability_id", body.Id))
+}
 
-	reviewers := make([]string, len(pr.Reviewers))
-	for i, r := range pr.Reviewers {
-		reviewers[i] = r.Id
-	}
-
-	resp := dto.MergeResponse{
-		Pr: dto.MergedPullRequestOfUser{
-			Id:        pr.Id,
-			Name:      pr.Name,
-			AuthorId:  pr.Author.Id,
-			Status:    pr.Status,
-			Reviewers: reviewers,
-			MergeAt:   pr.MergedAt,
-		},
+func availabilityWindow(w entityUser.Availability) dto.AvailabilityWindow {
+	return dto.AvailabilityWindow{
+		Id:     w.Id,
+		From:   w.From,
+		To:     w.To,
+		Reason: w.Reason,
 	}
-
-	ctx.JSON(http.StatusOK, resp)
-	h.logger.Info("successfully merged PR", zap.String("pr_id", body.Id))
 }
 
-// Reasign godoc
-// @Summary Переназначить конкретного ревьювера на другого из его команды
-// @Description Переназначить конкретного ревьювера на другого из его команды
-// @Tags PullRequests
-// @Param body body dto.ReassignPullRequest true "data"
-//
-//	@Param Authorization header string true "токен администратора(Вводить без Bearer)"
-//	@Success 200 {object} dto.MergeResponse "Переназначение выполнено"
-//	@Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
-//
-// @Failure 401 {object} dto.ErrorResponse "Некорректный формат запроса"
-// @Failure 404 {object} dto.ErrorResponse "PR не найден"
-// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /pullRequest/reassign [post]
-func (h *Handlers) Reasign(ctx *gin.Context) {
-	var body dto.ReassignPullRequest
-	if err := ctx.ShouldBindJSON(&body); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+// CreatePR godoc
+// @Summary      Создать Pull Request
+// @Description  Создаёт новый Pull Request от указанного автора.
+// @Description  Ревьюеры выбираются автоматически на основе команды автора.
+// @Description  В extra_teams можно указать дополнительные команды и сколько ревьюеров взять из каждой.
+// @Description  В required_tags можно указать нужные навыки, ревьюеры с ними выбираются в первую очередь.
+// @Description  PR с draft=true создаётся в состоянии DRAFT без ревьюеров, они назначаются при /pullRequest/ready.
+// @Tags         PullRequests
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param        body   body      dto.CreatePR  true  "Данные для создания Pull Request"
+// @Success      201    {object}  dto.PullRequestResponse "PR успешно создан"
+// @Failure      400    {object}  dto.ErrorResponse "Некорректный формат запроса"
+// @Failure      404    {object}  dto.ErrorResponse "Автор или команда не найдены"
+// @Failure      409    {object}  dto.ErrorResponse "PR с таким ID уже существует"
+// @Failure      500    "Внутренняя ошибка сервера"
+// @Router       /pullRequest/create [post]
+func (h *Handlers) CreatePR(ctx *gin.Context) {
+	var body dto.CreatePR
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.logger.Warn("invalid format of request to Create PR, error parsing JSON", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "Invalid reassign PR body",
+				Message: "invalid format of request to Create PR, error parsing JSON",
 			},
 		})
-		h.logger.Warn("invalid reassign PR body", zap.Error(err))
 		return
 	}
-
-	if body.OldReviewer == "" {
-		h.logger.Warn("no old_reviewer_id provided in Reasign")
+	if body.PrAuthor == "" {
+		h.logger.Warn("no author_id provided in CreatePR")
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "No old_reviewer_id for reassigning PR",
+				Message: "No chosen author for creating PR",
 			},
 		})
 		return
 	}
 	if body.PrID == "" {
-		h.logger.Warn("no pull_request_id provided in Reasign")
+		h.logger.Warn("no pr_id provided in CreatePR")
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "No pull_request_id for reassigning PR",
+				Message: "No chosen PR id for creating PR",
 			},
 		})
 		return
 	}
-
-	pr, replacedBy, err := h.svc.Reassign(ctx, body.PrID, body.OldReviewer)
+	if body.PrName == "" {
+		h.logger.Warn("no pr_name provided in CreatePR")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "No chosen PR name for creating PR",
+			},
+		})
+		return
+	}
+	pr, err := h.svc.CreatePR(ctx, body)
 	if err != nil {
-		switch err {
-		case application.ErrPrIsMerged:
-			ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrPrIsAlreadyCreated) {
+			h.logger.Warn("Pr with this ID is already created", zap.String("Pr_id", body.PrID))
+			ctx.JSON(http.StatusConflict, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
-					Code:    "PR_MERGED",
-					Message: "cannot reassign on merged PR",
+					Code:    CodePrExists,
+					Message: "PR id already exists",
 				},
 			})
-			h.logger.Warn(" pull_request_id is already merged", zap.String("pr_id", body.PrID))
 			return
-		case application.ErrPrNotFound:
-			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+		}
+		if errors.Is(err, application.ErrAuthorOrTeamAreNotFound) || errors.Is(err, application.ErrTeamNotFound) {
+			h.logger.Warn("Author or team of this PR not exist", zap.String("Pr_id", body.PrID),
+				zap.String("author_id", body.PrAuthor))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
 					Code:    CodeNotFound,
 					Message: "resource not found",
 				},
 			})
-			h.logger.Warn(" pull_request_id is not found", zap.String("pr_id", body.PrID))
-			return
-		case application.ErrTeamNotFound:
-			ctx.AbortWithStatus(http.StatusNotFound)
-			h.logger.Error(" error while reassigning PR", zap.String("pr_id", body.PrID), zap.Error(err))
 			return
-		case application.ErrNoCandidate:
-			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+		}
+		if errors.Is(err, application.ErrInvalidExtraTeams) {
+			h.logger.Warn("invalid extra teams in CreatePR", zap.String("Pr_id", body.PrID))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
-					Code:    CodeNoCandidate,
-					Message: "no available reviewer",
+					Code:    CodeBadRequest,
+					Message: "extra_teams must reference other existing teams with non-negative quotas",
 				},
 			})
-			h.logger.Warn("no available reviewer to reassign PR", zap.String("pr_id", body.PrID))
 			return
-		case application.ErrNotAssigned:
-			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+		}
+		if errors.Is(err, application.ErrInvalidTags) {
+			h.logger.Warn("invalid required tags in CreatePR", zap.String("Pr_id", body.PrID))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
-					Code:    CodeNotAssigned,
-					Message: "reviewer is not assigned to this PR",
+					Code:    CodeBadRequest,
+					Message: "required_tags must be non-empty words",
 				},
 			})
-			h.logger.Warn(" no candidate to reassign PR", zap.String("pr_id", body.PrID))
-			return
-		default:
-			ctx.AbortWithStatus(http.StatusInternalServerError)
-			h.logger.Error(" error while reassigning PR", zap.String("pr_id", body.PrID), zap.Error(err))
 			return
 		}
+		h.logger.Error("failed to create PR", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
 	}
-	assignedReviewers := make([]string, 0, len(pr.Reviewers))
-	for _, r := range pr.Reviewers {
-		assignedReviewers = append(assignedReviewers, r.Id)
+	reviewers := make([]string, 0, len(pr.Reviewers))
+	for _, reviewer := range pr.Reviewers {
+		reviewers = append(reviewers, reviewer.Id)
 	}
-
-	resp := dto.ReassignResponse{
-		Pr: dto.ReassignPR{
-			PullRequestID:     pr.Id,
-			PullRequestName:   pr.Name,
-			AuthorID:          pr.Author.Id,
-			Status:            pr.Status,
-			AssignedReviewers: assignedReviewers,
+	resp := dto.PullRequestResponse{
+		Pr: dto.PullRequest{
+			Id:                pr.Id,
+			Name:              pr.Name,
+			AuthorId:          pr.Author.Id,
+			Status:            string(pr.Status),
+			Reviewers:         reviewers,
+			NeedMoreReviewers: pr.NeedMoreReviewers,
+			NeedMoreReason:    pr.NeedMoreReason,
 		},
-		ReplacedBy: replacedBy,
 	}
-
-	ctx.JSON(http.StatusOK, resp)
-	h.logger.Info("successfully reassigned PR", zap.String("pr_id", body.PrID), zap.String("replaced_by", replacedBy))
+	h.logger.Info("successfully created Pull Request", zap.String("author_id", pr.Author.Id), zap.String("Pr_id", pr.Id))
+	ctx.JSON(http.StatusCreated, resp)
 }
 
-// GetStats godoc
-// @Summary Получить статистику PR
-// @Description Возвращает статистику по количеству ревьюеров на PR и по количеству PR, рассмотренных каждым пользователем
+// GetUsersPr godoc
+// @Summary Получить PR'ы, где пользователь назначен ревьювером
+// @Description Получить PR'ы, где пользователь назначен ревьювером
+// @Tags Users
+// @Param Authorization header string true "Bearer JWT пользователя"
+// @Param user_id query string true "Id пользователя"
+// @Success 200 {object} dto.UsersPrResponse "Список PR'ов пользователя"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 401 {object} dto.ErrorResponse "Пользователь не авторизован"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/getReview [get]
+func (h *Handlers) GetUsersPr(ctx *gin.Context) {
+	if _, ok := principal(ctx); !ok {
+		h.logger.Warn("unauthorized user to get PR")
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeUnauthorized,
+				Message: "No token to get info of PR",
+			},
+		})
+		return
+	}
+	par := ctx.Query("user_id")
+	if par == "" {
+		h.logger.Warn("GetUsersPr: empty user_id parameter")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "No chosen user for getting PR",
+			},
+		})
+		return
+	}
+	prs, err := h.svc.GetUsersPr(ctx, par)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrUserNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			h.logger.Warn("Not found user", zap.String("target_name", par))
+			return
+		}
+		h.logger.Error("failed to get user PRs", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	prResp := make([]dto.PullRequestOfUser, 0, len(prs))
+	for _, pr := range prs {
+		prResp = append(prResp, dto.PullRequestOfUser{
+			Id:       pr.Id,
+			Name:     pr.Name,
+			AuthorId: pr.Author.Id,
+			Status:   string(pr.Status),
+		})
+	}
+	resp := dto.UsersPrResponse{
+		UserId: par,
+		Prs:    prResp,
+	}
+	ctx.JSON(http.StatusOK, resp)
+	h.logger.Info("successfully got users pr", zap.String("user_id", par))
+}
+
+// Merge godoc
+// @Summary Пометить PR как MERGED
+// @Description Пометить PR как MERGED. Нужны одобрения назначенных ревьюеров (required_approvals команды автора) и ни одного неснятого CHANGES_REQUESTED.
+// @Description Администратор может смержить PR без одобрений, тогда в ответе review_bypassed = true.
+// @Tags PullRequests
+// @Param body body dto.MergeRequest true "Id PR"
+//
+//	@Param Authorization header string true "Bearer JWT пользователя"
+//	@Success 200 {object} dto.MergeResponse "PR в состоянии MERGED"
+//	@Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+//
+// @Failure 401 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR в состоянии DRAFT или CLOSED, не хватает одобрений или запрошены изменения"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /pullRequest/merge [post]
+func (h *Handlers) Merge(ctx *gin.Context) {
+	var body dto.MergeRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "Invalid merge request body",
+			},
+		})
+		h.logger.Warn("invalid merge body", zap.Error(err))
+		return
+	}
+
+	caller, ok := principal(ctx)
+	if !ok {
+		h.logger.Warn("unauthorized user to merge PR")
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeUnauthorized,
+				Message: "No token to merge PR",
+			},
+		})
+		return
+	}
+
+	pr, err := h.svc.Merge(ctx, body.Id)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch err {
+		case application.ErrPrNotFound:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			h.logger.Warn("PR not found", zap.String("pr_id", body.Id))
+			return
+		case application.ErrInvalidTransition:
+			ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeInvalidStatus,
+					Message: "only open PR can be merged",
+				},
+			})
+			h.logger.Warn("unable to merge PR in current status", zap.String("pr_id", body.Id))
+			return
+		case application.ErrNotEnoughApprovals:
+			ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNoApprovals,
+					Message: "PR does not have enough approvals",
+				},
+			})
+			h.logger.Warn("unable to merge PR without approvals", zap.String("pr_id", body.Id))
+			return
+		case application.ErrChangesRequested:
+			ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeChangesRequested,
+					Message: "changes are requested on PR",
+				},
+			})
+			h.logger.Warn("unable to merge PR with requested changes", zap.String("pr_id", body.Id))
+			return
+		case application.ErrUnableToMerge:
+			ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeForbidden,
+					Message: "User is not a reviewer, unable to merge",
+				},
+			})
+			h.logger.Warn("unable to merge, user is not a reviewer",
+				zap.String("pr_id", body.Id),
+				zap.String("user_id", caller.Subject),
+			)
+			return
+		default:
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			h.logger.Error("error while merging PR",
+				zap.Error(err),
+				zap.String("pr_id", body.Id),
+				zap.String("user_id", caller.Subject),
+			)
+			return
+		}
+	}
+
+	reviewers := make([]string, len(pr.Reviewers))
+	for i, r := range pr.Reviewers {
+		reviewers[i] = r.Id
+	}
+
+	resp := dto.MergeResponse{
+		Pr: dto.MergedPullRequestOfUser{
+			Id:             pr.Id,
+			Name:           pr.Name,
+			AuthorId:       pr.Author.Id,
+			Status:         string(pr.Status),
+			Reviewers:      reviewers,
+			MergeAt:        pr.MergedAt,
+			MergedBy:       pr.MergedBy,
+			ReviewBypassed: pr.ReviewBypassed,
+		},
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+	h.logger.Info("successfully merged PR", zap.String("pr_id", body.Id))
+}
+
+// SubmitReview godoc
+// @Summary Оставить ревью PR
+// @Description Назначенный ревьюер оставляет вердикт по открытому PR: APPROVED, CHANGES_REQUESTED или COMMENTED.
+// @Description Для мержа учитывается последний APPROVED/CHANGES_REQUESTED каждого ревьюера, COMMENTED его не меняет.
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT пользователя"
+// @Param body body dto.ReviewRequest true "Id PR, вердикт и комментарий"
+// @Success 201 {object} dto.ReviewResponse "Ревью сохранено"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса или неизвестный вердикт"
+// @Failure 401 {object} dto.ErrorResponse "Нет токена"
+// @Failure 403 {object} dto.ErrorResponse "Пользователь не назначен ревьюером PR"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR не открыт"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /pullRequest/review [post]
+func (h *Handlers) SubmitReview(ctx *gin.Context) {
+	var body dto.ReviewRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.PrID == "" {
+		h.logger.Warn("invalid review request body", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "pull_request_id and verdict are required",
+			},
+		})
+		return
+	}
+	caller, ok := principal(ctx)
+	if !ok {
+		h.logger.Warn("unauthorized user to review PR")
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeUnauthorized,
+				Message: "No token to review PR",
+			},
+		})
+		return
+	}
+	review, err := h.svc.SubmitReview(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrInvalidVerdict):
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "verdict must be APPROVED, CHANGES_REQUESTED or COMMENTED",
+				},
+			})
+			h.logger.Warn("invalid review verdict", zap.String("verdict", body.Verdict))
+		case errors.Is(err, application.ErrPrNotFound):
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			h.logger.Warn("PR to review not found", zap.String("pr_id", body.PrID))
+		case errors.Is(err, application.ErrNotAssigned):
+			ctx.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotAssigned,
+					Message: "user is not assigned to PR",
+				},
+			})
+			h.logger.Warn("user is not a reviewer of PR", zap.String("pr_id", body.PrID), zap.String("user_id", caller.Subject))
+		case errors.Is(err, application.ErrPrIsMerged), errors.Is(err, application.ErrPrNotOpen):
+			ctx.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodePrNotOpen,
+					Message: "only open PR can be reviewed",
+				},
+			})
+			h.logger.Warn("PR to review is not open", zap.String("pr_id", body.PrID))
+		default:
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			h.logger.Error("failed to submit review", zap.String("pr_id", body.PrID), zap.Error(err))
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, dto.ReviewResponse{
+		Review: dto.PullRequestReview{
+			Id:         review.Id,
+			PrID:       review.PrID,
+			ReviewerID: review.ReviewerID,
+			Verdict:    string(review.Verdict),
+			Body:       review.Body,
+			CreatedAt:  review.CreatedAt,
+		},
+	})
+	h.logger.Info("review submitted", zap.String("pr_id", review.PrID), zap.String("verdict", string(review.Verdict)))
+}
+
+// Ready godoc
+// @Summary Перевести черновик PR в состояние OPEN
+// @Description Переводит PR из DRAFT в OPEN и назначает ревьюеров так же, как при создании PR.
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.PullRequestLifecycleRequest true "Id PR, дополнительные команды и изменённые файлы"
+// @Success 200 {object} dto.PullRequestResponse "PR открыт, ревьюеры назначены"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR не в состоянии DRAFT"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /pullRequest/ready [post]
+func (h *Handlers) Ready(ctx *gin.Context) {
+	var body dto.PullRequestLifecycleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.PrID == "" {
+		h.logger.Warn("invalid ready request body", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+	pr, err := h.svc.Ready(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.lifecycleError(ctx, body.PrID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pullRequestResponse(pr))
+	h.logger.Info("PR is ready for review", zap.String("pr_id", pr.Id))
+}
+
+// GetPullRequest godoc
+// @Summary Получить PR
+// @Description Возвращает PR с ревьюерами. Для PR, пришедшего из GitHub/GitLab, в code_host видно,
+// @Description перенесены ли ревьюеры к провайдеру: PENDING, SYNCED или FAILED с last_error.
+// @Tags PullRequests
+// @Produce json
+// @Param Authorization header string true "Bearer JWT пользователя"
+// @Param pull_request_id query string true "Id PR"
+// @Success 200 {object} dto.PullRequestResponse "PR"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 401 {object} dto.ErrorResponse "Пользователь не авторизован"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /pullRequest/get [get]
+func (h *Handlers) GetPullRequest(ctx *gin.Context) {
+	prID := ctx.Query("pull_request_id")
+	if prID == "" {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+	pr, err := h.svc.GetPullRequest(ctx, prID)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.lifecycleError(ctx, prID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pullRequestResponse(pr))
+}
+
+// GetPullRequestHistory godoc
+// @Summary История ревьюеров PR
+// @Description Возвращает все назначения, снятия и замены ревьюеров PR с причиной (create, reassign, deactivation,
+// @Description team_move, sla, stale и т.д.), автором изменения (system - фоновые задачи) и временем.
+// @Tags PullRequests
+// @Produce json
+// @Param Authorization header string true "Bearer JWT пользователя"
+// @Param pull_request_id query string true "Id PR"
+// @Success 200 {object} dto.PullRequestHistoryResponse "История ревьюеров"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 401 {object} dto.ErrorResponse "Пользователь не авторизован"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /pullRequest/history [get]
+func (h *Handlers) GetPullRequestHistory(ctx *gin.Context) {
+	prID := ctx.Query("pull_request_id")
+	if prID == "" {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+	history, err := h.svc.GetPullRequestHistory(ctx, prID)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.lifecycleError(ctx, prID, err)
+		return
+	}
+	resp := dto.PullRequestHistoryResponse{
+		PrId:    prID,
+		History: make([]dto.ReviewerAssignment, 0, len(history)),
+	}
+	for _, a := range history {
+		resp.History = append(resp.History, dto.ReviewerAssignment{
+			ReviewerId: a.ReviewerID,
+			Action:     string(a.Action),
+			ReplacedBy: a.ReplacedBy,
+			Reason:     a.Reason,
+			Actor:      a.Actor,
+			At:         a.At,
+		})
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// ClosePR godoc
+// @Summary Закрыть PR без слияния
+// @Description Переводит PR в состояние CLOSED и снимает с него всех ревьюеров.
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.ClosePullRequest true "Id PR"
+// @Success 200 {object} dto.PullRequestResponse "PR закрыт"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR уже закрыт или слит"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /pullRequest/close [post]
+func (h *Handlers) ClosePR(ctx *gin.Context) {
+	var body dto.ClosePullRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.Id == "" {
+		h.logger.Warn("invalid close request body", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+	pr, err := h.svc.Close(ctx, body.Id)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.lifecycleError(ctx, body.Id, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pullRequestResponse(pr))
+	h.logger.Info("PR closed", zap.String("pr_id", pr.Id))
+}
+
+// Reopen godoc
+// @Summary Повторно открыть закрытый PR
+// @Description Переводит PR из CLOSED в OPEN и заново назначает ревьюеров.
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.PullRequestLifecycleRequest true "Id PR, дополнительные команды и изменённые файлы"
+// @Success 200 {object} dto.PullRequestResponse "PR открыт, ревьюеры назначены"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR не в состоянии CLOSED"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /pullRequest/reopen [post]
+func (h *Handlers) Reopen(ctx *gin.Context) {
+	var body dto.PullRequestLifecycleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.PrID == "" {
+		h.logger.Warn("invalid reopen request body", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+	pr, err := h.svc.Reopen(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.lifecycleError(ctx, body.PrID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pullRequestResponse(pr))
+	h.logger.Info("PR reopened", zap.String("pr_id", pr.Id))
+}
+
+// lifecycleError отвечает на ошибку смены состояния PR
+func (h *Handlers) lifecycleError(ctx *gin.Context, prID string, err error) {
+	switch {
+	case errors.Is(err, application.ErrPrNotFound), errors.Is(err, application.ErrAuthorOrTeamAreNotFound),
+		errors.Is(err, application.ErrTeamNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeNotFound,
+				Message: "resource not found",
+			},
+		})
+		h.logger.Warn("PR or its team not found", zap.String("pr_id", prID))
+	case errors.Is(err, application.ErrInvalidTransition):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeInvalidStatus,
+				Message: "transition is not allowed from current PR status",
+			},
+		})
+		h.logger.Warn("invalid PR status transition", zap.String("pr_id", prID), zap.Error(err))
+	case errors.Is(err, application.ErrInvalidExtraTeams):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "extra_teams must reference other existing teams with non-negative quotas",
+			},
+		})
+		h.logger.Warn("invalid extra teams", zap.String("pr_id", prID))
+	default:
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		h.logger.Error("failed to change PR status", zap.String("pr_id", prID), zap.Error(err))
+	}
+}
+
+// pullRequestResponse собирает ответ с данными PR
+func pullRequestResponse(pr *entityPr.PullRequest) dto.PullRequestResponse {
+	reviewers := make([]string, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		reviewers = append(reviewers, r.Id)
+	}
+	return dto.PullRequestResponse{
+		Pr: dto.PullRequest{
+			Id:                pr.Id,
+			Name:              pr.Name,
+			AuthorId:          pr.Author.Id,
+			Status:            string(pr.Status),
+			Reviewers:         reviewers,
+			NeedMoreReviewers: pr.NeedMoreReviewers,
+			NeedMoreReason:    pr.NeedMoreReason,
+			CodeHost:          codeHostSyncResponse(pr.CodeHost),
+		},
+	}
+}
+
+func codeHostSyncResponse(l *entityHost.Link) *dto.CodeHostSync {
+	if l == nil {
+		return nil
+	}
+	return &dto.CodeHostSync{
+		Provider:   string(l.Provider),
+		Repo:       l.Repo,
+		Number:     l.Number,
+		SyncStatus: string(l.Status),
+		Attempts:   l.Attempts,
+		LastError:  l.LastError,
+		SyncedAt:   l.SyncedAt,
+	}
+}
+
+// Reasign godoc
+// @Summary Переназначить конкретного ревьювера на другого из его команды
+// @Description Переназначить конкретного ревьювера на другого из его команды (или из пула, из которого он был назначен).
+// @Description Автор PR и уже назначенные ревьюеры не рассматриваются. В preferred_candidate_id можно явно указать замену.
+// @Tags PullRequests
+// @Param body body dto.ReassignPullRequest true "data"
+//
+//	@Param Authorization header string true "Bearer JWT администратора или тимлида"
+//	@Success 200 {object} dto.MergeResponse "Переназначение выполнено"
+//	@Failure 400 {object} dto.ErrorResponse "Некорректный формат запроса"
+//
+// @Failure 401 {object} dto.ErrorResponse "Некорректный формат запроса"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR уже смержен или выбранный кандидат не подходит"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /pullRequest/reassign [post]
+func (h *Handlers) Reasign(ctx *gin.Context) {
+	var body dto.ReassignPullRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "Invalid reassign PR body",
+			},
+		})
+		h.logger.Warn("invalid reassign PR body", zap.Error(err))
+		return
+	}
+
+	if body.OldReviewer == "" {
+		h.logger.Warn("no old_reviewer_id provided in Reasign")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "No old_reviewer_id for reassigning PR",
+			},
+		})
+		return
+	}
+	if body.PrID == "" {
+		h.logger.Warn("no pull_request_id provided in Reasign")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "No pull_request_id for reassigning PR",
+			},
+		})
+		return
+	}
+
+	pr, replacedBy, err := h.svc.Reassign(ctx, body.PrID, body.OldReviewer, body.PreferredCandidate)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch err {
+		case application.ErrPrIsMerged:
+			ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    "PR_MERGED",
+					Message: "cannot reassign on merged PR",
+				},
+			})
+			h.logger.Warn(" pull_request_id is already merged", zap.String("pr_id", body.PrID))
+			return
+		case application.ErrPrNotOpen:
+			ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodePrNotOpen,
+					Message: "cannot reassign on draft or closed PR",
+				},
+			})
+			h.logger.Warn(" pull_request_id is not open", zap.String("pr_id", body.PrID))
+			return
+		case application.ErrPrNotFound:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			h.logger.Warn(" pull_request_id is not found", zap.String("pr_id", body.PrID))
+			return
+		case application.ErrTeamNotFound:
+			ctx.AbortWithStatus(http.StatusNotFound)
+			h.logger.Error(" error while reassigning PR", zap.String("pr_id", body.PrID), zap.Error(err))
+			return
+		case application.ErrNoCandidate:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNoCandidate,
+					Message: "no available reviewer",
+				},
+			})
+			h.logger.Warn("no available reviewer to reassign PR", zap.String("pr_id", body.PrID))
+			return
+		case application.ErrInvalidCandidate:
+			ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeInvalidCandidate,
+					Message: "preferred candidate is not an active member of the reviewer's pool or is already assigned",
+				},
+			})
+			h.logger.Warn("invalid preferred candidate to reassign PR", zap.String("pr_id", body.PrID),
+				zap.String("preferred_candidate_id", body.PreferredCandidate))
+			return
+		case application.ErrUserNotFound:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			h.logger.Warn("old reviewer is not found", zap.String("old_reviewer_id", body.OldReviewer))
+			return
+		case application.ErrNotAssigned:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotAssigned,
+					Message: "reviewer is not assigned to this PR",
+				},
+			})
+			h.logger.Warn(" no candidate to reassign PR", zap.String("pr_id", body.PrID))
+			return
+		default:
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			h.logger.Error(" error while reassigning PR", zap.String("pr_id", body.PrID), zap.Error(err))
+			return
+		}
+	}
+	assignedReviewers := make([]string, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		assignedReviewers = append(assignedReviewers, r.Id)
+	}
+
+	resp := dto.ReassignResponse{
+		Pr: dto.ReassignPR{
+			PullRequestID:     pr.Id,
+			PullRequestName:   pr.Name,
+			AuthorID:          pr.Author.Id,
+			Status:            string(pr.Status),
+			AssignedReviewers: assignedReviewers,
+			NeedMoreReviewers: pr.NeedMoreReviewers,
+			NeedMoreReason:    pr.NeedMoreReason,
+		},
+		ReplacedBy: replacedBy,
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+	h.logger.Info("successfully reassigned PR", zap.String("pr_id", body.PrID), zap.String("replaced_by", replacedBy))
+}
+
+// GetStats godoc
+// @Summary Получить статистику PR
+// @Description Возвращает статистику по количеству ревьюеров на PR и по количеству PR, рассмотренных каждым пользователем
 // @Tags Stats
-// @Param Authorization header string true "токен администратора(Вводить без Bearer)"
+// @Param Authorization header string true "Bearer JWT с ролью admin"
 // @Success 200 {object} dto.StatsResponse "Статистика успешно получена"
 // @Failure 401 {object} dto.ErrorResponse "Неавторизованный доступ"
+// @Failure 403 {object} dto.ErrorResponse "Нет права stats:read"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /stats/get [get]
+func (h *Handlers) GetStats(ctx *gin.Context) {
+	var resp dto.StatsResponse
+	byUser, byPr, err := h.svc.GetStatistics(ctx)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.logger.Error("error getting stats", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	}
+	resp.ByPR = byPr
+	resp.ByUser = byUser
+	ctx.JSON(http.StatusOK, resp)
+	h.logger.Info("successfully given stats")
+}
+
+// Deactivation godoc
+// @Summary Деактивировать пользователей команды
+// @Description Массово деактивирует пользователей указанной команды и безопасно переназначает их открытые PR другим активным участникам
+// @Tags Deactivation
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.DeactivationRequest true "Тело запроса с ID пользователей и названием команды"
+// @Success 200 {string} string "Пользователи успешно деактивированы"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный запрос (пустой teamName или userIDs)"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный доступ"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /deactivate/use [post]
+func (h *Handlers) Deactivation(ctx *gin.Context) {
+	var body dto.DeactivationRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "Invalid users deactivation body",
+			},
+		})
+		h.logger.Warn("invalid users deactivation body", zap.Error(err))
+		return
+	}
+	if body.TeamName == "" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "Empty TeamName",
+			},
+		})
+		h.logger.Warn("invalid users deactivation body, empty Team Name")
+		return
+	}
+	if len(body.UserIDs) == 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "Empty users id",
+			},
+		})
+		h.logger.Warn("invalid users deactivation body, empty users id")
+		return
+	}
+
+	err := h.svc.Deactivate(ctx, body.TeamName, body.UserIDs)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrTeamNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "no team found",
+				},
+			})
+			h.logger.Warn("no team found to Deactivate users", zap.String("team_id", body.TeamName))
+			return
+		}
+		h.logger.Error("error while deactivating users", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	}
+	ctx.Status(http.StatusOK)
+	h.logger.Info("deactivated users", zap.String("team_name", body.TeamName))
+}
+
+// UploadCodeOwners godoc
+// @Summary Загрузка правил CODEOWNERS команды
+// @Description Заменяет правила владения путями для команды. Формат как у CODEOWNERS: шаблон пути и владельцы (@user_id или @org/team_name), последнее подходящее правило побеждает. Доступно администраторам и тимлидам команды.
+// @Tags codeowners
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.UploadCodeOwnersRequest true "Команда и содержимое файла CODEOWNERS"
+// @Success 200 {object} dto.CodeOwnersResponse "Сохранённые правила"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный синтаксис или неизвестный владелец"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /codeowners/upload [post]
+func (h *Handlers) UploadCodeOwners(ctx *gin.Context) {
+	var body dto.UploadCodeOwnersRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.TeamName == "" {
+		h.logger.Warn("invalid format of request to upload code owners", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to upload code owners",
+			},
+		})
+		return
+	}
+	rules, err := h.svc.UploadCodeOwners(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrTeamNotFound):
+			h.logger.Warn("Not found team to upload code owners", zap.String("team_name", body.TeamName))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+		case errors.Is(err, application.ErrInvalidCodeOwners), errors.Is(err, application.ErrUnknownOwner):
+			h.logger.Warn("invalid code owners", zap.Error(err), zap.String("team_name", body.TeamName))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: err.Error(),
+				},
+			})
+		default:
+			h.logger.Error("failed to upload code owners", zap.Error(err), zap.String("team_name", body.TeamName))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+	resp := dto.CodeOwnersResponse{
+		TeamName: body.TeamName,
+		Rules:    make([]dto.CodeOwnerRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, dto.CodeOwnerRule{
+			Pattern: rule.Pattern,
+			Owners:  ownerNames(rule.Owners),
+		})
+	}
+	ctx.JSON(http.StatusOK, resp)
+	h.logger.Info("successfully uploaded code owners", zap.String("team_name", body.TeamName), zap.Int("rules", len(rules)))
+}
+
+// TestCodeOwners godoc
+// @Summary Проверка правил CODEOWNERS
+// @Description Показывает, каких владельцев сервис назначит для каждого из переданных путей по сохранённым правилам команды. Доступно администраторам и тимлидам команды.
+// @Tags codeowners
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.TestCodeOwnersRequest true "Команда и список путей"
+// @Success 200 {object} dto.CodeOwnersTestResponse "Владельцы по путям"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав в команде"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /codeowners/test [post]
+func (h *Handlers) TestCodeOwners(ctx *gin.Context) {
+	var body dto.TestCodeOwnersRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.TeamName == "" {
+		h.logger.Warn("invalid format of request to test code owners", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to test code owners",
+			},
+		})
+		return
+	}
+	owners, err := h.svc.TestCodeOwners(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrTeamNotFound) {
+			h.logger.Warn("Not found team to test code owners", zap.String("team_name", body.TeamName))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			return
+		}
+		h.logger.Error("failed to test code owners", zap.Error(err), zap.String("team_name", body.TeamName))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	resp := dto.CodeOwnersTestResponse{
+		TeamName: body.TeamName,
+		Owners:   make(map[string][]string, len(owners)),
+	}
+	for path, o := range owners {
+		resp.Owners[path] = ownerNames(o)
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func ownerNames(owners []entityOwners.Owner) []string {
+	res := make([]string, 0, len(owners))
+	for _, o := range owners {
+		if o.Kind == entityOwners.OwnerTeam {
+			res = append(res, "@team/"+o.Name)
+		} else {
+			res = append(res, "@"+o.Name)
+		}
+	}
+	return res
+}
+
+// GrantRole godoc
+// @Summary Выдать роль пользователю
+// @Description Выдаёт пользователю роль admin (на всю организацию) или team_lead (в команде team_name). Тимлид управляет пользователями, PR, настройками и CODEOWNERS своей команды. Доступно только администраторам.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param body body dto.RoleRequest true "Пользователь, роль и команда для team_lead"
+// @Success 200 {object} dto.UserRolesResponse "Роли пользователя после выдачи"
+// @Failure 400 {object} dto.ErrorResponse "Неизвестная роль или команда указана неверно"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права roles:manage"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь или команда не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /roles/grant [post]
+func (h *Handlers) GrantRole(ctx *gin.Context) {
+	var body dto.RoleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.UserID == "" || body.Role == "" {
+		h.logger.Warn("invalid format of request to grant role", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to grant role",
+			},
+		})
+		return
+	}
+	grants, err := h.svc.GrantRole(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.roleError(ctx, body, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, userRolesResponse(body.UserID, grants))
+	h.logger.Info("Successfully granted role",
+		zap.String("user_id", body.UserID),
+		zap.String("role", body.Role),
+		zap.String("team_name", body.TeamName),
+	)
+}
+
+// RevokeRole godoc
+// @Summary Отозвать роль у пользователя
+// @Description Отзывает у пользователя роль admin или team_lead в команде team_name. Роли из токена не отзываются. Доступно только администраторам.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param body body dto.RoleRequest true "Пользователь, роль и команда для team_lead"
+// @Success 200 {object} dto.UserRolesResponse "Оставшиеся роли пользователя"
+// @Failure 400 {object} dto.ErrorResponse "Неизвестная роль или команда указана неверно"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права roles:manage"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь, команда или роль не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /roles/revoke [post]
+func (h *Handlers) RevokeRole(ctx *gin.Context) {
+	var body dto.RoleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.UserID == "" || body.Role == "" {
+		h.logger.Warn("invalid format of request to revoke role", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to revoke role",
+			},
+		})
+		return
+	}
+	grants, err := h.svc.RevokeRole(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.roleError(ctx, body, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, userRolesResponse(body.UserID, grants))
+	h.logger.Info("Successfully revoked role",
+		zap.String("user_id", body.UserID),
+		zap.String("role", body.Role),
+		zap.String("team_name", body.TeamName),
+	)
+}
+
+// GetUserRoles godoc
+// @Summary Получить роли пользователя
+// @Description Возвращает роли, выданные пользователю в сервисе. Роли из токена не возвращаются.
+// @Tags Roles
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param user_id query string true "Идентификатор пользователя"
+// @Success 200 {object} dto.UserRolesResponse "Роли пользователя"
+// @Failure 400 {object} dto.ErrorResponse "Не указан user_id"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /roles/get [get]
+func (h *Handlers) GetUserRoles(ctx *gin.Context) {
+	par := ctx.Query("user_id")
+	if par == "" {
+		h.logger.Warn("GetUserRoles: empty user_id parameter")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "No chosen user for getting roles",
+			},
+		})
+		return
+	}
+	grants, err := h.svc.GetUserRoles(ctx, par)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrUserNotFound) {
+			h.logger.Warn("Not found user", zap.String("target_name", par))
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			return
+		}
+		h.logger.Error("failed to get user roles", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	ctx.JSON(http.StatusOK, userRolesResponse(par, grants))
+}
+
+func (h *Handlers) roleError(ctx *gin.Context, body dto.RoleRequest, err error) {
+	switch {
+	case errors.Is(err, application.ErrInvalidRole):
+		h.logger.Warn("invalid role", zap.String("role", body.Role), zap.String("team_name", body.TeamName))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "role must be admin without team or team_lead with team_name",
+			},
+		})
+	case errors.Is(err, application.ErrUserNotFound),
+		errors.Is(err, application.ErrTeamNotFound),
+		errors.Is(err, application.ErrRoleNotGranted):
+		h.logger.Warn("role target not found", zap.String("user_id", body.UserID), zap.Error(err))
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeNotFound,
+				Message: err.Error(),
+			},
+		})
+	default:
+		h.logger.Error("failed to change user roles", zap.Error(err), zap.String("user_id", body.UserID))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	}
+}
+
+func userRolesResponse(userID string, grants []entityAuth.Grant) dto.UserRolesResponse {
+	roles := make([]dto.RoleDto, 0, len(grants))
+	for _, g := range grants {
+		roles = append(roles, dto.RoleDto{Role: string(g.Role), TeamName: g.TeamName})
+	}
+	return dto.UserRolesResponse{UserId: userID, Roles: roles}
+}
+
+// IssueAPIKey godoc
+// @Summary Выпустить API ключ
+// @Description Выпускает ключ для бота или CI с правами scopes (например pr:create, pr:merge) и необязательным сроком действия. Ключ передаётся как `Authorization: Bearer <key>` и возвращается только в этом ответе, сервис хранит лишь его хеш. Доступно только администраторам.
+// @Tags APIKeys
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param body body dto.IssueAPIKeyRequest true "Название, права и срок действия ключа"
+// @Success 201 {object} dto.IssueAPIKeyResponse "Выпущенный ключ"
+// @Failure 400 {object} dto.ErrorResponse "Пустое название, неизвестные права или срок в прошлом"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права apikeys:manage"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /apiKeys/issue [post]
+func (h *Handlers) IssueAPIKey(ctx *gin.Context) {
+	var body dto.IssueAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Warn("invalid format of request to issue api key", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to issue api key",
+			},
+		})
+		return
+	}
+	key, plain, err := h.svc.IssueAPIKey(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrInvalidAPIKeyName),
+			errors.Is(err, application.ErrInvalidScopes),
+			errors.Is(err, application.ErrInvalidExpiry):
+			h.logger.Warn("invalid api key request", zap.String("name", body.Name), zap.Strings("scopes", body.Scopes), zap.Error(err))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: err.Error(),
+				},
+			})
+		default:
+			h.logger.Error("failed to issue api key", zap.Error(err), zap.String("name", body.Name))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, dto.IssueAPIKeyResponse{Key: plain, APIKey: apiKeyResponse(*key)})
+	h.logger.Info("Successfully issued api key", zap.Int("api_key_id", key.Id), zap.String("name", key.Name))
+}
+
+// GetAPIKeys godoc
+// @Summary Список API ключей
+// @Description Возвращает все выпущенные ключи, включая отозванные, с временем последнего использования. Сами ключи не возвращаются. Доступно только администраторам.
+// @Tags APIKeys
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Success 200 {object} dto.APIKeysResponse "Выпущенные ключи"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права apikeys:manage"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /apiKeys/list [get]
+func (h *Handlers) GetAPIKeys(ctx *gin.Context) {
+	keys, err := h.svc.GetAPIKeys(ctx)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.logger.Error("failed to get api keys", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	resp := dto.APIKeysResponse{APIKeys: make([]dto.APIKeyDto, 0, len(keys))}
+	for _, k := range keys {
+		resp.APIKeys = append(resp.APIKeys, apiKeyResponse(k))
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// RevokeAPIKey godoc
+// @Summary Отозвать API ключ
+// @Description Отзывает ключ, после этого запросы с ним получают 401. Доступно только администраторам.
+// @Tags APIKeys
+// @Accept json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param body body dto.RevokeAPIKeyRequest true "Идентификатор ключа"
+// @Success 204 "Ключ отозван"
+// @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права apikeys:manage"
+// @Failure 404 {object} dto.ErrorResponse "Ключ не найден или уже отозван"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /apiKeys/revoke [post]
+func (h *Handlers) RevokeAPIKey(ctx *gin.Context) {
+	var body dto.RevokeAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.Id <= 0 {
+		h.logger.Warn("invalid format of request to revoke api key", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to revoke api key",
+			},
+		})
+		return
+	}
+	if err := h.svc.RevokeAPIKey(ctx, body.Id); err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrAPIKeyNotFound) {
+			h.logger.Warn("api key not found", zap.Int("api_key_id", body.Id))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			return
+		}
+		h.logger.Error("failed to revoke api key", zap.Error(err), zap.Int("api_key_id", body.Id))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+	h.logger.Info("Successfully revoked api key", zap.Int("api_key_id", body.Id))
+}
+
+func apiKeyResponse(k entityAuth.APIKey) dto.APIKeyDto {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return dto.APIKeyDto{
+		Id:         k.Id,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  k.CreatedAt,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
+
+// AddWebhook godoc
+// @Summary Подписаться на события
+// @Description Регистрирует адрес, на который сервис отправляет события pr.created, pr.reviewers_changed, pr.merged, user.deactivated и team.created. Без team_name приходят события всех команд, без events - все типы событий. Каждый запрос подписан заголовком X-PRS-Signature: t=<unix время>,v1=<hex HMAC-SHA256 от "<t>.<тело>" с секретом подписки>. Неудачные доставки повторяются с экспоненциальной паузой. Доступно только администраторам.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param body body dto.WebhookRequest true "Адрес, секрет, команда и типы событий"
+// @Success 201 {object} dto.WebhookDto "Созданная подписка"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный адрес, короткий секрет или неизвестный тип события"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права webhooks:manage"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks/add [post]
+func (h *Handlers) AddWebhook(ctx *gin.Context) {
+	var body dto.WebhookRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Warn("invalid format of request to add webhook", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to add webhook",
+			},
+		})
+		return
+	}
+	sub, err := h.svc.AddWebhook(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrInvalidWebhook):
+			h.logger.Warn("invalid webhook", zap.String("url", body.URL), zap.Strings("events", body.Events))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: err.Error(),
+				},
+			})
+		case errors.Is(err, application.ErrTeamNotFound):
+			h.logger.Warn("team not found while adding webhook", zap.String("team_name", body.TeamName))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "team not found",
+				},
+			})
+		default:
+			h.logger.Error("failed to add webhook", zap.Error(err), zap.String("url", body.URL))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, webhookResponse(*sub))
+	h.logger.Info("Successfully added webhook", zap.Int("webhook_id", sub.Id), zap.String("url", sub.URL))
+}
+
+// GetWebhooks godoc
+// @Summary Список подписок на события
+// @Description Возвращает все подписки без секретов. Доступно только администраторам.
+// @Tags Webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Success 200 {object} dto.WebhooksResponse "Подписки"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права webhooks:manage"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks/list [get]
+func (h *Handlers) GetWebhooks(ctx *gin.Context) {
+	subs, err := h.svc.GetWebhooks(ctx)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.logger.Error("failed to get webhooks", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	resp := dto.WebhooksResponse{Webhooks: make([]dto.WebhookDto, 0, len(subs))}
+	for _, sub := range subs {
+		resp.Webhooks = append(resp.Webhooks, webhookResponse(sub))
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// DeleteWebhook godoc
+// @Summary Удалить подписку
+// @Description Удаляет подписку вместе с её доставками. Доступно только администраторам.
+// @Tags Webhooks
+// @Accept json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param body body dto.DeleteWebhookRequest true "Идентификатор подписки"
+// @Success 204 "Подписка удалена"
+// @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права webhooks:manage"
+// @Failure 404 {object} dto.ErrorResponse "Подписка не найдена"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /stats/get [get]
-func (h *Handlers) GetStats(ctx *gin.Context) {
-	var resp dto.StatsResponse
-	byUser, byPr, err := h.svc.GetStatistics(ctx)
+// @Router /webhooks/delete [post]
+func (h *Handlers) DeleteWebhook(ctx *gin.Context) {
+	var body dto.DeleteWebhookRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.Id <= 0 {
+		h.logger.Warn("invalid format of request to delete webhook", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "invalid format of request to delete webhook",
+			},
+		})
+		return
+	}
+	if err := h.svc.DeleteWebhook(ctx, body.Id); err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrWebhookNotFound) {
+			h.logger.Warn("webhook not found", zap.Int("webhook_id", body.Id))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+			return
+		}
+		h.logger.Error("failed to delete webhook", zap.Error(err), zap.Int("webhook_id", body.Id))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+	h.logger.Info("Successfully deleted webhook", zap.Int("webhook_id", body.Id))
+}
+
+// GetDeliveries godoc
+// @Summary Список доставок событий
+// @Description Возвращает последние 100 доставок со статусом status (PENDING, DELIVERED или FAILED, по умолчанию FAILED) с числом попыток и последней ошибкой. Доступно только администраторам.
+// @Tags Webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param status query string false "Статус доставок"
+// @Success 200 {object} dto.DeliveriesResponse "Доставки"
+// @Failure 400 {object} dto.ErrorResponse "Неизвестный статус"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права webhooks:manage"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /webhooks/deliveries [get]
+func (h *Handlers) GetDeliveries(ctx *gin.Context) {
+	status := ctx.Query("status")
+	deliveries, err := h.svc.GetDeliveries(ctx, status)
 	if err != nil {
-		h.logger.Error("error getting stats", zap.Error(err))
+		if h.denied(ctx, err) {
+			return
+		}
+		if errors.Is(err, application.ErrInvalidDeliveryStatus) {
+			h.logger.Warn("unknown delivery status", zap.String("status", status))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "status must be PENDING, DELIVERED or FAILED",
+				},
+			})
+			return
+		}
+		h.logger.Error("failed to get webhook deliveries", zap.Error(err))
 		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	resp := dto.DeliveriesResponse{Deliveries: make([]dto.DeliveryDto, 0, len(deliveries))}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, deliveryResponse(d))
 	}
-	resp.ByPR = byPr
-	resp.ByUser = byUser
 	ctx.JSON(http.StatusOK, resp)
-	h.logger.Info("successfully given stats")
 }
 
-// Deactivation godoc
-// @Summary Деактивировать пользователей команды
-// @Description Массово деактивирует пользователей указанной команды и безопасно переназначает их открытые PR другим активным участникам
-// @Tags Deactivation
-// @Param Authorization header string true "токен администратора(Вводить без Bearer)"
-// @Param body body dto.DeactivationRequest true "Тело запроса с ID пользователей и названием команды"
-// @Success 200 {string} string "Пользователи успешно деактивированы"
-// @Failure 400 {object} dto.ErrorResponse "Некорректный запрос (пустой teamName или userIDs)"
-// @Failure 401 {object} dto.ErrorResponse "Неавторизованный доступ"
-// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// Redeliver godoc
+// @Summary Повторить доставку события
+// @Description Ставит проваленную доставку в очередь заново с полным числом попыток. Доступно только администраторам.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param body body dto.RedeliverRequest true "Идентификатор доставки"
+// @Success 200 {object} dto.DeliveryDto "Доставка поставлена в очередь"
+// @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет права webhooks:manage"
+// @Failure 404 {object} dto.ErrorResponse "Доставка не найдена"
+// @Failure 409 {object} dto.ErrorResponse "Доставка не в статусе FAILED"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /deactivate/use [post]
-func (h *Handlers) Deactivation(ctx *gin.Context) {
-	var body dto.DeactivationRequest
-	if err := ctx.ShouldBindJSON(&body); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+// @Router /webhooks/redeliver [post]
+func (h *Handlers) Redeliver(ctx *gin.Context) {
+	var body dto.RedeliverRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.Id <= 0 {
+		h.logger.Warn("invalid format of request to redeliver", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "Invalid users deactivation body",
+				Message: "invalid format of request to redeliver",
 			},
 		})
-		h.logger.Warn("invalid users deactivation body", zap.Error(err))
 		return
 	}
-	if body.TeamName == "" {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+	d, err := h.svc.Redeliver(ctx, body.Id)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrDeliveryNotFound):
+			h.logger.Warn("delivery not found", zap.Int("delivery_id", body.Id))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+		case errors.Is(err, application.ErrDeliveryNotFailed):
+			h.logger.Warn("delivery is not failed", zap.Int("delivery_id", body.Id))
+			ctx.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeDeliveryNotFailed,
+					Message: "only failed deliveries can be redelivered",
+				},
+			})
+		default:
+			h.logger.Error("failed to redeliver", zap.Error(err), zap.Int("delivery_id", body.Id))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveryResponse(*d))
+	h.logger.Info("Delivery queued again", zap.Int("delivery_id", d.Id))
+}
+
+func webhookResponse(sub entityHook.Subscription) dto.WebhookDto {
+	events := make([]string, 0, len(sub.Events))
+	for _, e := range sub.Events {
+		events = append(events, string(e))
+	}
+	return dto.WebhookDto{
+		Id:        sub.Id,
+		URL:       sub.URL,
+		TeamName:  sub.TeamName,
+		Events:    events,
+		CreatedAt: sub.CreatedAt,
+	}
+}
+
+func deliveryResponse(d entityHook.Delivery) dto.DeliveryDto {
+	return dto.DeliveryDto{
+		Id:            d.Id,
+		WebhookID:     d.SubscriptionID,
+		EventID:       d.EventID,
+		EventType:     string(d.EventType),
+		Status:        string(d.Status),
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		LastError:     d.LastError,
+		ResponseCode:  d.ResponseCode,
+		CreatedAt:     d.CreatedAt,
+		DeliveredAt:   d.DeliveredAt,
+	}
+}
+
+// maxCodeHostPayload - максимальный размер тела входящего вебхука
+const maxCodeHostPayload = 5 << 20
+
+// GitHubWebhook godoc
+// @Summary Вебхук pull_request от GitHub
+// @Description Проверяет X-Hub-Signature-256 и повторяет действие с PR: opened, closed (с merged - мерж), reopened, converted_to_draft, ready_for_review.
+// @Description Повторная доставка с тем же X-GitHub-Delivery не обрабатывается. Автор PR ищется по логину в связях codeHost/mapUser.
+// @Tags CodeHost
+// @Accept json
+// @Produce json
+// @Param X-GitHub-Event header string true "Тип события, обрабатывается pull_request"
+// @Param X-GitHub-Delivery header string true "Id доставки"
+// @Param X-Hub-Signature-256 header string true "sha256=<HMAC-SHA256 тела с секретом вебхука>"
+// @Success 200 {object} dto.CodeHostWebhookResponse "processed, duplicate или ignored"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный payload"
+// @Failure 401 {object} dto.ErrorResponse "Неверная подпись"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "Переход недопустим из текущего статуса PR"
+// @Failure 422 {object} dto.ErrorResponse "Автор PR не связан с пользователем сервиса"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /webhooks/github [post]
+func (h *Handlers) GitHubWebhook(ctx *gin.Context) {
+	h.codeHostWebhook(ctx, entityHost.ProviderGitHub)
+}
+
+// GitLabWebhook godoc
+// @Summary Вебхук Merge Request Hook от GitLab
+// @Description Сверяет X-Gitlab-Token и повторяет действие с MR: open, merge, close, reopen, update со сменой draft.
+// @Description Повторная доставка с тем же X-Gitlab-Event-UUID не обрабатывается. Автор MR ищется по логину в связях codeHost/mapUser.
+// @Tags CodeHost
+// @Accept json
+// @Produce json
+// @Param X-Gitlab-Event header string true "Тип события, обрабатывается Merge Request Hook"
+// @Param X-Gitlab-Event-UUID header string true "Id доставки"
+// @Param X-Gitlab-Token header string true "Секретный токен вебхука"
+// @Success 200 {object} dto.CodeHostWebhookResponse "processed, duplicate или ignored"
+// @Failure 400 {object} dto.ErrorResponse "Некорректный payload"
+// @Failure 401 {object} dto.ErrorResponse "Неверный токен"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "Переход недопустим из текущего статуса PR"
+// @Failure 422 {object} dto.ErrorResponse "Автор MR не связан с пользователем сервиса"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /webhooks/gitlab [post]
+func (h *Handlers) GitLabWebhook(ctx *gin.Context) {
+	h.codeHostWebhook(ctx, entityHost.ProviderGitLab)
+}
+
+func (h *Handlers) codeHostWebhook(ctx *gin.Context, provider entityHost.Provider) {
+	receiver, ok := h.receivers[provider]
+	if !ok {
+		ctx.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxCodeHostPayload))
+	if err != nil {
+		h.logger.Warn("failed to read code host webhook", zap.String("provider", string(provider)), zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "Empty TeamName",
+				Message: "failed to read request body",
 			},
 		})
-		h.logger.Warn("invalid users deactivation body, empty Team Name")
 		return
 	}
-	if len(body.UserIDs) == 0 {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+	e, err := receiver.Parse(ctx.Request.Header, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, entityHost.ErrIgnoredEvent):
+			ctx.JSON(http.StatusOK, dto.CodeHostWebhookResponse{Status: "ignored"})
+		case errors.Is(err, entityHost.ErrInvalidSignature):
+			h.logger.Warn("code host webhook with invalid signature", zap.String("provider", string(provider)))
+			ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeUnauthorized,
+					Message: "invalid webhook signature",
+				},
+			})
+		default:
+			h.logger.Warn("malformed code host webhook", zap.String("provider", string(provider)), zap.Error(err))
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "malformed webhook payload",
+				},
+			})
+		}
+		return
+	}
+	pr, err := h.svc.HandleCodeHostEvent(ctx, *e)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrDuplicateDelivery):
+			h.logger.Info("code host delivery is already processed", zap.String("provider", string(provider)), zap.String("delivery_id", e.DeliveryID))
+			ctx.JSON(http.StatusOK, dto.CodeHostWebhookResponse{Status: "duplicate"})
+		case errors.Is(err, entityHost.ErrIgnoredEvent):
+			ctx.JSON(http.StatusOK, dto.CodeHostWebhookResponse{Status: "ignored"})
+		case errors.Is(err, application.ErrCodeHostUserNotMapped):
+			h.logger.Warn("code host user is not mapped", zap.String("provider", string(provider)), zap.String("login", e.AuthorLogin))
+			ctx.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotMapped,
+					Message: "author is not mapped to a service user",
+				},
+			})
+		default:
+			h.lifecycleError(ctx, e.PrID(), err)
+		}
+		return
+	}
+	resp := pullRequestResponse(pr).Pr
+	ctx.JSON(http.StatusOK, dto.CodeHostWebhookResponse{Status: "processed", Pr: &resp})
+	h.logger.Info("Code host webhook processed",
+		zap.String("provider", string(provider)),
+		zap.String("action", string(e.Action)),
+		zap.String("pr_id", pr.Id),
+	)
+}
+
+// MapCodeHostUser godoc
+// @Summary Связать логин GitHub/GitLab с пользователем
+// @Description По этой связи вебхуки находят автора PR. Связь логина перезаписывается. Доступно администраторам и тимлиду команды пользователя.
+// @Tags CodeHost
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.CodeHostUserRequest true "Провайдер, логин и user_id"
+// @Success 200 {object} dto.CodeHostUserDto "Связь сохранена"
+// @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /codeHost/mapUser [post]
+func (h *Handlers) MapCodeHostUser(ctx *gin.Context) {
+	var body dto.CodeHostUserRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.UserID == "" {
+		h.logger.Warn("invalid format of request to map code host user", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: dto.ErrorMessage{
 				Code:    CodeBadRequest,
-				Message: "Empty users id",
+				Message: "provider, login and user_id are required",
 			},
 		})
-		h.logger.Warn("invalid users deactivation body, empty users id")
 		return
 	}
+	m, err := h.svc.MapCodeHostUser(ctx, body)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.codeHostUserError(ctx, body, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, codeHostUserResponse(*m))
+	h.logger.Info("Code host user mapped", zap.String("provider", string(m.Provider)), zap.String("login", m.Login), zap.String("user_id", m.UserID))
+}
 
-	err := h.svc.Deactivate(ctx, body.TeamName, body.UserIDs)
+// UnmapCodeHostUser godoc
+// @Summary Удалить связь логина GitHub/GitLab с пользователем
+// @Tags CodeHost
+// @Accept json
+// @Param Authorization header string true "Bearer JWT администратора или тимлида"
+// @Param body body dto.CodeHostUserRequest true "Провайдер и логин"
+// @Success 204 "Связь удалена"
+// @Failure 400 {object} dto.ErrorResponse "Некорректные данные запроса"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "Связь не найдена"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /codeHost/unmapUser [post]
+func (h *Handlers) UnmapCodeHostUser(ctx *gin.Context) {
+	var body dto.CodeHostUserRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Warn("invalid format of request to unmap code host user", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "provider and login are required",
+			},
+		})
+		return
+	}
+	if err := h.svc.UnmapCodeHostUser(ctx, body); err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.codeHostUserError(ctx, body, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+	h.logger.Info("Code host user unmapped", zap.String("provider", body.Provider), zap.String("login", body.Login))
+}
+
+// GetCodeHostUsers godoc
+// @Summary Связи логинов провайдера с пользователями
+// @Tags CodeHost
+// @Produce json
+// @Param Authorization header string true "Bearer JWT с ролью admin"
+// @Param provider query string true "github или gitlab"
+// @Success 200 {object} dto.CodeHostUsersResponse "Связи"
+// @Failure 400 {object} dto.ErrorResponse "Неизвестный провайдер"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /codeHost/users [get]
+func (h *Handlers) GetCodeHostUsers(ctx *gin.Context) {
+	provider := ctx.Query("provider")
+	users, err := h.svc.GetCodeHostUsers(ctx, provider)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		h.codeHostUserError(ctx, dto.CodeHostUserRequest{Provider: provider}, err)
+		return
+	}
+	resp := dto.CodeHostUsersResponse{Users: make([]dto.CodeHostUserDto, 0, len(users))}
+	for _, m := range users {
+		resp.Users = append(resp.Users, codeHostUserResponse(m))
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *Handlers) codeHostUserError(ctx *gin.Context, req dto.CodeHostUserRequest, err error) {
+	switch {
+	case errors.Is(err, application.ErrInvalidCodeHostUser):
+		h.logger.Warn("invalid code host user", zap.String("provider", req.Provider), zap.String("login", req.Login))
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeBadRequest,
+				Message: "provider must be github or gitlab and login is required",
+			},
+		})
+	case errors.Is(err, application.ErrUserNotFound), errors.Is(err, application.ErrCodeHostUserNotMapped):
+		h.logger.Warn("code host user or user not found", zap.String("provider", req.Provider), zap.String("login", req.Login))
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeNotFound,
+				Message: "resource not found",
+			},
+		})
+	default:
+		h.logger.Error("failed to manage code host users", zap.Error(err), zap.String("provider", req.Provider))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	}
+}
+
+func codeHostUserResponse(m entityHost.UserMapping) dto.CodeHostUserDto {
+	return dto.CodeHostUserDto{
+		Provider: string(m.Provider),
+		Login:    m.Login,
+		UserID:   m.UserID,
+	}
+}
+
+// GetSLABreaches godoc
+// @Summary Просрочки ревью
+// @Description Возвращает назначенных ревьюеров открытых PR, которые не оставили ни одного ревью за срок SLA команды автора (см. team/settings), от давних назначений к новым. Без team_name участник видит свою команду, тимлид - свои команды, администратор - все.
+// @Tags team
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param team_name query string false "Команда"
+// @Success 200 {object} dto.SLABreachesResponse "Просрочки"
+// @Failure 401 {object} dto.ErrorResponse "Нет/неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Нет доступа к команде"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /sla/breaches [get]
+func (h *Handlers) GetSLABreaches(ctx *gin.Context) {
+	teamName := ctx.Query("team_name")
+	breaches, err := h.svc.GetSLABreaches(ctx, teamName)
 	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
 		if errors.Is(err, application.ErrTeamNotFound) {
-			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			h.logger.Warn("team not found while GetSLABreaches", zap.String("team_name", teamName))
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
 					Code:    CodeNotFound,
-					Message: "no team found",
+					Message: "resource not found",
 				},
 			})
-			h.logger.Warn("no team found to Deactivate users", zap.String("team_id", body.TeamName))
 			return
 		}
-		h.logger.Error("error while deactivating users", zap.Error(err))
+		h.logger.Error("failed to get SLA breaches", zap.Error(err), zap.String("team_name", teamName))
 		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	resp := dto.SLABreachesResponse{Breaches: make([]dto.SLABreachDto, 0, len(breaches))}
+	for _, b := range breaches {
+		resp.Breaches = append(resp.Breaches, dto.SLABreachDto{
+			PrID:         b.PrID,
+			PrName:       b.PrName,
+			AuthorID:     b.AuthorID,
+			TeamName:     b.TeamName,
+			ReviewerID:   b.ReviewerID,
+			AssignedAt:   b.AssignedAt,
+			Deadline:     b.Deadline(),
+			OverdueHours: int(now.Sub(b.Deadline()).Hours()),
+			RemindedAt:   b.RemindedAt,
+			EscalatedAt:  b.EscalatedAt,
+		})
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func teamSLA(team *entityTeam.Team) *dto.TeamSLA {
+	if !team.SLA.Enabled() {
+		return nil
+	}
+	return &dto.TeamSLA{
+		ReviewHours:   team.SLA.ReviewHours,
+		EscalateHours: team.SLA.EscalateHours,
+		Action:        team.SLA.Action,
+	}
+}
+
+// streamPingInterval - как часто в поток пишется комментарий, чтобы прокси не закрывали простаивающее соединение
+const streamPingInterval = 15 * time.Second
+
+// StreamEvents godoc
+// @Summary Поток событий назначений (Server-Sent Events)
+// @Description Отдаёт события text/event-stream: по умолчанию pr.created, pr.reviewers_changed, pr.merged,
+// @Description user.activated и user.deactivated. id события в потоке - его номер в outbox: после обрыва клиент
+// @Description передаёт его в Last-Event-ID (или last_event_id) и получает пропущенные события из журнала последних событий.
+// @Description Если события уже нет в журнале, первым приходит событие reset - состояние нужно перечитать.
+// @Description Без фильтров участник получает события своей команды, тимлид - своих команд, администратор - всех.
+// @Tags Events
+// @Produce text/event-stream
+// @Param Authorization header string true "Bearer JWT пользователя"
+// @Param team_name query string false "Только события команды"
+// @Param user_id query string false "Только события, где пользователь - автор, ревьюер или сам пользователь"
+// @Param types query string false "Типы событий через запятую"
+// @Param Last-Event-ID header string false "id последнего полученного события"
+// @Success 200 {string} string "Поток событий"
+// @Failure 400 {object} dto.ErrorResponse "Неизвестный тип события"
+// @Failure 401 {object} dto.ErrorResponse "Пользователь не авторизован"
+// @Failure 403 {object} dto.ErrorResponse "Нет прав"
+// @Failure 404 {object} dto.ErrorResponse "Команда или пользователь не найдены"
+// @Failure 500 "Внутренняя ошибка сервера"
+// @Router /events/stream [get]
+func (h *Handlers) StreamEvents(ctx *gin.Context) {
+	req := dto.EventStreamRequest{TeamName: ctx.Query("team_name"), UserID: ctx.Query("user_id")}
+	if types := ctx.Query("types"); types != "" {
+		req.Types = strings.Split(types, ",")
+	}
+	lastID := ctx.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = ctx.Query("last_event_id")
 	}
+	var lastSeq int64
+	if lastID != "" {
+		seq, err := strconv.ParseInt(lastID, 10, 64)
+		if err != nil || seq <= 0 {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: "Last-Event-ID must be a positive event id",
+				},
+			})
+			return
+		}
+		lastSeq = seq
+	}
+	filter, err := h.svc.EventFilter(ctx, req)
+	if err != nil {
+		if h.denied(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, application.ErrInvalidEventType):
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeBadRequest,
+					Message: err.Error(),
+				},
+			})
+		case errors.Is(err, application.ErrTeamNotFound), errors.Is(err, application.ErrUserNotFound):
+			ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: dto.ErrorMessage{
+					Code:    CodeNotFound,
+					Message: "resource not found",
+				},
+			})
+		default:
+			h.logger.Error("failed to build event filter", zap.Error(err))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	backlog, found, events, cancel := h.stream.Subscribe(lastSeq)
+	defer cancel()
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
 	ctx.Status(http.StatusOK)
-	h.logger.Info("deactivated users", zap.String("team_name", body.TeamName))
+	if !found {
+		ctx.Render(-1, sse.Event{Event: "reset", Data: gin.H{"last_event_id": lastID}})
+	}
+	for _, e := range backlog {
+		if filter.Matches(e) {
+			ctx.Render(-1, streamEvent(e))
+		}
+	}
+	ctx.Writer.Flush()
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case e, ok := <-events:
+			if !ok {
+				return false
+			}
+			if filter.Matches(e) {
+				ctx.Render(-1, streamEvent(e))
+			}
+			return true
+		case <-ping.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
+
+func streamEvent(e entityEvent.Event) sse.Event {
+	return sse.Event{Id: strconv.FormatInt(e.Seq, 10), Event: string(e.Type), Data: e}
 }
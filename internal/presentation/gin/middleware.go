@@ -1,16 +1,18 @@
 package rest
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/JanArsMAI/PullRequestService/internal/application"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
 	"github.com/JanArsMAI/PullRequestService/internal/presentation/gin/dto"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-const (
-	AdminToken = "admin"
-)
+const bearerPrefix = "Bearer "
 
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -25,28 +27,23 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
-// middleware для проверки, что токен - админский
-// здесь допустим небольшое упрощение и будем сравнивать с константным значением токена
-func (h *Handlers) AdminMiddleware() gin.HandlerFunc {
+// Require пропускает запрос, если у вызывающего есть право perm хотя бы в одной команде.
+// Право в конкретной команде проверяет PolicyService
+func (h *Handlers) Require(perm entityAuth.Permission) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		//здесь должна быть имитация похода по gRPC на другой микросервис авторизации для проверки токена
-		authHeader := ctx.GetHeader("Authorization")
-		if authHeader == "" {
-			h.logger.Warn("AdminMiddleware: missing Authorization header")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: dto.ErrorMessage{
-					Code:    CodeNotFound,
-					Message: "resource not found",
-				},
-			})
+		p, ok := h.authenticate(ctx)
+		if !ok {
 			return
 		}
-		if authHeader != AdminToken {
-			h.logger.Warn("AdminMiddleware: invalid admin token", zap.String("header", authHeader))
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+		if !p.CanAny(perm) {
+			h.logger.Warn("caller has no permission",
+				zap.String("subject", p.Subject),
+				zap.String("permission", string(perm)),
+			)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
 				Error: dto.ErrorMessage{
-					Code:    CodeNotFound,
-					Message: "resource not found",
+					Code:    CodeForbidden,
+					Message: "permission " + string(perm) + " is required",
 				},
 			})
 			return
@@ -55,22 +52,78 @@ func (h *Handlers) AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
-func (h *Handlers) UserMiddleware() gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		//аналогично, идём на gRPC, для проверки токена, но тут мы этого не делаем
-		authHeader := ctx.GetHeader("Authorization")
-		if authHeader == "" {
-			h.logger.Warn("UserMiddleware: missing Authorization header")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: dto.ErrorMessage{
-					Code:    CodeNotFound,
-					Message: "resource not found",
-				},
-			})
-			return
+// authenticate проверяет Bearer токен и кладёт вызывающего в контекст запроса,
+// при ошибке отвечает 401 (или 500, если не удалось загрузить роли) и прерывает обработку
+func (h *Handlers) authenticate(ctx *gin.Context) (entityAuth.Principal, bool) {
+	token, ok := bearerToken(ctx.GetHeader("Authorization"))
+	if !ok {
+		h.logger.Warn("missing bearer token", zap.String("path", ctx.FullPath()))
+		h.unauthorized(ctx, CodeUnauthorized, "bearer token is required")
+		return entityAuth.Principal{}, false
+	}
+	p, err := h.verifier.Verify(ctx, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, entityAuth.ErrTokenExpired):
+			h.logger.Warn("token rejected", zap.String("path", ctx.FullPath()), zap.Error(err))
+			h.unauthorized(ctx, CodeTokenExpired, "token is expired")
+		case errors.Is(err, entityAuth.ErrInvalidToken):
+			h.logger.Warn("token rejected", zap.String("path", ctx.FullPath()), zap.Error(err))
+			h.unauthorized(ctx, CodeUnauthorized, "invalid token")
+		default:
+			h.logger.Error("failed to authenticate caller", zap.String("path", ctx.FullPath()), zap.Error(err))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
 		}
-		userID := authHeader
-		ctx.Set("User_Id", userID)
-		ctx.Next()
+		return entityAuth.Principal{}, false
 	}
+	setPrincipal(ctx, p)
+	return p, true
+}
+
+func (h *Handlers) unauthorized(ctx *gin.Context, code, message string) {
+	ctx.Header("WWW-Authenticate", `Bearer realm="pull-request-service"`)
+	ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+		Error: dto.ErrorMessage{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+// denied отвечает 401/403, если сервис отказал вызывающему по политике доступа
+func (h *Handlers) denied(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, application.ErrUnauthenticated):
+		h.unauthorized(ctx, CodeUnauthorized, "bearer token is required")
+	case errors.Is(err, application.ErrForbidden):
+		h.logger.Warn("caller is not allowed", zap.String("path", ctx.FullPath()), zap.Error(err))
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Error: dto.ErrorMessage{
+				Code:    CodeForbidden,
+				Message: "not allowed in this team",
+			},
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+// bearerToken достаёт токен из заголовка Authorization вида "Bearer <token>"
+func bearerToken(header string) (string, bool) {
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(header[len(bearerPrefix):])
+	return token, token != ""
+}
+
+// setPrincipal кладёт вызывающего в контекст запроса, откуда его берёт application слой
+func setPrincipal(ctx *gin.Context, p entityAuth.Principal) {
+	ctx.Request = ctx.Request.WithContext(entityAuth.WithPrincipal(ctx.Request.Context(), p))
+}
+
+// principal возвращает вызывающего, которого положил в контекст middleware
+func principal(ctx *gin.Context) (entityAuth.Principal, bool) {
+	return entityAuth.FromContext(ctx.Request.Context())
 }
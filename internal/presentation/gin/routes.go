@@ -2,6 +2,8 @@ package rest
 
 import (
 	_ "github.com/JanArsMAI/PullRequestService/docs"
+	entityAuth "github.com/JanArsMAI/PullRequestService/internal/domain/auth"
+	entityHost "github.com/JanArsMAI/PullRequestService/internal/domain/codehost"
 	"github.com/JanArsMAI/PullRequestService/internal/domain/interfaces"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -9,34 +11,94 @@ import (
 	"go.uber.org/zap"
 )
 
-func InitRoutes(r *gin.Engine, svc interfaces.PrService, logger *zap.Logger) {
+func InitRoutes(r *gin.Engine, svc interfaces.PrService, verifier interfaces.TokenVerifier, receivers map[entityHost.Provider]interfaces.CodeHostReceiver, stream interfaces.EventStream, logger *zap.Logger) {
+	// вызывающий кладётся middleware в контекст запроса, а в сервис передаётся *gin.Context
+	r.ContextWithFallback = true
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	h := NewHandlers(svc, logger)
+	h := NewHandlers(svc, verifier, receivers, stream, logger)
 	apiTeam := r.Group("team")
 	{
-		apiTeam.POST("/add", h.AddTeam)
-		apiTeam.GET("/get", h.UserMiddleware(), h.GetTeam)
+		// team/add может перевести существующих пользователей в новую команду, изменить их данные
+		// и переназначить их ревью, поэтому создавать команды может только администратор (см. PolicyService.AddTeam)
+		apiTeam.POST("/add", h.Require(entityAuth.PermTeamManage), h.AddTeam)
+		apiTeam.GET("/get", h.Require(entityAuth.PermTeamRead), h.GetTeam)
+		apiTeam.POST("/settings", h.Require(entityAuth.PermTeamManage), h.UpdateTeamSettings)
 	}
 
 	apiUsers := r.Group("users")
 	{
-		apiUsers.POST("/setIsActive", h.AdminMiddleware(), h.SetIsActive)
-		apiUsers.GET("/getReview", h.UserMiddleware(), h.GetUsersPr)
+		apiUsers.POST("/setIsActive", h.Require(entityAuth.PermUserManage), h.SetIsActive)
+		apiUsers.GET("/getReview", h.Require(entityAuth.PermUserRead), h.GetUsersPr)
+		apiUsers.POST("/setMaxOpenReviews", h.Require(entityAuth.PermUserManage), h.SetMaxOpenReviews)
+		apiUsers.POST("/setEmail", h.Require(entityAuth.PermUserManage), h.SetUserEmail)
+		apiUsers.POST("/setTags", h.Require(entityAuth.PermUserManage), h.SetUserTags)
+		apiUsers.GET("/getTags", h.Require(entityAuth.PermUserRead), h.GetUserTags)
+		apiUsers.POST("/addAvailability", h.Require(entityAuth.PermUserManage), h.AddAvailability)
+		apiUsers.GET("/getAvailability", h.Require(entityAuth.PermUserRead), h.GetAvailability)
+		apiUsers.POST("/deleteAvailability", h.Require(entityAuth.PermUserManage), h.DeleteAvailability)
 	}
 
 	apiPullRequests := r.Group("pullRequest")
 	{
-		apiPullRequests.POST("/create", h.AdminMiddleware(), h.CreatePR)
-		apiPullRequests.POST("/merge", h.UserMiddleware(), h.Merge)
-		apiPullRequests.POST("/reassign", h.AdminMiddleware(), h.Reasign)
+		apiPullRequests.POST("/create", h.Require(entityAuth.PermPrCreate), h.CreatePR)
+		apiPullRequests.GET("/get", h.Require(entityAuth.PermUserRead), h.GetPullRequest)
+		apiPullRequests.GET("/history", h.Require(entityAuth.PermUserRead), h.GetPullRequestHistory)
+		apiPullRequests.POST("/merge", h.Require(entityAuth.PermPrMerge), h.Merge)
+		apiPullRequests.POST("/review", h.Require(entityAuth.PermPrReview), h.SubmitReview)
+		apiPullRequests.POST("/reassign", h.Require(entityAuth.PermPrReassign), h.Reasign)
+		apiPullRequests.POST("/ready", h.Require(entityAuth.PermPrManage), h.Ready)
+		apiPullRequests.POST("/close", h.Require(entityAuth.PermPrManage), h.ClosePR)
+		apiPullRequests.POST("/reopen", h.Require(entityAuth.PermPrManage), h.Reopen)
+	}
+	apiRoles := r.Group("roles")
+	{
+		apiRoles.POST("/grant", h.Require(entityAuth.PermRolesManage), h.GrantRole)
+		apiRoles.POST("/revoke", h.Require(entityAuth.PermRolesManage), h.RevokeRole)
+		apiRoles.GET("/get", h.Require(entityAuth.PermUserRead), h.GetUserRoles)
+	}
+	apiKeys := r.Group("apiKeys")
+	{
+		apiKeys.POST("/issue", h.Require(entityAuth.PermAPIKeysManage), h.IssueAPIKey)
+		apiKeys.GET("/list", h.Require(entityAuth.PermAPIKeysManage), h.GetAPIKeys)
+		apiKeys.POST("/revoke", h.Require(entityAuth.PermAPIKeysManage), h.RevokeAPIKey)
+	}
+	apiWebhooks := r.Group("webhooks")
+	{
+		apiWebhooks.POST("/add", h.Require(entityAuth.PermWebhooksManage), h.AddWebhook)
+		apiWebhooks.GET("/list", h.Require(entityAuth.PermWebhooksManage), h.GetWebhooks)
+		apiWebhooks.POST("/delete", h.Require(entityAuth.PermWebhooksManage), h.DeleteWebhook)
+		apiWebhooks.GET("/deliveries", h.Require(entityAuth.PermWebhooksManage), h.GetDeliveries)
+		apiWebhooks.POST("/redeliver", h.Require(entityAuth.PermWebhooksManage), h.Redeliver)
+		// входящие вебхуки проверяются подписью провайдера, а не токеном
+		apiWebhooks.POST("/github", h.GitHubWebhook)
+		apiWebhooks.POST("/gitlab", h.GitLabWebhook)
+	}
+	apiCodeHost := r.Group("codeHost")
+	{
+		apiCodeHost.POST("/mapUser", h.Require(entityAuth.PermUserManage), h.MapCodeHostUser)
+		apiCodeHost.POST("/unmapUser", h.Require(entityAuth.PermUserManage), h.UnmapCodeHostUser)
+		apiCodeHost.GET("/users", h.Require(entityAuth.PermUserManage), h.GetCodeHostUsers)
+	}
+	apiEvents := r.Group("events")
+	{
+		apiEvents.GET("/stream", h.Require(entityAuth.PermTeamRead), h.StreamEvents)
+	}
+	apiSLA := r.Group("sla")
+	{
+		apiSLA.GET("/breaches", h.Require(entityAuth.PermTeamRead), h.GetSLABreaches)
+	}
+	apiCodeOwners := r.Group("codeowners")
+	{
+		apiCodeOwners.POST("/upload", h.Require(entityAuth.PermCodeOwnersManage), h.UploadCodeOwners)
+		apiCodeOwners.POST("/test", h.Require(entityAuth.PermCodeOwnersManage), h.TestCodeOwners)
 	}
 	apiStats := r.Group("stats")
 	{
-		apiStats.GET("get", h.AdminMiddleware(), h.GetStats)
+		apiStats.GET("get", h.Require(entityAuth.PermStatsRead), h.GetStats)
 	}
 	apiDeactivate := r.Group("deactivate")
 	{
-		apiDeactivate.POST("/use", h.AdminMiddleware(), h.Deactivation)
+		apiDeactivate.POST("/use", h.Require(entityAuth.PermUserManage), h.Deactivation)
 	}
 	r.Use(CORSMiddleware())
 }
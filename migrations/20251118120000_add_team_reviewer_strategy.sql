@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+ADD COLUMN reviewer_strategy VARCHAR(20) NOT NULL DEFAULT 'random'
+CHECK (reviewer_strategy IN ('random','round_robin','least_loaded','weighted'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams
+DROP COLUMN reviewer_strategy;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+ADD COLUMN required_reviewers INT NOT NULL DEFAULT 2
+CHECK (required_reviewers >= 1);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams
+DROP COLUMN required_reviewers;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pull_request_reviewers
+ADD COLUMN pool_team_id INT REFERENCES teams(id) ON DELETE SET NULL;
+
+UPDATE pull_request_reviewers r
+SET pool_team_id = u.team_id
+FROM pull_requests pr
+JOIN users u ON u.user_id = pr.author_id
+WHERE pr.pull_request_id = r.pull_request_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_request_reviewers
+DROP COLUMN pool_team_id;
+-- +goose StatementEnd
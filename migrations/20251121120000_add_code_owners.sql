@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS code_owner_rules (
+    id SERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    pattern VARCHAR(500) NOT NULL,
+    UNIQUE (team_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS code_owner_rule_owners (
+    rule_id INT NOT NULL REFERENCES code_owner_rules(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    owner_kind VARCHAR(10) NOT NULL CHECK (owner_kind IN ('user','team')),
+    owner_name VARCHAR(100) NOT NULL,
+    PRIMARY KEY (rule_id, position)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS code_owner_rule_owners;
+DROP TABLE IF EXISTS code_owner_rules;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_availability (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason VARCHAR(200) NOT NULL DEFAULT '',
+    started BOOLEAN NOT NULL DEFAULT FALSE,
+    ended BOOLEAN NOT NULL DEFAULT FALSE,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_user_availability_user ON user_availability(user_id, starts_at, ends_at);
+CREATE INDEX idx_user_availability_pending ON user_availability(starts_at, ends_at) WHERE NOT ended;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_availability;
+-- +goose StatementEnd
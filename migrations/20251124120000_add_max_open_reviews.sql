@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+ADD COLUMN max_open_reviews INT CHECK (max_open_reviews > 0);
+
+ALTER TABLE pull_requests
+ADD COLUMN need_more_reason VARCHAR(30);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests
+DROP COLUMN need_more_reason;
+
+ALTER TABLE users
+DROP COLUMN max_open_reviews;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS pull_request_reviews (
+    id SERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(50) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    verdict VARCHAR(20) NOT NULL CHECK (verdict IN ('APPROVED','CHANGES_REQUESTED','COMMENTED')),
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pr_reviews_pr ON pull_request_reviews(pull_request_id, created_at);
+
+ALTER TABLE teams
+ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 1
+CHECK (required_approvals >= 1);
+
+ALTER TABLE pull_requests
+ADD COLUMN IF NOT EXISTS merged_by VARCHAR(50),
+ADD COLUMN IF NOT EXISTS review_bypassed BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests
+DROP COLUMN IF EXISTS review_bypassed,
+DROP COLUMN IF EXISTS merged_by;
+
+ALTER TABLE teams
+DROP COLUMN IF EXISTS required_approvals;
+
+DROP TABLE IF EXISTS pull_request_reviews;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS code_host_users (
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('github','gitlab')),
+    login VARCHAR(255) NOT NULL,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, login)
+);
+
+CREATE TABLE IF NOT EXISTS code_host_deliveries (
+    provider VARCHAR(20) NOT NULL,
+    delivery_id VARCHAR(100) NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, delivery_id)
+);
+
+CREATE INDEX idx_code_host_users_user ON code_host_users(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS code_host_deliveries;
+DROP TABLE IF EXISTS code_host_users;
+-- +goose StatementEnd
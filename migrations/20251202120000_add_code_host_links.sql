@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS code_host_links (
+    pull_request_id VARCHAR(50) PRIMARY KEY REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('github','gitlab')),
+    repo_id BIGINT NOT NULL,
+    repo VARCHAR(255) NOT NULL DEFAULT '',
+    number INT NOT NULL,
+    sync_status VARCHAR(10) NOT NULL DEFAULT 'PENDING' CHECK (sync_status IN ('PENDING','SYNCED','FAILED')),
+    version INT NOT NULL DEFAULT 1,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    synced_reviewers TEXT[] NOT NULL DEFAULT '{}',
+    synced_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_code_host_links_due ON code_host_links(next_attempt_at) WHERE sync_status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS code_host_links;
+-- +goose StatementEnd
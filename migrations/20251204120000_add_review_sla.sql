@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+ADD COLUMN sla_review_hours INT NOT NULL DEFAULT 0 CHECK (sla_review_hours >= 0),
+ADD COLUMN sla_escalate_hours INT NOT NULL DEFAULT 0 CHECK (sla_escalate_hours >= 0),
+ADD COLUMN sla_action VARCHAR(20) NOT NULL DEFAULT 'escalate' CHECK (sla_action IN ('escalate','reassign'));
+
+CREATE TABLE IF NOT EXISTS review_sla_notices (
+    pull_request_id VARCHAR(50) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reminded_at TIMESTAMP WITH TIME ZONE,
+    escalated_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (pull_request_id, reviewer_id, assigned_at)
+);
+
+CREATE INDEX idx_pr_reviews_reviewer ON pull_request_reviews(pull_request_id, reviewer_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pr_reviews_reviewer;
+
+DROP TABLE IF EXISTS review_sla_notices;
+
+ALTER TABLE teams
+DROP COLUMN sla_action,
+DROP COLUMN sla_escalate_hours,
+DROP COLUMN sla_review_hours;
+-- +goose StatementEnd
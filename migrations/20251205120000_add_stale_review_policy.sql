@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+ADD COLUMN stale_review_hours INT NOT NULL DEFAULT 0 CHECK (stale_review_hours >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams
+DROP COLUMN stale_review_hours;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reviewer_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(50) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('assigned','removed','replaced')),
+    replaced_by VARCHAR(50) REFERENCES users(user_id) ON DELETE SET NULL,
+    reason VARCHAR(30) NOT NULL DEFAULT '',
+    actor VARCHAR(255) NOT NULL DEFAULT 'system',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_reviewer_assignments_pr ON reviewer_assignments(pull_request_id, id);
+
+-- история до миграции неизвестна, текущие ревьюеры записываются назначенными в момент assigned_at
+INSERT INTO reviewer_assignments (pull_request_id, reviewer_id, action, actor, created_at)
+SELECT pull_request_id, reviewer_id, 'assigned', 'system', COALESCE(assigned_at, NOW())
+FROM pull_request_reviewers
+ORDER BY assigned_at, pull_request_id, reviewer_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reviewer_assignments;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- история назначений - журнал аудита: удаление PR или пользователя не должно стирать её записи
+ALTER TABLE reviewer_assignments
+    DROP CONSTRAINT reviewer_assignments_pull_request_id_fkey,
+    DROP CONSTRAINT reviewer_assignments_reviewer_id_fkey,
+    ADD CONSTRAINT reviewer_assignments_pull_request_id_fkey FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id),
+    ADD CONSTRAINT reviewer_assignments_reviewer_id_fkey FOREIGN KEY (reviewer_id) REFERENCES users(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reviewer_assignments
+    DROP CONSTRAINT reviewer_assignments_pull_request_id_fkey,
+    DROP CONSTRAINT reviewer_assignments_reviewer_id_fkey,
+    ADD CONSTRAINT reviewer_assignments_pull_request_id_fkey FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    ADD CONSTRAINT reviewer_assignments_reviewer_id_fkey FOREIGN KEY (reviewer_id) REFERENCES users(user_id) ON DELETE CASCADE;
+-- +goose StatementEnd
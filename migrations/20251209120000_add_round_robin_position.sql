@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- последний участник, выбранный стратегией round_robin, пусто - очередь начинается сначала
+ALTER TABLE teams ADD COLUMN round_robin_last VARCHAR(50) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP COLUMN IF EXISTS round_robin_last;
+-- +goose StatementEnd
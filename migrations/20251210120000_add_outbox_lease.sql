@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- до какого момента пачку событий публикует забравшая её реплика
+ALTER TABLE outbox ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox DROP COLUMN IF EXISTS locked_until;
+-- +goose StatementEnd